@@ -0,0 +1,147 @@
+// Bai-Perron(2003) 多断点估计(纯结构突变: 全部系数在断点处变化)
+// 1. 各区间 [i, j] 的 OLS 残差平方和由递归残差累加得到: SSR(i, j) = SSR(i, j-1) + w_j²
+// 2. 动态规划: SSR_m(j) = min_t [SSR_{m-1}(t-1) + SSR(t, j)]，得到 0..maxBreaks 个断点的全局最优划分
+// 3. 断点个数按 BIC(Yao 1988) 选择: ln(SSR_m/n) + ((m+1)k + m)·ln(n)/n
+package ols
+
+import (
+	"fmt"
+	"math"
+	"ofeisInfra/infra/errorx"
+	"ofeisInfra/infra/errorx/errCode"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+type BaiPerronResult struct {
+	NBreaks   int                // BIC 选出的断点个数
+	Breaks    []int              // 选出的断点(各新区间的起始下标)
+	BreaksByM [][]int            // m = 0..maxBreaks 时的最优断点，无可行划分时为 nil
+	SSR       []float64          // m = 0..maxBreaks 时的最小 SSR，无可行划分时为 +Inf
+	BIC       []float64          // m = 0..maxBreaks 时的 BIC
+	MinSeg    int                // 最小区间长度
+	Segments  []MultiLinearModel // 选出划分下各区间的 OLS 结果
+}
+
+// Bai-Perron 多断点估计
+// input: matX 自带常数列; maxBreaks 最大断点数; trim 最小区间长度占样本比例(常用 0.15)，不小于 k+1
+// 内存 O(n²)，适用于数千以内的样本
+func BaiPerron(matX *mat.Dense, matY *mat.VecDense, maxBreaks int, trim float64) (BaiPerronResult, error) {
+	n, k := matX.Dims()
+	if matY.Len() != n {
+		return BaiPerronResult{}, errorx.New(errCode.INVALID_VALUE, "数据长度不匹配")
+	}
+	if maxBreaks < 0 {
+		return BaiPerronResult{}, errorx.New(errCode.INVALID_VALUE, "maxBreaks 不能为负")
+	}
+	if !(trim > 0 && trim < 0.5) {
+		return BaiPerronResult{}, errorx.New(errCode.INVALID_VALUE, "trim 必须在 (0, 0.5) 之间")
+	}
+	h := max(int(math.Floor(trim*float64(n))), k+1)
+	if (maxBreaks+1)*h > n {
+		return BaiPerronResult{}, errorx.New(errCode.INVALID_VALUE, fmt.Sprintf("样本量 %d 不足以划分 %d 个长度 >= %d 的区间", n, maxBreaks+1, h))
+	}
+
+	// 1. 区间 SSR: seg[i][j-i] = SSR(i, j)，仅保留长度 >= h 的区间
+	// 以 i 起的剩余样本设计矩阵奇异时跳过该起点(seg[i] 为 nil，SSR 视为 +Inf)
+	seg := make([][]float64, n)
+	for i := 0; i+h <= n; i++ {
+		w, first, initSSR, err := recursiveResiduals(matX, matY, i, n)
+		if err != nil {
+			continue
+		}
+		row := make([]float64, n-i)
+		for j := range row {
+			row[j] = math.Inf(1)
+		}
+		cum := initSSR
+		if first-1 >= i {
+			row[first-1-i] = cum
+		}
+		for idx, v := range w {
+			cum += v * v
+			row[first+idx-i] = cum
+		}
+		for j := 0; j < h-1 && j < len(row); j++ {
+			row[j] = math.Inf(1)
+		}
+		seg[i] = row
+	}
+	ssr := func(i, j int) float64 {
+		if seg[i] == nil {
+			return math.Inf(1)
+		}
+		return seg[i][j-i]
+	}
+
+	// 2. 动态规划，opt[m][j] 为 [0, j] 划分为 m+1 段的最小 SSR，last[m][j] 为最后一段起点
+	opt := make([][]float64, maxBreaks+1)
+	last := make([][]int, maxBreaks+1)
+	for m := range opt {
+		opt[m] = make([]float64, n)
+		last[m] = make([]int, n)
+		for j := range opt[m] {
+			opt[m][j] = math.Inf(1)
+		}
+	}
+	for j := h - 1; j < n; j++ {
+		opt[0][j] = ssr(0, j)
+	}
+	for m := 1; m <= maxBreaks; m++ {
+		for j := (m+1)*h - 1; j < n; j++ {
+			for t := m * h; t <= j-h+1; t++ {
+				v := opt[m-1][t-1] + ssr(t, j)
+				if v < opt[m][j] {
+					opt[m][j] = v
+					last[m][j] = t
+				}
+			}
+		}
+	}
+
+	// 3. 回溯与 BIC
+	result := BaiPerronResult{
+		BreaksByM: make([][]int, maxBreaks+1),
+		SSR:       make([]float64, maxBreaks+1),
+		BIC:       make([]float64, maxBreaks+1),
+		MinSeg:    h,
+	}
+	nf := float64(n)
+	bestBIC := math.Inf(1)
+	for m := 0; m <= maxBreaks; m++ {
+		if math.IsInf(opt[m][n-1], 1) {
+			// 无可行划分
+			result.SSR[m] = math.Inf(1)
+			result.BIC[m] = math.Inf(1)
+			continue
+		}
+		breaks := make([]int, m)
+		j := n - 1
+		for mm := m; mm >= 1; mm-- {
+			t := last[mm][j]
+			breaks[mm-1] = t
+			j = t - 1
+		}
+		result.BreaksByM[m] = breaks
+		result.SSR[m] = opt[m][n-1]
+		result.BIC[m] = math.Log(result.SSR[m]/nf) + float64((m+1)*k+m)*math.Log(nf)/nf
+		if result.BIC[m] < bestBIC {
+			bestBIC = result.BIC[m]
+			result.NBreaks = m
+		}
+	}
+	if math.IsInf(bestBIC, 1) {
+		return BaiPerronResult{}, errorx.New(errCode.INVALID_VALUE, "X'X 不可逆，请检查自变量是否共线")
+	}
+	result.Breaks = result.BreaksByM[result.NBreaks]
+
+	bounds := append(append([]int{0}, result.Breaks...), n)
+	for s := 0; s+1 < len(bounds); s++ {
+		model, err := MultiRegressionMat(rowSlice(matX, bounds[s], bounds[s+1]), matY.SliceVec(bounds[s], bounds[s+1]).(*mat.VecDense))
+		if err != nil {
+			return BaiPerronResult{}, err
+		}
+		result.Segments = append(result.Segments, model)
+	}
+	return result, nil
+}
@@ -0,0 +1,84 @@
+package ols
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// 在 t=50、t=100 处系数从 (1, 0.5) 变为 (4, -0.5)、再变为 (0, 1.5)
+func TestBaiPerronRecoversTwoBreaks(t *testing.T) {
+	const n = 150
+	rng := rand.New(rand.NewSource(39))
+	matX := mat.NewDense(n, 2, nil)
+	matY := mat.NewVecDense(n, nil)
+	coefs := [][]float64{{1, 0.5}, {4, -0.5}, {0, 1.5}}
+	for i := 0; i < n; i++ {
+		x := rng.NormFloat64()
+		c := coefs[i/50]
+		matX.Set(i, 0, 1)
+		matX.Set(i, 1, x)
+		matY.SetVec(i, c[0]+c[1]*x+0.5*rng.NormFloat64())
+	}
+	res, err := BaiPerron(matX, matY, 3, 0.15)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.NBreaks != 2 || len(res.Breaks) != 2 || res.Breaks[0] != 50 || res.Breaks[1] != 100 {
+		t.Fatalf("breaks %v, BIC %v", res.Breaks, res.BIC)
+	}
+	for s, seg := range res.Segments {
+		if math.Abs(seg.Coeffs[0]-coefs[s][0]) > 0.3 || math.Abs(seg.Coeffs[1]-coefs[s][1]) > 0.3 {
+			t.Errorf("segment %d coeffs %v, want %v", s, seg.Coeffs, coefs[s])
+		}
+	}
+	// 动态规划的 SSR 等于选出划分下各段 OLS 的 SSR 之和
+	total := 0.0
+	for _, seg := range res.Segments {
+		total += sumSq(seg.Resids)
+	}
+	if math.Abs(res.SSR[2]-total) > 1e-8*total {
+		t.Errorf("SSR %v, segments %v", res.SSR[2], total)
+	}
+	// SSR 随断点数单调不增
+	for m := 1; m < len(res.SSR); m++ {
+		if res.SSR[m] > res.SSR[m-1]+1e-9 {
+			t.Errorf("SSR increases at m=%d: %v", m, res.SSR)
+		}
+	}
+}
+
+// t >= 70 后自变量恒为 0，以这些位置为起点的区间设计矩阵奇异，应跳过而非报错
+func TestBaiPerronSkipsSingularTail(t *testing.T) {
+	const n = 100
+	rng := rand.New(rand.NewSource(40))
+	matX := mat.NewDense(n, 2, nil)
+	matY := mat.NewVecDense(n, nil)
+	for i := 0; i < n; i++ {
+		x := 0.0
+		if i < 70 {
+			x = float64(i) / 10
+		}
+		mu := 2.0
+		if i >= 35 {
+			mu = 8
+		}
+		matX.Set(i, 0, 1)
+		matX.Set(i, 1, x)
+		matY.SetVec(i, mu+x+0.3*rng.NormFloat64())
+	}
+	res, err := BaiPerron(matX, matY, 2, 0.15)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.NBreaks != 1 || res.Breaks[0] != 35 {
+		t.Errorf("breaks %v, BIC %v", res.Breaks, res.BIC)
+	}
+	for _, b := range res.BreaksByM[2] {
+		if b >= 70 {
+			t.Errorf("segment starts in singular tail: %v", res.BreaksByM[2])
+		}
+	}
+}
@@ -0,0 +1,197 @@
+package ols
+
+import (
+	"fmt"
+	"math"
+	"ofeisInfra/infra/errorx"
+	"ofeisInfra/infra/errorx/errCode"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// 文件: elasticnet_regr.go
+// 说明: 与 MultiRegressionLasso 同一套约定的 Ridge 与 Elastic Net（线性 / 逻辑）。
+//       - α 缩放与 LASSO 相同: 内部使用 α/n，X 标准化（截距列不标准化），系数反标准化回原尺度。
+//       - Elastic Net 线性目标: (1/(2n))||y - Xβ||^2 + α'·l1Ratio·||β||_1 + (α'(1-l1Ratio)/2)||β||^2，α' = α/n
+//       - l1Ratio = 1 的系数与 MultiRegressionLasso 完全一致(逻辑回归的残差与似然指标改为基于概率 p̂)；l1Ratio = 0 的线性目标等价于 sklearn Ridge 的 ||y - Xβ||^2 + α||β||^2。
+//       - Ridge 线性为闭式解，逻辑为 Newton 迭代；可选择是否惩罚截距（sklearn 默认不惩罚）。
+
+// MultiRegressionElasticNet: Elastic Net 回归，返回 MultiLinearModel 结构。
+// l1Ratio: L1 占比 [0,1]，1 为 LASSO，0 为 Ridge（坐标下降求解；Ridge 建议用 MultiRegressionRidge 闭式解）
+// 逻辑回归使用默认求解器 LOGIT_IRLS_CD
+func MultiRegressionElasticNet(matX *mat.Dense, matY *mat.VecDense, alpha, l1Ratio float64, useLogistic bool, withIntercept bool) (MultiLinearModel, error) {
+	return MultiRegressionElasticNetSolver(matX, matY, alpha, l1Ratio, useLogistic, withIntercept, LOGIT_IRLS_CD)
+}
+
+// MultiRegressionElasticNetSolver: 指定逻辑回归求解器的 Elastic Net，线性回归忽略 solver
+// LOGIT_IRLS_CD / LOGIT_FISTA 在列主序数据上求解，并沿用路径求解器的强规则筛选；LOGIT_ISTA 为原实现
+func MultiRegressionElasticNetSolver(matX *mat.Dense, matY *mat.VecDense, alpha, l1Ratio float64, useLogistic bool, withIntercept bool, solver LogitSolver) (MultiLinearModel, error) {
+	n, _ := matX.Dims()
+	if matY.Len() != n {
+		return MultiLinearModel{}, errorx.New(errCode.INVALID_VALUE, "Y 长度与 X 行数不匹配")
+	}
+	if alpha < 0 {
+		return MultiLinearModel{}, errorx.New(errCode.INVALID_VALUE, "alpha 不能为负")
+	}
+	if l1Ratio < 0 || l1Ratio > 1 {
+		return MultiLinearModel{}, errorx.New(errCode.INVALID_VALUE, fmt.Sprintf("l1Ratio=%v 须在 [0,1] 内", l1Ratio))
+	}
+
+	// sklearn 对齐：alpha 按样本数缩放
+	alphaEff := alpha / float64(n)
+
+	var beta []float64
+	var err error
+	const tol = 1e-5
+	const maxIter = 10000
+	switch {
+	case !useLogistic:
+		beta, err = elasticNetLinearCD(matX, matY, alphaEff, l1Ratio, withIntercept, tol, maxIter)
+	case solver == LOGIT_ISTA:
+		beta, err = elasticNetLogisticISTA(matX, matY, alphaEff, l1Ratio, withIntercept, tol, maxIter)
+	case solver == LOGIT_IRLS_CD || solver == LOGIT_FISTA:
+		d := newEnetData(matX, matY, nil, withIntercept, true)
+		opts := PathOptions{L1Ratio: l1Ratio, UseLogistic: true, WithIntercept: withIntercept, LogitSolver: solver, Tol: tol, MaxIter: maxIter}
+		coeffs, _ := d.path([]float64{alphaEff}, opts)
+		beta = coeffs[0]
+	default:
+		return MultiLinearModel{}, errorx.New(errCode.INVALID_VALUE, "未知的逻辑回归求解器")
+	}
+	if err != nil {
+		return MultiLinearModel{}, err
+	}
+	return penalizedModel(matX, matY, beta, useLogistic, withIntercept), nil
+}
+
+// MultiRegressionRidge: Ridge 回归，返回 MultiLinearModel 结构。
+// 线性: 标准化空间闭式解 β = (Z'Z + αΛ)^{-1} Z'y，Λ 为惩罚指示对角阵
+// 逻辑: 目标 (1/n)Σ[log(1+e^{zβ}) - y zβ] + (α/(2n))β'Λβ，Newton 迭代 + 步长减半
+// penalizeIntercept: 截距是否参与惩罚（仅 withIntercept 时有效）
+func MultiRegressionRidge(matX *mat.Dense, matY *mat.VecDense, alpha float64, useLogistic bool, withIntercept bool, penalizeIntercept bool) (MultiLinearModel, error) {
+	n, _ := matX.Dims()
+	if matY.Len() != n {
+		return MultiLinearModel{}, errorx.New(errCode.INVALID_VALUE, "Y 长度与 X 行数不匹配")
+	}
+	if alpha < 0 {
+		return MultiLinearModel{}, errorx.New(errCode.INVALID_VALUE, "alpha 不能为负")
+	}
+
+	Z := mat.DenseCopyOf(matX)
+	if withIntercept {
+		Z = addInterceptColumnDense(Z)
+	}
+	means, stds := standardizeExceptIntercept(Z, withIntercept)
+	_, p := Z.Dims()
+	penalty := make([]float64, p)
+	for j := range penalty {
+		penalty[j] = alpha
+	}
+	if withIntercept && !penalizeIntercept {
+		penalty[0] = 0
+	}
+
+	var beta []float64
+	var err error
+	if !useLogistic {
+		beta, err = ridgeLinear(Z, matY, penalty)
+	} else {
+		beta, err = ridgeLogisticNewton(Z, matY, penalty, 1e-8, 100)
+	}
+	if err != nil {
+		return MultiLinearModel{}, err
+	}
+	beta = destandardizeBeta(beta, means, stds, withIntercept)
+	return penalizedModel(matX, matY, beta, useLogistic, withIntercept), nil
+}
+
+// ridgeLinear: (Z'Z + diag(penalty)) β = Z'y，Cholesky 求解
+func ridgeLinear(Z *mat.Dense, Y *mat.VecDense, penalty []float64) ([]float64, error) {
+	_, p := Z.Dims()
+	var A mat.SymDense
+	A.SymOuterK(1, Z.T())
+	for j := 0; j < p; j++ {
+		A.SetSym(j, j, A.At(j, j)+penalty[j])
+	}
+	var chol mat.Cholesky
+	if !chol.Factorize(&A) {
+		return nil, errorx.New(errCode.INVALID_VALUE, "Z'Z + αI 非正定，请检查 alpha 或自变量是否共线")
+	}
+	var ZTY mat.VecDense
+	ZTY.MulVec(Z.T(), Y)
+	beta := mat.NewVecDense(p, nil)
+	if err := chol.SolveVecTo(beta, &ZTY); err != nil {
+		return nil, errorx.New(errCode.INVALID_VALUE, "Ridge 方程求解失败")
+	}
+	return beta.RawVector().Data, nil
+}
+
+// ridgeLogisticNewton: 梯度 g = Z'(p̂-y)/n + Λβ/n，Hessian H = Z'WZ/n + Λ/n
+func ridgeLogisticNewton(Z *mat.Dense, Y *mat.VecDense, penalty []float64, tol float64, maxIter int) ([]float64, error) {
+	n, p := Z.Dims()
+	nf := float64(n)
+	beta := make([]float64, p)
+	objective := func(b []float64) float64 {
+		eta := computePred(Z, b)
+		loss := 0.0
+		for i := 0; i < n; i++ {
+			loss += logisticLoss(eta.AtVec(i), Y.AtVec(i))
+		}
+		for j := 0; j < p; j++ {
+			loss += 0.5 * penalty[j] * b[j] * b[j]
+		}
+		return loss / nf
+	}
+
+	obj := objective(beta)
+	Zw := mat.NewDense(n, p, nil) // √w_i · z_i，H = Zw'Zw/n + Λ/n
+	for it := 0; it < maxIter; it++ {
+		eta := computePred(Z, beta)
+		g := mat.NewVecDense(p, nil)
+		for i := 0; i < n; i++ {
+			pi := sigmoid(eta.AtVec(i))
+			sw := math.Sqrt(math.Max(pi*(1-pi), 1e-10))
+			for j := 0; j < p; j++ {
+				zij := Z.At(i, j)
+				Zw.Set(i, j, sw*zij)
+				g.SetVec(j, g.AtVec(j)+(pi-Y.AtVec(i))*zij/nf)
+			}
+		}
+		var H mat.SymDense
+		H.SymOuterK(1/nf, Zw.T())
+		for j := 0; j < p; j++ {
+			g.SetVec(j, g.AtVec(j)+penalty[j]*beta[j]/nf)
+			H.SetSym(j, j, H.At(j, j)+penalty[j]/nf)
+		}
+		var chol mat.Cholesky
+		if !chol.Factorize(&H) {
+			return nil, errorx.New(errCode.INVALID_VALUE, "Hessian 非正定，数据可能完全可分，请增大 alpha")
+		}
+		var step mat.VecDense
+		if err := chol.SolveVecTo(&step, g); err != nil {
+			return nil, errorx.New(errCode.INVALID_VALUE, "Newton 方程求解失败")
+		}
+		// 步长减半直到目标下降
+		t := 1.0
+		next := make([]float64, p)
+		newObj := obj
+		for bt := 0; bt < 30; bt++ {
+			for j := 0; j < p; j++ {
+				next[j] = beta[j] - t*step.AtVec(j)
+			}
+			if newObj = objective(next); newObj <= obj {
+				break
+			}
+			t *= 0.5
+		}
+		maxChange := 0.0
+		for j := 0; j < p; j++ {
+			maxChange = math.Max(maxChange, math.Abs(next[j]-beta[j]))
+		}
+		copy(beta, next)
+		obj = newObj
+		if maxChange < tol {
+			break
+		}
+	}
+	return beta, nil
+}
@@ -0,0 +1,241 @@
+package ols
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func penalizedData(n int, logistic bool) (*mat.Dense, *mat.VecDense) {
+	r := rand.New(rand.NewSource(4))
+	X := mat.NewDense(n, 4, nil)
+	y := mat.NewVecDense(n, nil)
+	for i := 0; i < n; i++ {
+		x0, x1 := r.NormFloat64(), r.NormFloat64()
+		X.SetRow(i, []float64{x0, 3 * x1, x0 + 0.1*r.NormFloat64(), r.NormFloat64()})
+		eta := 0.5 + x0 - 0.5*x1
+		if logistic {
+			if r.Float64() < 1/(1+math.Exp(-eta)) {
+				y.SetVec(i, 1)
+			}
+		} else {
+			y.SetVec(i, eta+0.5*r.NormFloat64())
+		}
+	}
+	return X, y
+}
+
+// ------------------- 原实现(07a9763 的 lassoLinearCD): 线性 LASSO 坐标下降 -------------------
+// 目标: (1/(2n))||y - Xβ||^2 + α||β||_1，截距不惩罚
+func legacyLassoLinearCD(matX *mat.Dense, matY *mat.VecDense, alpha float64, withIntercept bool, tol float64, maxIter int) ([]float64, error) {
+	X := mat.DenseCopyOf(matX)
+	Y := mat.NewVecDense(matY.Len(), nil)
+	Y.CopyVec(matY)
+	// 处理截距列
+	if withIntercept {
+		X = addInterceptColumnDense(X)
+	}
+	// 标准化（除截距列）
+	means, stds := standardizeExceptIntercept(X, withIntercept)
+	n, p := X.Dims()
+	beta := make([]float64, p)
+
+	// 预先计算列平方和: gj = (1/n) * ||X_j||^2
+	gj := make([]float64, p)
+	for j := 0; j < p; j++ {
+		sum := 0.0
+		for i := 0; i < n; i++ {
+			v := X.At(i, j)
+			sum += v * v
+		}
+		gj[j] = sum / float64(n)
+		if gj[j] == 0 {
+			gj[j] = 1e-8
+		}
+	}
+
+	for it := 0; it < maxIter; it++ {
+		maxChange := 0.0
+		// 截距先单独更新: β0 ← 平均残差 + β0 （等价于不惩罚的坐标更新）
+		if withIntercept {
+			// r_i = y_i - Σ_{j>=1} x_ij β_j
+			sumRes := 0.0
+			for i := 0; i < n; i++ {
+				pred := 0.0
+				for j := 1; j < p; j++ {
+					pred += X.At(i, j) * beta[j]
+				}
+				sumRes += (Y.AtVec(i) - pred)
+			}
+			newB0 := sumRes / float64(n)
+			maxChange = math.Max(maxChange, math.Abs(newB0-beta[0]))
+			beta[0] = newB0
+		}
+		// 其他坐标做 L1 软阈值
+		start := 0
+		if withIntercept {
+			start = 1
+		}
+		for j := start; j < p; j++ {
+			// ρ_j = (1/n) * X_j^T (y - Xβ + X_j β_j)
+			var rho float64
+			for i := 0; i < n; i++ {
+				pred := beta[0]
+				for k := start; k < p; k++ {
+					pred += X.At(i, k) * beta[k]
+				}
+				r := Y.AtVec(i) - pred + X.At(i, j)*beta[j]
+				rho += X.At(i, j) * r
+			}
+			rho /= float64(n)
+			// 更新 β_j ← S(rho, α) / gj[j]
+			newBj := softThreshold(rho, alpha) / gj[j]
+			if math.IsNaN(newBj) || math.IsInf(newBj, 0) {
+				newBj = 0
+			}
+			maxChange = math.Max(maxChange, math.Abs(newBj-beta[j]))
+			beta[j] = newBj
+		}
+		if maxChange < tol {
+			break
+		}
+	}
+	// 反标准化（以及截距回到原尺度）
+	beta = destandardizeBeta(beta, means, stds, withIntercept)
+	return beta, nil
+}
+
+func assertClose(t *testing.T, name string, got, want []float64, tol float64) {
+	t.Helper()
+	for j := range want {
+		if math.Abs(got[j]-want[j]) > tol {
+			t.Errorf("%s coef %d: %v want %v", name, j, got[j], want[j])
+		}
+	}
+}
+
+func TestRidgeAndElasticNet(t *testing.T) {
+	X, y := penalizedData(400, false)
+	// α = 0 的 Ridge 即 OLS
+	ridge0, err := MultiRegressionRidge(X, y, 0, false, true, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ols, _ := MultiRegressionMat(addInterceptColumnDense(X), y)
+	assertClose(t, "ridge(0)", ridge0.Coeffs, ols.Coeffs, 1e-8)
+
+	// l1Ratio = 0 的坐标下降与闭式解一致
+	ridge, _ := MultiRegressionRidge(X, y, 50, false, true, false)
+	en0, _ := MultiRegressionElasticNet(X, y, 50, 0, false, true)
+	assertClose(t, "enet(l1=0)", en0.Coeffs, ridge.Coeffs, 1e-4)
+
+	// l1Ratio = 1 与原 LASSO 坐标下降实现收敛到同一解(α 按样本数缩放)
+	en1, _ := MultiRegressionElasticNet(X, y, 20, 1, false, true)
+	legacy, err := legacyLassoLinearCD(X, y, 20.0/400, true, 1e-12, 100000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertClose(t, "enet(l1=1)", en1.Coeffs, legacy, 1e-6)
+	if en1.Coeffs[3] != 0 {
+		t.Errorf("enet(l1=1) 未把共线列压到 0: %v", en1.Coeffs)
+	}
+
+	// 惩罚截距时截距向 0 收缩
+	ridgeP, _ := MultiRegressionRidge(X, y, 400, false, true, true)
+	if math.Abs(ridgeP.Coeffs[0]) >= math.Abs(ridge.Coeffs[0]) {
+		t.Errorf("惩罚截距 %v 未小于不惩罚 %v", ridgeP.Coeffs[0], ridge.Coeffs[0])
+	}
+}
+
+func TestRidgeLogistic(t *testing.T) {
+	X, y := penalizedData(600, true)
+	ridge, err := MultiRegressionRidge(X, y, 30, true, true, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	en0, _ := MultiRegressionElasticNet(X, y, 30, 0, true, true)
+	assertClose(t, "logistic enet(l1=0)", en0.Coeffs, ridge.Coeffs, 1e-3)
+	if ridge.RSquared <= 0 || ridge.RSquared >= 1 {
+		t.Errorf("McFadden R² = %v", ridge.RSquared)
+	}
+}
+
+// 原实现(07a9763 的 MultiRegressionLasso)由系数整理逻辑回归输出的部分: 残差与对数似然均基于线性预测值 Xβ
+func legacyLassoLogisticOutputs(matX *mat.Dense, matY *mat.VecDense, beta []float64, withIntercept bool) (resids []float64, aic, bic, r2 float64) {
+	n, _ := matX.Dims()
+	Xeff := mat.DenseCopyOf(matX)
+	if withIntercept {
+		Xeff = addInterceptColumnDense(Xeff)
+	}
+	yhat := computePred(Xeff, beta)
+	k := len(beta)
+	resids = make([]float64, n)
+	for i := 0; i < n; i++ {
+		resids[i] = matY.AtVec(i) - yhat.AtVec(i)
+	}
+	logLik := 0.0
+	for i := 0; i < n; i++ {
+		p := yhat.AtVec(i)
+		if p < 1e-12 {
+			p = 1e-12
+		} else if p > 1-1e-12 {
+			p = 1 - 1e-12
+		}
+		y := matY.AtVec(i)
+		logLik += y*math.Log(p) + (1-y)*math.Log(1-p)
+	}
+	ybar := 0.0
+	for i := 0; i < n; i++ {
+		ybar += matY.AtVec(i)
+	}
+	ybar /= float64(n)
+	llNull := float64(n) * (ybar*math.Log(ybar+1e-12) + (1-ybar)*math.Log(1-ybar+1e-12))
+	aic = -2*logLik + 2*float64(k)
+	bic = -2*logLik + float64(k)*math.Log(float64(n))
+	if llNull != 0 {
+		r2 = 1 - (logLik)/(llNull)
+	}
+	return
+}
+
+// 逻辑 LASSO 的残差、AIC/BIC 与 pseudo-R² 保持原 MultiRegressionLasso 的口径，
+// 同系数的 Elastic Net(l1Ratio=1) 输出基于概率 p̂
+func TestLassoLogisticOutputsMatchLegacy(t *testing.T) {
+	X, y := penalizedData(300, true)
+	for _, withIntercept := range []bool{true, false} {
+		m, err := MultiRegressionLasso(X, y, 2, true, withIntercept)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resids, aic, bic, r2 := legacyLassoLogisticOutputs(X, y, m.Coeffs, withIntercept)
+		for i, r := range resids {
+			if math.Abs(m.Resids[i]-r) > 1e-12 {
+				t.Fatalf("intercept=%v: resid[%d] = %v, want %v", withIntercept, i, m.Resids[i], r)
+			}
+		}
+		if math.Abs(m.AIC-aic) > 1e-9 || math.Abs(m.BIC-bic) > 1e-9 || math.Abs(m.RSquared-r2) > 1e-12 {
+			t.Errorf("intercept=%v: AIC=%v BIC=%v R2=%v, want %v %v %v", withIntercept, m.AIC, m.BIC, m.RSquared, aic, bic, r2)
+		}
+
+		en, err := MultiRegressionElasticNet(X, y, 2, 1, true, withIntercept)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertClose(t, "logistic enet(l1=1)", en.Coeffs, m.Coeffs, 0)
+		Xeff := mat.DenseCopyOf(X)
+		if withIntercept {
+			Xeff = addInterceptColumnDense(Xeff)
+		}
+		eta := computePred(Xeff, en.Coeffs)
+		for i := range en.Resids {
+			if want := y.AtVec(i) - sigmoid(eta.AtVec(i)); math.Abs(en.Resids[i]-want) > 1e-12 {
+				t.Fatalf("intercept=%v: enet resid[%d] = %v, want %v", withIntercept, i, en.Resids[i], want)
+			}
+		}
+		if withIntercept && (en.RSquared <= 0 || en.RSquared >= 1) {
+			t.Errorf("enet McFadden R² = %v", en.RSquared)
+		}
+	}
+}
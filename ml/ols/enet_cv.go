@@ -0,0 +1,216 @@
+package ols
+
+import (
+	"fmt"
+	"math"
+	"ofeisInfra/infra/errorx"
+	"ofeisInfra/infra/errorx/errCode"
+	"sync"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// 文件: enet_cv.go
+// 说明: 按时间顺序的交叉验证选 α（同 sklearn LassoCV / ElasticNetCV）。
+//       - 网格由全样本确定，各折使用相同的有效惩罚 λ = α/n（n 为全样本量），因此选出的 α 可直接用于全样本重拟合。
+//       - 标准化统计量只用各折训练集计算。
+//       - CV 误差: 线性为 MSE，逻辑为平均 log-loss。
+//       - alpha_1se: 平均误差不超过 min + SE(min) 的最大 α，SE 为各折误差的标准差 / √折数。
+
+type CVOptions struct {
+	Scheme   CVScheme
+	NFolds   int // 折数；walk-forward 时样本切为 NFolds+1 块，第 1 块只做训练
+	Purge    int // 从训练集中剔除测试块之前紧邻的样本数(标签与测试期重叠)
+	Embargo  int // purged k-fold 中测试块之后禁止进入训练的样本数
+	MinTrain int // 训练集最少样本数，不足的折报错
+}
+
+// 默认参数: walk-forward 5 折，无 purge/embargo
+func DefaultCVOptions() CVOptions {
+	return CVOptions{Scheme: CV_WALK_FORWARD, NFolds: 5}
+}
+
+type CVFold struct {
+	Train []int // 训练样本下标(升序)
+	Test  []int // 测试样本下标(连续)
+}
+
+type CVResult struct {
+	Path     PathResult  // 全样本路径
+	Folds    []CVFold    // 各折划分
+	FoldErr  [][]float64 // FoldErr[a][f] 第 f 折在 Path.Alphas[a] 上的测试误差
+	MeanErr  []float64   // 各 α 的平均 CV 误差
+	SEErr    []float64   // 各 α 的 CV 误差标准误
+	AlphaMin float64     // 平均误差最小的 α
+	Alpha1SE float64     // 1-SE 规则选出的 α(更稀疏)
+	IdxMin   int         // AlphaMin 在 Path.Alphas 中的下标
+	Idx1SE   int
+	ModelMin MultiLinearModel // 全样本在 AlphaMin 的拟合
+	Model1SE MultiLinearModel // 全样本在 Alpha1SE 的拟合
+}
+
+// ElasticNetCV: 时序交叉验证选 α，返回 CV 误差曲线与两种选择下的全样本模型
+func ElasticNetCV(matX *mat.Dense, matY *mat.VecDense, pathOpts PathOptions, cvOpts CVOptions) (CVResult, error) {
+	n, _ := matX.Dims()
+	if matY.Len() != n {
+		return CVResult{}, errorx.New(errCode.INVALID_VALUE, "Y 长度与 X 行数不匹配")
+	}
+	folds, err := TimeSeriesFolds(n, cvOpts)
+	if err != nil {
+		return CVResult{}, err
+	}
+	path, err := ElasticNetPath(matX, matY, pathOpts)
+	if err != nil {
+		return CVResult{}, err
+	}
+	nA, nF := len(path.Alphas), len(folds)
+	lams := make([]float64, nA)
+	for i, a := range path.Alphas {
+		lams[i] = a / float64(n)
+	}
+
+	// 各折独立，并行拟合
+	foldErr := make([][]float64, nF)
+	wg := sync.WaitGroup{}
+	for f, fold := range folds {
+		wg.Add(1)
+		go func(f int, fold CVFold) {
+			defer wg.Done()
+			d := newEnetData(matX, matY, fold.Train, pathOpts.WithIntercept, pathOpts.UseLogistic)
+			coeffs, _ := d.path(lams, pathOpts)
+			foldErr[f] = make([]float64, nA)
+			for a, c := range coeffs {
+				foldErr[f][a] = testError(matX, matY, fold.Test, c, pathOpts.WithIntercept, pathOpts.UseLogistic)
+			}
+		}(f, fold)
+	}
+	wg.Wait()
+
+	res := CVResult{
+		Path:    path,
+		Folds:   folds,
+		FoldErr: make([][]float64, nA),
+		MeanErr: make([]float64, nA),
+		SEErr:   make([]float64, nA),
+	}
+	for a := 0; a < nA; a++ {
+		res.FoldErr[a] = make([]float64, nF)
+		mean := 0.0
+		for f := 0; f < nF; f++ {
+			res.FoldErr[a][f] = foldErr[f][a]
+			mean += foldErr[f][a]
+		}
+		mean /= float64(nF)
+		v := 0.0
+		for f := 0; f < nF; f++ {
+			v += (foldErr[f][a] - mean) * (foldErr[f][a] - mean)
+		}
+		res.MeanErr[a] = mean
+		if nF > 1 {
+			res.SEErr[a] = math.Sqrt(v/float64(nF-1)) / math.Sqrt(float64(nF))
+		}
+		if mean < res.MeanErr[res.IdxMin] {
+			res.IdxMin = a
+		}
+	}
+	// α 降序，第一个满足阈值的即最大 α
+	limit := res.MeanErr[res.IdxMin] + res.SEErr[res.IdxMin]
+	for a := 0; a <= res.IdxMin; a++ {
+		if res.MeanErr[a] <= limit {
+			res.Idx1SE = a
+			break
+		}
+	}
+	res.AlphaMin, res.Alpha1SE = path.Alphas[res.IdxMin], path.Alphas[res.Idx1SE]
+	res.ModelMin = penalizedModel(matX, matY, path.Coeffs[res.IdxMin], pathOpts.UseLogistic, pathOpts.WithIntercept)
+	res.Model1SE = penalizedModel(matX, matY, path.Coeffs[res.Idx1SE], pathOpts.UseLogistic, pathOpts.WithIntercept)
+	return res, nil
+}
+
+// TimeSeriesFolds: 按时间顺序划分训练/测试集，测试块之间不重叠
+// walk-forward: 样本切为 NFolds+1 个连续块，第 f 折用前 f+1 块(扣除测试块前 Purge 个样本)训练、第 f+2 块测试
+// purged k-fold: 样本切为 NFolds 个连续块，轮流作测试块，训练集剔除测试块前 Purge 个与后 Embargo 个样本
+func TimeSeriesFolds(n int, opts CVOptions) ([]CVFold, error) {
+	if opts.NFolds < 2 && opts.Scheme == CV_PURGED_KFOLD || opts.NFolds < 1 {
+		return nil, errorx.New(errCode.INVALID_VALUE, "折数过少")
+	}
+	if opts.Purge < 0 || opts.Embargo < 0 {
+		return nil, errorx.New(errCode.INVALID_VALUE, "Purge/Embargo 不能为负")
+	}
+	minTrain := max(opts.MinTrain, 2)
+	var blocks int
+	switch opts.Scheme {
+	case CV_WALK_FORWARD:
+		blocks = opts.NFolds + 1
+	case CV_PURGED_KFOLD:
+		blocks = opts.NFolds
+	default:
+		return nil, errorx.New(errCode.INVALID_VALUE, "未知的交叉验证方式")
+	}
+	if n < blocks {
+		return nil, errorx.New(errCode.INVALID_VALUE, fmt.Sprintf("样本数 %d 少于块数 %d", n, blocks))
+	}
+	// 块边界: 前 n%blocks 块多 1 个样本
+	bounds := make([]int, blocks+1)
+	for b := 0; b < blocks; b++ {
+		size := n / blocks
+		if b < n%blocks {
+			size++
+		}
+		bounds[b+1] = bounds[b] + size
+	}
+
+	folds := make([]CVFold, 0, opts.NFolds)
+	for f := 0; f < opts.NFolds; f++ {
+		b := f
+		if opts.Scheme == CV_WALK_FORWARD {
+			b = f + 1
+		}
+		lo, hi := bounds[b], bounds[b+1]
+		fold := CVFold{Test: make([]int, 0, hi-lo)}
+		for i := lo; i < hi; i++ {
+			fold.Test = append(fold.Test, i)
+		}
+		trainEnd := n
+		if opts.Scheme == CV_WALK_FORWARD {
+			trainEnd = lo
+		}
+		for i := 0; i < trainEnd; i++ {
+			if i >= lo-opts.Purge && i < hi+opts.Embargo {
+				continue
+			}
+			fold.Train = append(fold.Train, i)
+		}
+		if len(fold.Train) < minTrain {
+			return nil, errorx.New(errCode.INVALID_VALUE, fmt.Sprintf("第 %d 折训练样本 %d 少于 %d", f, len(fold.Train), minTrain))
+		}
+		folds = append(folds, fold)
+	}
+	return folds, nil
+}
+
+// 测试集误差: 线性 MSE，逻辑平均 log-loss
+func testError(matX *mat.Dense, matY *mat.VecDense, rows []int, coeffs []float64, withIntercept, logistic bool) float64 {
+	_, p := matX.Dims()
+	off := 0
+	if withIntercept {
+		off = 1
+	}
+	sum := 0.0
+	for _, i := range rows {
+		eta := 0.0
+		if withIntercept {
+			eta = coeffs[0]
+		}
+		for j := 0; j < p; j++ {
+			eta += matX.At(i, j) * coeffs[j+off]
+		}
+		y := matY.AtVec(i)
+		if logistic {
+			sum += logisticLoss(eta, y)
+		} else {
+			sum += (y - eta) * (y - eta)
+		}
+	}
+	return sum / float64(len(rows))
+}
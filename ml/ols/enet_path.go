@@ -0,0 +1,382 @@
+package ols
+
+import (
+	"fmt"
+	"math"
+	"ofeisInfra/infra/errorx"
+	"ofeisInfra/infra/errorx/errCode"
+	"sort"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// 文件: enet_path.go
+// 说明: LASSO / Elastic Net 正则化路径（同 sklearn lasso_path / enet_path）。
+//       - α 约定与 MultiRegressionLasso 相同: 有效惩罚 λ = α/n，X 标准化（列主序存储），截距不惩罚。
+//       - 网格: α_max 为使全部系数为 0 的最小 α，按对数等距降到 Eps·α_max，共 NAlphas 个。
+//       - 热启动: 每个 α 以上一个 α 的解为初值。
+//       - 序贯强规则 (Tibshirani et al. 2012): |z_j'r|/n < l1Ratio·(2λ_k - λ_{k-1}) 的变量先剔除，
+//         在剩余集合上求解后对剔除变量做 KKT 检查，违反者加回重解。
+//       - 线性用残差更新的坐标下降；逻辑按 LogitSolver 选 IRLS + 坐标下降(默认) 或 FISTA。
+
+type PathOptions struct {
+	L1Ratio       float64   // L1 占比 (0,1]，1 为 LASSO
+	NAlphas       int       // 网格点数
+	Eps           float64   // α_min / α_max
+	Alphas        []float64 // 自定义网格(按降序使用)，非空时忽略 NAlphas/Eps
+	UseLogistic   bool
+	WithIntercept bool
+	LogitSolver   LogitSolver // 逻辑回归求解器，路径只支持 LOGIT_IRLS_CD / LOGIT_FISTA
+	Tol           float64     // 坐标/参数最大变化量收敛阈值(标准化尺度)
+	MaxIter       int         // 每个 α 的最大迭代次数
+}
+
+// 默认参数: LASSO，100 个点，Eps=1e-3，带截距
+func DefaultPathOptions() PathOptions {
+	return PathOptions{L1Ratio: 1, NAlphas: 100, Eps: 1e-3, WithIntercept: true, Tol: 1e-5, MaxIter: 10000}
+}
+
+type PathResult struct {
+	Alphas   []float64   // 降序 α(与 MultiRegressionLasso 的 alpha 同尺度)
+	Coeffs   [][]float64 // Coeffs[a] 原始尺度系数，带截距时截距在首位
+	NNonZero []int       // 非零系数个数(不含截距)
+	Iters    []int       // 各 α 的迭代次数
+	NObs     int
+}
+
+// ElasticNetPath: 沿降序 α 网格拟合整条路径
+func ElasticNetPath(matX *mat.Dense, matY *mat.VecDense, opts PathOptions) (PathResult, error) {
+	n, _ := matX.Dims()
+	if matY.Len() != n {
+		return PathResult{}, errorx.New(errCode.INVALID_VALUE, "Y 长度与 X 行数不匹配")
+	}
+	if err := opts.validate(); err != nil {
+		return PathResult{}, err
+	}
+	d := newEnetData(matX, matY, nil, opts.WithIntercept, opts.UseLogistic)
+	alphas, err := opts.grid(d)
+	if err != nil {
+		return PathResult{}, err
+	}
+	lams := make([]float64, len(alphas))
+	for i, a := range alphas {
+		lams[i] = a / float64(n)
+	}
+	coeffs, iters := d.path(lams, opts)
+	res := PathResult{Alphas: alphas, Coeffs: coeffs, NNonZero: make([]int, len(alphas)), Iters: iters, NObs: n}
+	for i, c := range coeffs {
+		res.NNonZero[i] = countNonZero(c, opts.WithIntercept)
+	}
+	return res, nil
+}
+
+func (o PathOptions) validate() error {
+	if o.L1Ratio < 0 || o.L1Ratio > 1 {
+		return errorx.New(errCode.INVALID_VALUE, fmt.Sprintf("l1Ratio=%v 须在 [0,1] 内", o.L1Ratio))
+	}
+	if len(o.Alphas) == 0 {
+		if o.L1Ratio == 0 {
+			return errorx.New(errCode.INVALID_VALUE, "l1Ratio=0 时 α_max 无定义，须给出 Alphas")
+		}
+		if o.NAlphas <= 0 || !(o.Eps > 0 && o.Eps < 1) {
+			return errorx.New(errCode.INVALID_VALUE, "NAlphas 须 > 0 且 Eps 须在 (0,1) 内")
+		}
+	}
+	for _, a := range o.Alphas {
+		if !(a >= 0) {
+			return errorx.New(errCode.INVALID_VALUE, "alpha 不能为负")
+		}
+	}
+	if o.UseLogistic && o.LogitSolver != LOGIT_IRLS_CD && o.LogitSolver != LOGIT_FISTA {
+		return errorx.New(errCode.INVALID_VALUE, fmt.Sprintf("路径不支持求解器 %s", o.LogitSolver))
+	}
+	if o.Tol <= 0 || o.MaxIter <= 0 {
+		return errorx.New(errCode.INVALID_VALUE, "Tol 与 MaxIter 须 > 0")
+	}
+	return nil
+}
+
+// α 网格(降序，用户尺度)
+func (o PathOptions) grid(d *enetData) ([]float64, error) {
+	if len(o.Alphas) > 0 {
+		alphas := append([]float64(nil), o.Alphas...)
+		sort.Sort(sort.Reverse(sort.Float64Slice(alphas)))
+		return alphas, nil
+	}
+	lamMax := d.lambdaMax(o.L1Ratio)
+	if lamMax <= 0 {
+		return nil, errorx.New(errCode.INVALID_VALUE, "所有自变量与 y 无关，α_max = 0")
+	}
+	aMax := lamMax * float64(d.n)
+	alphas := make([]float64, o.NAlphas)
+	for i := range alphas {
+		if o.NAlphas == 1 {
+			alphas[i] = aMax
+			break
+		}
+		alphas[i] = aMax * math.Pow(o.Eps, float64(i)/float64(o.NAlphas-1))
+	}
+	return alphas, nil
+}
+
+// 标准化后的列主序数据
+type enetData struct {
+	n, p          int
+	cols          [][]float64 // 标准化列 z_j
+	y             []float64
+	means, stds   []float64 // 含截距位(截距位为 0/1)，与 destandardizeBeta 约定一致
+	withIntercept bool
+	logistic      bool
+}
+
+// rows 非 nil 时只取这些行(交叉验证训练集)，标准化只用这些行的统计量
+func newEnetData(matX *mat.Dense, matY *mat.VecDense, rows []int, withIntercept, logistic bool) *enetData {
+	nAll, p := matX.Dims()
+	if rows == nil {
+		rows = make([]int, nAll)
+		for i := range rows {
+			rows[i] = i
+		}
+	}
+	n := len(rows)
+	off := 0
+	if withIntercept {
+		off = 1
+	}
+	d := &enetData{
+		n: n, p: p, cols: make([][]float64, p), y: make([]float64, n),
+		means: make([]float64, p+off), stds: make([]float64, p+off),
+		withIntercept: withIntercept, logistic: logistic,
+	}
+	if withIntercept {
+		d.stds[0] = 1
+	}
+	for i, r := range rows {
+		d.y[i] = matY.AtVec(r)
+	}
+	// 与 standardizeExceptIntercept 相同: 去均值、总体标准差、零方差列标准差取 1
+	for j := 0; j < p; j++ {
+		col := make([]float64, n)
+		mu := 0.0
+		for i, r := range rows {
+			col[i] = matX.At(r, j)
+			mu += col[i]
+		}
+		mu /= float64(n)
+		v := 0.0
+		for _, x := range col {
+			v += (x - mu) * (x - mu)
+		}
+		std := math.Sqrt(v / float64(n))
+		if std == 0 {
+			std = 1
+		}
+		for i := range col {
+			col[i] = (col[i] - mu) / std
+		}
+		d.cols[j] = col
+		d.means[j+off], d.stds[j+off] = mu, std
+	}
+	return d
+}
+
+// 使全部系数为 0 的最小 λ: max_j |z_j'(y - μ_0)|/n / l1Ratio，μ_0 为仅截距模型的拟合值
+func (d *enetData) lambdaMax(l1Ratio float64) float64 {
+	b0 := d.nullIntercept()
+	resid := make([]float64, d.n)
+	for i, y := range d.y {
+		resid[i] = y - d.mean(b0)
+	}
+	best := 0.0
+	for j := 0; j < d.p; j++ {
+		best = math.Max(best, math.Abs(dot(d.cols[j], resid))/float64(d.n))
+	}
+	return best / l1Ratio
+}
+
+// 仅截距模型的截距(线性 ȳ，逻辑 logit(ȳ))；无截距时为 0
+func (d *enetData) nullIntercept() float64 {
+	if !d.withIntercept {
+		return 0
+	}
+	ybar := 0.0
+	for _, y := range d.y {
+		ybar += y
+	}
+	ybar /= float64(d.n)
+	if d.logistic {
+		ybar = math.Min(math.Max(ybar, 1e-10), 1-1e-10)
+		return math.Log(ybar / (1 - ybar))
+	}
+	return ybar
+}
+
+// 线性预测 η 对应的均值
+func (d *enetData) mean(eta float64) float64 {
+	if d.logistic {
+		return sigmoid(eta)
+	}
+	return eta
+}
+
+// 沿 λ 网格求解，返回原始尺度系数
+func (d *enetData) path(lams []float64, opts PathOptions) ([][]float64, []int) {
+	l1 := opts.L1Ratio
+	beta := make([]float64, d.p)
+	b0 := d.nullIntercept()
+	eta := make([]float64, d.n)
+	for i := range eta {
+		eta[i] = b0
+	}
+	resid := make([]float64, d.n) // y - μ(η)
+	grad := make([]float64, d.p)  // z_j'(y - μ)/n
+	updateGrad := func(js []int) {
+		for i := range resid {
+			resid[i] = d.y[i] - d.mean(eta[i])
+		}
+		for _, j := range js {
+			grad[j] = dot(d.cols[j], resid) / float64(d.n)
+		}
+	}
+	all := make([]int, d.p)
+	for j := range all {
+		all[j] = j
+	}
+	updateGrad(all)
+
+	coeffs := make([][]float64, len(lams))
+	iters := make([]int, len(lams))
+	lamMax := d.lambdaMax(math.Max(l1, 1e-12))
+	lamPrev := lamMax
+	inSet := make([]bool, d.p)
+	for k, lam := range lams {
+		// λ >= λ_max 时解即仅截距模型，直接给出以免舍入误差产生 1e-16 量级的非零系数
+		if lam >= lamMax {
+			coeffs[k] = d.originalScale(beta, b0)
+			continue
+		}
+		// 序贯强规则
+		set := make([]int, 0, d.p)
+		for j := 0; j < d.p; j++ {
+			inSet[j] = beta[j] != 0 || math.Abs(grad[j]) >= l1*(2*lam-lamPrev)
+			if inSet[j] {
+				set = append(set, j)
+			}
+		}
+		for {
+			if d.logistic && opts.LogitSolver == LOGIT_FISTA {
+				iters[k] += d.solveLogisticFISTA(beta, &b0, eta, set, lam, l1, opts.Tol, opts.MaxIter)
+			} else if d.logistic {
+				iters[k] += d.solveLogisticIRLSCD(beta, &b0, eta, set, lam, l1, opts.Tol, opts.MaxIter)
+			} else {
+				iters[k] += d.solveLinear(beta, &b0, eta, set, lam, l1, opts.Tol, opts.MaxIter)
+			}
+			// KKT 检查: 被剔除变量须满足 |g_j| <= λ·l1Ratio
+			updateGrad(all)
+			added := false
+			for j := 0; j < d.p; j++ {
+				if !inSet[j] && math.Abs(grad[j]) > lam*l1*(1+1e-9) {
+					inSet[j] = true
+					set = append(set, j)
+					added = true
+				}
+			}
+			if !added {
+				break
+			}
+		}
+		coeffs[k] = d.originalScale(beta, b0)
+		lamPrev = lam
+	}
+	return coeffs, iters
+}
+
+// 线性: 残差坐标下降，标准化列满足 ||z_j||²/n = 1(零方差列为 0，系数恒为 0)
+func (d *enetData) solveLinear(beta []float64, b0 *float64, eta []float64, set []int, lam, l1, tol float64, maxIter int) int {
+	n := float64(d.n)
+	r := make([]float64, d.n)
+	for i := range r {
+		r[i] = d.y[i] - eta[i]
+	}
+	it := 0
+	for it < maxIter {
+		it++
+		maxChange := 0.0
+		if d.withIntercept {
+			// 列已中心化，截距的坐标更新即残差均值
+			s := 0.0
+			for _, v := range r {
+				s += v
+			}
+			delta := s / n
+			if delta != 0 {
+				for i := range r {
+					r[i] -= delta
+				}
+				*b0 += delta
+				maxChange = math.Max(maxChange, math.Abs(delta))
+			}
+		}
+		for _, j := range set {
+			zj := d.cols[j]
+			gj := dot(zj, zj) / n
+			if gj == 0 {
+				continue
+			}
+			rho := dot(zj, r)/n + gj*beta[j]
+			nb := softThreshold(rho, lam*l1) / (gj + lam*(1-l1))
+			if delta := nb - beta[j]; delta != 0 {
+				for i := range r {
+					r[i] -= zj[i] * delta
+				}
+				beta[j] = nb
+				maxChange = math.Max(maxChange, math.Abs(delta))
+			}
+		}
+		if maxChange < tol {
+			break
+		}
+	}
+	for i := range eta {
+		eta[i] = d.y[i] - r[i]
+	}
+	return it
+}
+
+// 标准化空间 (b0, β) → 原始尺度系数
+func (d *enetData) originalScale(beta []float64, b0 float64) []float64 {
+	full := beta
+	if d.withIntercept {
+		full = append([]float64{b0}, beta...)
+	}
+	return destandardizeBeta(full, d.means, d.stds, d.withIntercept)
+}
+
+func countNonZero(coeffs []float64, withIntercept bool) int {
+	start := 0
+	if withIntercept {
+		start = 1
+	}
+	nz := 0
+	for _, c := range coeffs[start:] {
+		if c != 0 {
+			nz++
+		}
+	}
+	return nz
+}
+
+func dot(a, b []float64) float64 {
+	s := 0.0
+	for i := range a {
+		s += a[i] * b[i]
+	}
+	return s
+}
+
+// y += a·x
+func axpy(y []float64, a float64, x []float64) {
+	for i := range y {
+		y[i] += a * x[i]
+	}
+}
@@ -0,0 +1,98 @@
+package ols
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// 稀疏真值: 20 个自变量中只有前 3 个非零
+func sparseData(n int, logistic bool) (*mat.Dense, *mat.VecDense) {
+	r := rand.New(rand.NewSource(5))
+	X := mat.NewDense(n, 20, nil)
+	y := mat.NewVecDense(n, nil)
+	for i := 0; i < n; i++ {
+		for j := 0; j < 20; j++ {
+			X.Set(i, j, r.NormFloat64())
+		}
+		eta := 1 + 2*X.At(i, 0) - 1.5*X.At(i, 1) + X.At(i, 2)
+		if logistic {
+			if r.Float64() < 1/(1+math.Exp(-eta)) {
+				y.SetVec(i, 1)
+			}
+		} else {
+			y.SetVec(i, eta+r.NormFloat64())
+		}
+	}
+	return X, y
+}
+
+func TestElasticNetPathMatchesSingleFit(t *testing.T) {
+	for _, logistic := range []bool{false, true} {
+		X, y := sparseData(300, logistic)
+		opts := DefaultPathOptions()
+		opts.UseLogistic = logistic
+		opts.NAlphas = 30
+		opts.Tol = 1e-8
+		path, err := ElasticNetPath(X, y, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if path.NNonZero[0] != 0 {
+			t.Errorf("α_max 处应全为 0, nnz=%d", path.NNonZero[0])
+		}
+		for _, a := range []int{5, 15, 29} {
+			single, err := MultiRegressionLasso(X, y, path.Alphas[a], logistic, true)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assertClose(t, "path", path.Coeffs[a], single.Coeffs, 2e-3)
+		}
+	}
+}
+
+func TestTimeSeriesFolds(t *testing.T) {
+	folds, err := TimeSeriesFolds(100, CVOptions{Scheme: CV_PURGED_KFOLD, NFolds: 5, Purge: 3, Embargo: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := folds[2] // 测试 [40,60)
+	if f.Test[0] != 40 || f.Test[len(f.Test)-1] != 59 || len(f.Train) != 100-20-3-2 {
+		t.Errorf("test %v..%v train %d", f.Test[0], f.Test[len(f.Test)-1], len(f.Train))
+	}
+	for _, i := range f.Train {
+		if i >= 37 && i < 62 {
+			t.Errorf("训练集含 purge/embargo 样本 %d", i)
+		}
+	}
+	wf, _ := TimeSeriesFolds(120, CVOptions{Scheme: CV_WALK_FORWARD, NFolds: 5, Purge: 2})
+	for _, f := range wf {
+		if last := f.Train[len(f.Train)-1]; last != f.Test[0]-3 {
+			t.Errorf("walk-forward 训练集末尾 %d, 测试起点 %d", last, f.Test[0])
+		}
+	}
+}
+
+func TestElasticNetCV(t *testing.T) {
+	X, y := sparseData(500, false)
+	opts := DefaultPathOptions()
+	opts.NAlphas = 40
+	cv, err := ElasticNetCV(X, y, opts, CVOptions{Scheme: CV_PURGED_KFOLD, NFolds: 5, Purge: 5, Embargo: 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cv.Alpha1SE < cv.AlphaMin || cv.MeanErr[cv.Idx1SE] > cv.MeanErr[cv.IdxMin]+cv.SEErr[cv.IdxMin] {
+		t.Errorf("alpha_1se %v alpha_min %v", cv.Alpha1SE, cv.AlphaMin)
+	}
+	// 噪声方差为 1，最优 CV 误差应接近 1
+	if e := cv.MeanErr[cv.IdxMin]; e < 0.8 || e > 1.3 {
+		t.Errorf("min cv mse %v", e)
+	}
+	for j := 1; j <= 3; j++ {
+		if cv.Model1SE.Coeffs[j] == 0 {
+			t.Errorf("真实变量 %d 被剔除", j-1)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+// GLM 分布族与连接函数。IRLS 只通过 glmFamily / glmLink 接口取用方差函数、单位偏差、对数似然与连接导数，
+// 新增分布族或连接函数时实现接口并在 newGLMFamily / newGLMLink 中登记即可
+//
+//	单位偏差 d(y, μ)，偏差 D = Σ w_i d(y_i, μ_i):
+//	  gaussian: (y - μ)²
+//	  poisson:  2[y ln(y/μ) - (y - μ)]
+//	  negbin:   2[y ln(y/μ) - (y + 1/α) ln((1 + αy)/(1 + αμ))]
+//	  gamma:    2[-ln(y/μ) + (y - μ)/μ]
+package ols
+
+import "math"
+
+type glmFamily interface {
+	variance(mu float64) float64
+	unitDeviance(y, mu float64) float64
+	// 单个观测的对数似然，w 为先验权重，scale 为离散参数 φ
+	logLik(y, mu, w, scale float64) float64
+	validY(y float64) bool
+	validMu(mu float64) bool
+	// φ 是否需要估计(泊松、负二项固定为 1)
+	estimateScale() bool
+}
+
+type glmLink interface {
+	link(mu float64) float64
+	inverse(eta float64) float64
+	deriv(mu float64) float64 // dη/dμ
+}
+
+func newGLMFamily(family GLMFamily, alpha float64) glmFamily {
+	switch family {
+	case FAMILY_GAUSSIAN:
+		return gaussianFamily{}
+	case FAMILY_POISSON:
+		return poissonFamily{}
+	case FAMILY_NEGBIN:
+		return negBinFamily{alpha: alpha}
+	case FAMILY_GAMMA:
+		return gammaFamily{}
+	default:
+		return nil
+	}
+}
+
+func newGLMLink(link GLMLink) glmLink {
+	switch link {
+	case LINK_IDENTITY:
+		return identityLink{}
+	case LINK_LOG:
+		return logLink{}
+	default:
+		return nil
+	}
+}
+
+// y·ln(y/μ)，y=0 时取 0
+func ylogyOverMu(y, mu float64) float64 {
+	if y == 0 {
+		return 0
+	}
+	return y * math.Log(y/mu)
+}
+
+type gaussianFamily struct{}
+
+func (gaussianFamily) variance(float64) float64           { return 1 }
+func (gaussianFamily) unitDeviance(y, mu float64) float64 { return (y - mu) * (y - mu) }
+func (gaussianFamily) logLik(y, mu, w, scale float64) float64 {
+	return -0.5 * (w*(y-mu)*(y-mu)/scale + math.Log(2*math.Pi*scale/w))
+}
+func (gaussianFamily) validY(y float64) bool   { return !math.IsNaN(y) && !math.IsInf(y, 0) }
+func (gaussianFamily) validMu(mu float64) bool { return !math.IsNaN(mu) && !math.IsInf(mu, 0) }
+func (gaussianFamily) estimateScale() bool     { return true }
+
+type poissonFamily struct{}
+
+func (poissonFamily) variance(mu float64) float64 { return mu }
+func (poissonFamily) unitDeviance(y, mu float64) float64 {
+	return 2 * (ylogyOverMu(y, mu) - (y - mu))
+}
+func (poissonFamily) logLik(y, mu, w, _ float64) float64 {
+	ly, _ := math.Lgamma(y + 1)
+	ll := -mu - ly
+	if y > 0 {
+		ll += y * math.Log(mu)
+	}
+	return w * ll
+}
+func (poissonFamily) validY(y float64) bool   { return y >= 0 && !math.IsInf(y, 0) }
+func (poissonFamily) validMu(mu float64) bool { return mu > 0 && !math.IsInf(mu, 0) }
+func (poissonFamily) estimateScale() bool     { return false }
+
+type negBinFamily struct {
+	alpha float64
+}
+
+func (f negBinFamily) variance(mu float64) float64 { return mu + f.alpha*mu*mu }
+func (f negBinFamily) unitDeviance(y, mu float64) float64 {
+	a := f.alpha
+	return 2 * (ylogyOverMu(y, mu) - (y+1/a)*math.Log((1+a*y)/(1+a*mu)))
+}
+func (f negBinFamily) logLik(y, mu, w, _ float64) float64 {
+	a := f.alpha
+	l1, _ := math.Lgamma(y + 1/a)
+	l2, _ := math.Lgamma(1 / a)
+	l3, _ := math.Lgamma(y + 1)
+	ll := l1 - l2 - l3 - math.Log1p(a*mu)/a
+	if y > 0 {
+		ll += y * math.Log(a*mu/(1+a*mu))
+	}
+	return w * ll
+}
+func (negBinFamily) validY(y float64) bool   { return y >= 0 && !math.IsInf(y, 0) }
+func (negBinFamily) validMu(mu float64) bool { return mu > 0 && !math.IsInf(mu, 0) }
+func (negBinFamily) estimateScale() bool     { return false }
+
+type gammaFamily struct{}
+
+func (gammaFamily) variance(mu float64) float64 { return mu * mu }
+func (gammaFamily) unitDeviance(y, mu float64) float64 {
+	return 2 * (-math.Log(y/mu) + (y-mu)/mu)
+}
+
+// 形状参数 ν = w/φ
+func (gammaFamily) logLik(y, mu, w, scale float64) float64 {
+	nu := w / scale
+	lg, _ := math.Lgamma(nu)
+	return nu*math.Log(nu*y/mu) - nu*y/mu - math.Log(y) - lg
+}
+func (gammaFamily) validY(y float64) bool   { return y > 0 && !math.IsInf(y, 0) }
+func (gammaFamily) validMu(mu float64) bool { return mu > 0 && !math.IsInf(mu, 0) }
+func (gammaFamily) estimateScale() bool     { return true }
+
+type identityLink struct{}
+
+func (identityLink) link(mu float64) float64     { return mu }
+func (identityLink) inverse(eta float64) float64 { return eta }
+func (identityLink) deriv(float64) float64       { return 1 }
+
+type logLink struct{}
+
+func (logLink) link(mu float64) float64     { return math.Log(mu) }
+func (logLink) inverse(eta float64) float64 { return math.Exp(eta) }
+func (logLink) deriv(mu float64) float64    { return 1 / mu }
@@ -0,0 +1,481 @@
+// 广义线性模型(GLM): g(E[y_i]) = x_i'β + o_i，Var(y_i) = φ·V(μ_i)/w_i，o 为偏移(Offset)，w 为先验权重(Weights)
+// IRLS(Fisher scoring): 在当前 μ 处令 ω_i = w_i / (V(μ_i)·g'(μ_i)²)，工作响应 z_i = η_i - o_i + (y_i - μ_i)·g'(μ_i)，
+// 对 √ω X β = √ω z 做加权最小二乘(solveLeastSquares QR)；偏差上升或 μ 越界时向上一步折半，
+// 收敛判据 |D - D_old| / (|D| + 0.1) < Tol(同 R glm.fit)
+//
+//	非稳健: Cov = φ(X'ΩX)^-1，φ 对泊松/负二项取 1，对 gaussian/gamma 取 Pearson χ²/(n - rank)
+//	稳健:   以 √ω X 为设计矩阵、√ω_i·g'(μ_i)(y_i - μ_i) 为残差调用 RobustCov，即得分 ω_i g'(μ_i)(y_i - μ_i) x_i 的三明治估计
+//
+// z 统计量与 p 值使用标准正态分布(同 statsmodels GLM 默认)
+// 负二项 Alpha <= 0 时交替估计: 固定 α 由 IRLS 求 β，固定 μ 在 ln α 上黄金分割最大化对数似然，直至 α 收敛
+package ols
+
+import (
+	"fmt"
+	"math"
+	"ofeisInfra/infra/errorx"
+	"ofeisInfra/infra/errorx/errCode"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+const (
+	glmNBMaxIter = 50
+	glmNBTol     = 1e-6 // ln α 变化收敛阈值
+	glmStepHalve = 30
+)
+
+type GLMOptions struct {
+	Family  GLMFamily
+	Link    GLMLink
+	Alpha   float64    // 负二项离散参数 α；<= 0 时极大似然估计，其他分布族忽略
+	Offset  []float64  // 线性预测偏移(如 ln 暴露量)，为空时取 0
+	Weights []float64  // 先验权重，为空时取 1
+	Cov     CovOptions // 系数协方差口径
+	MaxIter int
+	Tol     float64 // 偏差相对变化收敛阈值
+}
+
+// 默认参数: gaussian 用 identity 连接，其余分布族用 log 连接
+func DefaultGLMOptions(family GLMFamily) GLMOptions {
+	link := LINK_LOG
+	if family == FAMILY_GAUSSIAN {
+		link = LINK_IDENTITY
+	}
+	return GLMOptions{Family: family, Link: link, MaxIter: 100, Tol: 1e-8}
+}
+
+type GLMModel struct {
+	MultiLinearModel // Coeffs/SE/TStats(z 统计量)/PValues(正态)/Cov；Resids 为响应残差 y - μ̂，Sigma2 为离散参数 φ，RSquared 为偏差解释比例 1 - D/D_0
+	Family           GLMFamily
+	Link             GLMLink
+	Alpha            float64   // 负二项 α(给定值或估计值)
+	Mu               []float64 // 拟合均值 μ̂
+	Deviance         float64
+	NullDeviance     float64 // 空模型偏差(有常数列时仅截距，否则 β = 0；均含 Offset)
+	PearsonChi2      float64
+	LogLik           float64 // gaussian 时 φ 取极大似然估计 Σw(y-μ)²/n，与 MultiRegression 的 AIC 口径一致
+	LLNull           float64
+	NIter            int
+	Converged        bool
+	WithConst        bool
+}
+
+// 广义线性模型(行数据接口)，withConst 时在最左侧添加常数列
+func GLMRegression(X [][]float64, Y []float64, withConst bool, opts GLMOptions) (GLMModel, error) {
+	n := len(Y)
+	if n == 0 || len(X) == 0 {
+		return GLMModel{}, errorx.New(errCode.EMPTY_VALUE, "输入数据为空")
+	}
+	if n != len(X) {
+		return GLMModel{}, errorx.New(errCode.INVALID_VALUE, "数据长度不匹配")
+	}
+	if withConst {
+		X = addConstantColumn(X)
+	}
+	k := len(X[0])
+	matX := mat.NewDense(n, k, nil)
+	for i := 0; i < n; i++ {
+		if len(X[i]) != k {
+			return GLMModel{}, errorx.New(errCode.INVALID_VALUE, "X 各行长度不一致")
+		}
+		matX.SetRow(i, X[i])
+	}
+	m, err := GLMRegressionMat(matX, mat.NewVecDense(n, append([]float64(nil), Y...)), opts)
+	m.WithConst = withConst
+	return m, err
+}
+
+// 广义线性模型，matX 需自带常数列
+func GLMRegressionMat(matX *mat.Dense, matY *mat.VecDense, opts GLMOptions) (GLMModel, error) {
+	n, k := matX.Dims()
+	if matY.Len() != n {
+		return GLMModel{}, errorx.New(errCode.INVALID_VALUE, "数据长度不匹配")
+	}
+	if n <= k {
+		return GLMModel{}, errorx.New(errCode.INVALID_VALUE, fmt.Sprintf("样本数 %d 须大于参数数 %d", n, k))
+	}
+	if err := opts.validate(n); err != nil {
+		return GLMModel{}, err
+	}
+	g := &glmProblem{
+		X:       matX,
+		y:       make([]float64, n),
+		w:       opts.Weights,
+		off:     opts.Offset,
+		fam:     newGLMFamily(opts.Family, opts.Alpha),
+		link:    newGLMLink(opts.Link),
+		maxIter: opts.MaxIter,
+		tol:     opts.Tol,
+	}
+	for i := range g.y {
+		g.y[i] = matY.AtVec(i)
+		if !g.fam.validY(g.y[i]) {
+			return GLMModel{}, errorx.New(errCode.INVALID_VALUE, fmt.Sprintf("Y[%d]=%v 不在 %s 分布族的取值范围内", i, g.y[i], opts.Family))
+		}
+	}
+	if len(g.w) == 0 {
+		g.w = make([]float64, n)
+		for i := range g.w {
+			g.w[i] = 1
+		}
+	}
+	if len(g.off) == 0 {
+		g.off = make([]float64, n)
+	}
+
+	estAlpha := opts.Family == FAMILY_NEGBIN && opts.Alpha <= 0
+	var fit glmFit
+	var err error
+	alpha := opts.Alpha
+	if estAlpha {
+		fit, alpha, err = g.fitNegBinAlpha()
+	} else {
+		fit, err = g.irls(nil)
+	}
+	if err != nil {
+		return GLMModel{}, err
+	}
+	m, err := g.model(fit, opts.Cov, estAlpha)
+	if err != nil {
+		return GLMModel{}, err
+	}
+	m.Family, m.Link = opts.Family, opts.Link
+	if opts.Family == FAMILY_NEGBIN {
+		m.Alpha = alpha
+	}
+	return m, nil
+}
+
+// 预测均值 μ = g^-1(x'β + offset)，x 不含常数项(WithConst 时自动添加)
+func (m *GLMModel) Predict(x []float64, offset float64) (float64, error) {
+	if m.WithConst {
+		x = append([]float64{1}, x...)
+	}
+	if len(x) != len(m.Coeffs) {
+		return math.NaN(), errorx.New(errCode.INVALID_VALUE, "x 长度与参数数不符")
+	}
+	link := newGLMLink(m.Link)
+	if link == nil {
+		return math.NaN(), errorx.New(errCode.INVALID_VALUE, "未知的连接函数")
+	}
+	eta := offset
+	for j, c := range m.Coeffs {
+		eta += x[j] * c
+	}
+	return link.inverse(eta), nil
+}
+
+func (o GLMOptions) validate(n int) error {
+	if newGLMFamily(o.Family, o.Alpha) == nil {
+		return errorx.New(errCode.INVALID_VALUE, "未知的 GLM 分布族")
+	}
+	if newGLMLink(o.Link) == nil {
+		return errorx.New(errCode.INVALID_VALUE, "未知的连接函数")
+	}
+	if len(o.Offset) != 0 && len(o.Offset) != n {
+		return errorx.New(errCode.INVALID_VALUE, "Offset 长度与样本量不一致")
+	}
+	if len(o.Weights) != 0 && len(o.Weights) != n {
+		return errorx.New(errCode.INVALID_VALUE, "Weights 长度与样本量不一致")
+	}
+	for _, v := range o.Offset {
+		if !isFinite(v) {
+			return errorx.New(errCode.INVALID_VALUE, "Offset 须为有限值")
+		}
+	}
+	for _, v := range o.Weights {
+		if !(v > 0) || math.IsInf(v, 1) {
+			return errorx.New(errCode.INVALID_VALUE, "Weights 须为正的有限值")
+		}
+	}
+	if o.Tol <= 0 || o.MaxIter <= 0 {
+		return errorx.New(errCode.INVALID_VALUE, "Tol 与 MaxIter 须 > 0")
+	}
+	return nil
+}
+
+type glmProblem struct {
+	X       *mat.Dense
+	y       []float64
+	w       []float64
+	off     []float64
+	fam     glmFamily
+	link    glmLink
+	maxIter int
+	tol     float64
+}
+
+type glmFit struct {
+	beta      *mat.VecDense
+	eta, mu   []float64
+	sol       olsSolution // 末次加权最小二乘，invXTX 即 (X'ΩX)^-1
+	xw        *mat.Dense  // 末次 √ω X
+	dev       float64
+	nIter     int
+	converged bool
+}
+
+// IRLS，beta0 为空时以 μ_i = (y_i + ȳ)/2 起步
+func (g *glmProblem) irls(beta0 *mat.VecDense) (glmFit, error) {
+	n, k := g.X.Dims()
+	fit := glmFit{eta: make([]float64, n), mu: make([]float64, n)}
+	if beta0 == nil {
+		ybar, sw := 0.0, 0.0
+		for i, y := range g.y {
+			ybar += g.w[i] * y
+			sw += g.w[i]
+		}
+		ybar /= sw
+		for i, y := range g.y {
+			fit.mu[i] = (y + ybar) / 2
+			fit.eta[i] = g.link.link(fit.mu[i])
+		}
+	} else {
+		fit.beta = mat.VecDenseCopyOf(beta0)
+		if !g.evalMu(fit.beta, fit.eta, fit.mu) {
+			return glmFit{}, errorx.New(errCode.INVALID_VALUE, "初始系数处 μ 越界")
+		}
+	}
+	dev := g.deviance(fit.mu)
+
+	Xw := mat.NewDense(n, k, nil)
+	z := mat.NewVecDense(n, nil)
+	next := mat.NewVecDense(k, nil)
+	eta := make([]float64, n)
+	mu := make([]float64, n)
+	for it := 1; it <= g.maxIter; it++ {
+		fit.nIter = it
+		g.weigh(fit.mu, fit.eta, Xw, z)
+		sol, err := solveLeastSquares(Xw, z, SOLVER_QR)
+		if err != nil {
+			return glmFit{}, err
+		}
+
+		// 首步(无上一步系数)直接接受；之后偏差上升或 μ 越界时折半
+		newDev, ok := 0.0, false
+		step := 1.0
+		for bt := 0; bt < glmStepHalve; bt++ {
+			if fit.beta == nil {
+				next.CopyVec(sol.beta)
+			} else {
+				next.ScaleVec(1-step, fit.beta)
+				next.AddScaledVec(next, step, sol.beta)
+			}
+			if g.evalMu(next, eta, mu) {
+				newDev = g.deviance(mu)
+				if fit.beta == nil || newDev <= dev+1e-12*math.Abs(dev) {
+					ok = true
+					break
+				}
+			}
+			if fit.beta == nil {
+				break
+			}
+			step *= 0.5
+		}
+		if !ok {
+			if fit.beta == nil {
+				return glmFit{}, errorx.New(errCode.INVALID_VALUE, "IRLS 首步 μ 越界，请检查连接函数是否适合该分布族")
+			}
+			break
+		}
+		if fit.beta == nil {
+			fit.beta = mat.NewVecDense(k, nil)
+		}
+		fit.beta.CopyVec(next)
+		copy(fit.eta, eta)
+		copy(fit.mu, mu)
+		done := math.Abs(newDev-dev) < g.tol*(math.Abs(newDev)+0.1)
+		dev = newDev
+		if done {
+			fit.converged = true
+			break
+		}
+	}
+	// 在最终 μ 处刷新权重与 (X'ΩX)^-1
+	g.weigh(fit.mu, fit.eta, Xw, z)
+	sol, err := solveLeastSquares(Xw, z, SOLVER_QR)
+	if err != nil {
+		return glmFit{}, err
+	}
+	fit.sol, fit.xw, fit.dev = sol, Xw, dev
+	return fit, nil
+}
+
+// η = Xβ + o，μ = g^-1(η)；μ 越界返回 false
+func (g *glmProblem) evalMu(beta *mat.VecDense, eta, mu []float64) bool {
+	mat.NewVecDense(len(eta), eta).MulVec(g.X, beta)
+	for i := range eta {
+		eta[i] += g.off[i]
+		mu[i] = g.link.inverse(eta[i])
+		if !g.fam.validMu(mu[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// 工作权重与工作响应: Xw = √ω X，z = √ω (η - o + (y - μ)g'(μ))
+func (g *glmProblem) weigh(mu, eta []float64, Xw *mat.Dense, z *mat.VecDense) {
+	_, k := g.X.Dims()
+	for i, m := range mu {
+		d := g.link.deriv(m)
+		s := math.Sqrt(g.w[i] / (g.fam.variance(m) * d * d))
+		for j := 0; j < k; j++ {
+			Xw.Set(i, j, s*g.X.At(i, j))
+		}
+		z.SetVec(i, s*(eta[i]-g.off[i]+(g.y[i]-m)*d))
+	}
+}
+
+func (g *glmProblem) deviance(mu []float64) float64 {
+	dev := 0.0
+	for i, y := range g.y {
+		dev += g.w[i] * g.fam.unitDeviance(y, mu[i])
+	}
+	return dev
+}
+
+// 对数似然；gaussian 的 φ 取极大似然估计
+func (g *glmProblem) logLik(mu []float64, scale float64) float64 {
+	if _, ok := g.fam.(gaussianFamily); ok {
+		scale = g.deviance(mu) / float64(len(mu))
+	}
+	ll := 0.0
+	for i, y := range g.y {
+		ll += g.fam.logLik(y, mu[i], g.w[i], scale)
+	}
+	return ll
+}
+
+// 负二项 α 的交替估计，以泊松拟合为起点
+func (g *glmProblem) fitNegBinAlpha() (glmFit, float64, error) {
+	g.fam = poissonFamily{}
+	fit, err := g.irls(nil)
+	if err != nil {
+		return glmFit{}, 0, err
+	}
+	alpha := 0.0
+	for it := 0; it < glmNBMaxIter; it++ {
+		next := g.profileAlpha(fit.mu)
+		g.fam = negBinFamily{alpha: next}
+		if fit, err = g.irls(fit.beta); err != nil {
+			return glmFit{}, 0, err
+		}
+		done := alpha > 0 && math.Abs(math.Log(next/alpha)) < glmNBTol
+		alpha = next
+		if done {
+			break
+		}
+	}
+	return fit, alpha, nil
+}
+
+// 固定 μ，在 ln α ∈ [ln 1e-8, ln 1e4] 上黄金分割最大化负二项对数似然
+func (g *glmProblem) profileAlpha(mu []float64) float64 {
+	ll := func(t float64) float64 {
+		f := negBinFamily{alpha: math.Exp(t)}
+		s := 0.0
+		for i, y := range g.y {
+			s += f.logLik(y, mu[i], g.w[i], 1)
+		}
+		return s
+	}
+	const gr = 0.6180339887498949
+	lo, hi := math.Log(1e-8), math.Log(1e4)
+	a, b := hi-gr*(hi-lo), lo+gr*(hi-lo)
+	fa, fb := ll(a), ll(b)
+	for hi-lo > 1e-8 {
+		if fa < fb {
+			lo, a, fa = a, b, fb
+			b = lo + gr*(hi-lo)
+			fb = ll(b)
+		} else {
+			hi, b, fb = b, a, fa
+			a = hi - gr*(hi-lo)
+			fa = ll(a)
+		}
+	}
+	return math.Exp((lo + hi) / 2)
+}
+
+func (g *glmProblem) model(fit glmFit, covOpts CovOptions, estAlpha bool) (GLMModel, error) {
+	n, k := g.X.Dims()
+	rank := fit.sol.rank
+	m := GLMModel{
+		Mu:        fit.mu,
+		Deviance:  fit.dev,
+		NIter:     fit.nIter,
+		Converged: fit.converged,
+	}
+	resid := make([]float64, n)
+	score := make([]float64, n) // √ω_i·g'(μ_i)(y_i - μ_i)
+	for i, y := range g.y {
+		mu := fit.mu[i]
+		v, d := g.fam.variance(mu), g.link.deriv(mu)
+		resid[i] = y - mu
+		m.PearsonChi2 += g.w[i] * resid[i] * resid[i] / v
+		score[i] = math.Sqrt(g.w[i]/(v*d*d)) * d * resid[i]
+	}
+	scale := 1.0
+	if g.fam.estimateScale() {
+		scale = m.PearsonChi2 / float64(n-rank)
+	}
+	m.LogLik = g.logLik(fit.mu, scale)
+
+	// 空模型
+	nullK := 0
+	nullMu := make([]float64, n)
+	if hasConstColumn(g.X) {
+		nullK = 1
+		null := *g
+		null.X = mat.NewDense(n, 1, nil)
+		for i := 0; i < n; i++ {
+			null.X.Set(i, 0, 1)
+		}
+		nf, err := null.irls(nil)
+		if err != nil {
+			return GLMModel{}, err
+		}
+		nullMu = nf.mu
+	} else {
+		for i := range nullMu {
+			nullMu[i] = g.link.inverse(g.off[i])
+		}
+	}
+	m.NullDeviance = g.deviance(nullMu)
+	m.LLNull = g.logLik(nullMu, scale)
+
+	nParams := float64(rank)
+	if estAlpha {
+		nParams++
+	}
+	coeffs := make([]float64, k)
+	for j := range coeffs {
+		coeffs[j] = fit.beta.AtVec(j)
+	}
+	m.MultiLinearModel = MultiLinearModel{
+		Coeffs:  coeffs,
+		Resids:  resid,
+		AIC:     -2*m.LogLik + 2*nParams,
+		BIC:     -2*m.LogLik + nParams*math.Log(float64(n)),
+		Sigma2:  scale,
+		Rank:    rank,
+		CondNum: fit.sol.cond,
+		Solver:  fit.sol.solver,
+	}
+	if m.NullDeviance > 0 {
+		m.RSquared = 1 - m.Deviance/m.NullDeviance
+		m.AdjRSquared = 1 - (m.Deviance/float64(n-rank))/(m.NullDeviance/float64(n-nullK))
+	}
+	m.applyCov(scaledCov(scale, fit.sol.invXTX), COV_NONROBUST)
+	if covOpts.Type != COV_NONROBUST {
+		cov, err := RobustCov(fit.xw, score, covOpts)
+		if err != nil {
+			return GLMModel{}, err
+		}
+		m.applyCov(cov, covOpts.Type)
+	}
+	return m, nil
+}
@@ -0,0 +1,187 @@
+package ols
+
+import (
+	"math"
+	"math/rand/v2"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// 计数数据: μ_i = e_i·exp(0.3 + 0.5·x1 - 0.2·x2)，e_i 为暴露量；alpha > 0 时为 NB2(Gamma-Poisson 混合)
+func countData(n int, alpha float64) (X [][]float64, Y, offset []float64) {
+	src := rand.NewPCG(5, 9)
+	r := rand.New(src)
+	X = make([][]float64, n)
+	Y = make([]float64, n)
+	offset = make([]float64, n)
+	for i := range X {
+		X[i] = []float64{r.NormFloat64(), r.NormFloat64()}
+		offset[i] = math.Log(0.5 + 2*r.Float64())
+		mu := math.Exp(offset[i] + 0.3 + 0.5*X[i][0] - 0.2*X[i][1])
+		lam := mu
+		if alpha > 0 {
+			lam = distuv.Gamma{Alpha: 1 / alpha, Beta: 1 / (alpha * mu), Src: src}.Rand()
+		}
+		Y[i] = distuv.Poisson{Lambda: lam, Src: src}.Rand()
+	}
+	return X, Y, offset
+}
+
+func assertNear(t *testing.T, name string, got, want []float64, se []float64, z float64) {
+	t.Helper()
+	for j := range want {
+		if math.Abs(got[j]-want[j]) > z*se[j] {
+			t.Errorf("%s[%d] = %v, want %v ± %v", name, j, got[j], want[j], z*se[j])
+		}
+	}
+}
+
+func TestGLMGaussianMatchesOLS(t *testing.T) {
+	X, Y := rollingData(300)
+	ols, _ := MultiRegression(X, Y, true)
+	glm, err := GLMRegression(X, Y, true, DefaultGLMOptions(FAMILY_GAUSSIAN))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertClose(t, "coeffs", glm.Coeffs, ols.Coeffs, 1e-9)
+	assertClose(t, "se", glm.SE, ols.SE, 1e-9)
+	if math.Abs(glm.RSquared-ols.RSquared) > 1e-9 || math.Abs(glm.AIC-ols.AIC) > 1e-6 || math.Abs(glm.Sigma2-ols.Sigma2) > 1e-9 {
+		t.Errorf("R2 %v/%v AIC %v/%v sigma2 %v/%v", glm.RSquared, ols.RSquared, glm.AIC, ols.AIC, glm.Sigma2, ols.Sigma2)
+	}
+
+	// 稳健口径与 MultiRegressionRobust 一致
+	opts := DefaultGLMOptions(FAMILY_GAUSSIAN)
+	for _, ct := range []CovType{COV_HC1, COV_HC3} {
+		opts.Cov = CovOptions{Type: ct}
+		glm, _ = GLMRegression(X, Y, true, opts)
+		rob, _ := MultiRegressionRobust(X, Y, true, opts.Cov)
+		assertClose(t, ct.String(), glm.SE, rob.SE, 1e-9)
+	}
+}
+
+func TestGLMPoissonOffset(t *testing.T) {
+	X, Y, off := countData(3000, 0)
+	opts := DefaultGLMOptions(FAMILY_POISSON)
+	opts.Offset = off
+	m, err := GLMRegression(X, Y, true, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !m.Converged || m.Sigma2 != 1 {
+		t.Fatalf("converged=%v scale=%v", m.Converged, m.Sigma2)
+	}
+	assertNear(t, "coef", m.Coeffs, []float64{0.3, 0.5, -0.2}, m.SE, 4)
+	// 得分方程 X'(y - μ) = 0(log 为泊松的典则连接)；按偏差收敛，得分残差约为 √Tol 量级
+	for j := 0; j < 3; j++ {
+		s := 0.0
+		for i := range Y {
+			x := 1.0
+			if j > 0 {
+				x = X[i][j-1]
+			}
+			s += x * m.Resids[i]
+		}
+		if math.Abs(s) > 1e-4 {
+			t.Errorf("score %d = %v", j, s)
+		}
+	}
+	// 正确设定时 Pearson χ²/df ≈ 1
+	if d := m.PearsonChi2 / float64(len(Y)-3); d < 0.9 || d > 1.1 {
+		t.Errorf("pearson dispersion %v", d)
+	}
+	if m.RSquared <= 0 || m.RSquared >= 1 || m.Deviance >= m.NullDeviance {
+		t.Errorf("deviance %v null %v", m.Deviance, m.NullDeviance)
+	}
+	p, _ := m.Predict(X[7], off[7])
+	if math.Abs(p-m.Mu[7]) > 1e-12 {
+		t.Errorf("Predict %v vs Mu %v", p, m.Mu[7])
+	}
+
+	// 整数权重等价于重复样本
+	w := make([]float64, 200)
+	var xr [][]float64
+	var yr, or []float64
+	for i := range w {
+		w[i] = float64(1 + i%3)
+		for c := 0; c < int(w[i]); c++ {
+			xr, yr, or = append(xr, X[i]), append(yr, Y[i]), append(or, off[i])
+		}
+	}
+	opts.Offset, opts.Weights = off[:200], w
+	mw, _ := GLMRegression(X[:200], Y[:200], true, opts)
+	opts.Offset, opts.Weights = or, nil
+	mr, _ := GLMRegression(xr, yr, true, opts)
+	assertClose(t, "weighted coeffs", mw.Coeffs, mr.Coeffs, 1e-8)
+	assertClose(t, "weighted se", mw.SE, mr.SE, 1e-8)
+	if math.Abs(mw.Deviance-mr.Deviance) > 1e-8 || math.Abs(mw.LogLik-mr.LogLik) > 1e-8 {
+		t.Errorf("weighted deviance %v/%v loglik %v/%v", mw.Deviance, mr.Deviance, mw.LogLik, mr.LogLik)
+	}
+}
+
+func TestGLMNegBin(t *testing.T) {
+	X, Y, off := countData(4000, 0.5)
+	opts := DefaultGLMOptions(FAMILY_NEGBIN)
+	opts.Offset = off
+	nb, err := GLMRegression(X, Y, true, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !nb.Converged || math.Abs(nb.Alpha-0.5) > 0.1 {
+		t.Fatalf("converged=%v alpha=%v", nb.Converged, nb.Alpha)
+	}
+	assertNear(t, "coef", nb.Coeffs, []float64{0.3, 0.5, -0.2}, nb.SE, 4)
+
+	// 给定 α 时不再估计，AIC 少计一个参数
+	opts.Alpha = nb.Alpha
+	fixed, _ := GLMRegression(X, Y, true, opts)
+	assertClose(t, "fixed alpha", fixed.Coeffs, nb.Coeffs, 1e-6)
+	if math.Abs(nb.AIC-fixed.AIC-2) > 1e-6 {
+		t.Errorf("AIC estimated %v fixed %v", nb.AIC, fixed.AIC)
+	}
+
+	// 过度离散下泊松的非稳健 SE 偏小，稳健 SE 修正之；NB 的 AIC 更优
+	popts := DefaultGLMOptions(FAMILY_POISSON)
+	popts.Offset = off
+	pois, _ := GLMRegression(X, Y, true, popts)
+	popts.Cov = CovOptions{Type: COV_HC0}
+	poisRobust, _ := GLMRegression(X, Y, true, popts)
+	for j := range pois.SE {
+		if poisRobust.SE[j] < 1.2*pois.SE[j] {
+			t.Errorf("robust SE %d: %v vs %v", j, poisRobust.SE[j], pois.SE[j])
+		}
+	}
+	if nb.AIC >= pois.AIC {
+		t.Errorf("NB AIC %v >= Poisson AIC %v", nb.AIC, pois.AIC)
+	}
+}
+
+func TestGLMGamma(t *testing.T) {
+	// 价差: y ~ Gamma(形状 2, 均值 exp(-1 + 0.4·x))，φ = 1/形状
+	src := rand.NewPCG(3, 4)
+	r := rand.New(src)
+	n := 3000
+	matX := mat.NewDense(n, 2, nil)
+	matY := mat.NewVecDense(n, nil)
+	for i := 0; i < n; i++ {
+		x := r.NormFloat64()
+		matX.Set(i, 0, 1)
+		matX.Set(i, 1, x)
+		mu := math.Exp(-1 + 0.4*x)
+		matY.SetVec(i, distuv.Gamma{Alpha: 2, Beta: 2 / mu, Src: src}.Rand())
+	}
+	m, err := GLMRegressionMat(matX, matY, DefaultGLMOptions(FAMILY_GAMMA))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertNear(t, "coef", m.Coeffs, []float64{-1, 0.4}, m.SE, 4)
+	if math.Abs(m.Sigma2-0.5) > 0.05 {
+		t.Errorf("scale %v, want 0.5", m.Sigma2)
+	}
+
+	matY.SetVec(0, 0)
+	if _, err = GLMRegressionMat(matX, matY, DefaultGLMOptions(FAMILY_GAMMA)); err == nil {
+		t.Error("expected error for y <= 0")
+	}
+}
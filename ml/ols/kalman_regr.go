@@ -0,0 +1,432 @@
+// Kalman 滤波动态线性回归(系数随机游走)
+//
+//	y_t = x_t'β_t + ε_t,        ε_t ~ N(0, σ²_ε)
+//	β_{t+1} = β_t + η_t,        η_t ~ N(0, diag(q_1..q_k))
+//
+// 初值近似扩散: β_0 = 0，P_0 = κ·diag(1/mean(x_j²))，κ = 1e6，前 k 个有效观测不计入似然
+// q 全为 0 时退化为递推 OLS，末端滤波系数即全样本静态回归系数
+// MLE 时把 σ²_ε 从似然中集中掉，只对 ψ_j = q_j/σ²_ε 的对数做 Nelder-Mead 搜索
+// y 或 x 含 NaN/Inf 的时点只做预测步(视为缺失)
+package ols
+
+import (
+	"fmt"
+	"math"
+	"ofeisInfra/infra/errorx"
+	"ofeisInfra/infra/errorx/errCode"
+
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/optimize"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+const (
+	kalmanDiffuseKappa = 1e6
+	kalmanLogPsiMin    = -30.0 // ψ 下限 e^-30，视为 0
+	kalmanLogPsiMax    = 10.0
+)
+
+type KalmanOptions struct {
+	StateVar       []float64 // 固定的状态噪声方差 q_j(长度 k，含常数项)，nil 时由 MLE 估计
+	ObsVar         float64   // 固定的观测噪声方差，StateVar 非 nil 时必须 > 0
+	SharedStateVar bool      // MLE 时所有系数共用一个 ψ
+}
+
+type KalmanRegrModel struct {
+	WithConst  bool
+	ObsVar     float64     // σ²_ε
+	StateVar   []float64   // q_j
+	Filtered   [][]float64 // β_{t|t}，[t][k]
+	FilteredSE [][]float64
+	Smoothed   [][]float64 // RTS 平滑 β_{t|n}
+	SmoothedSE [][]float64
+	Pred       []float64 // 一步预测 ŷ_{t|t-1} = x_t'β_{t|t-1}，x 缺失时为 NaN
+	PredVar    []float64 // 一步预测方差 F_t = x_t'P_{t|t-1}x_t + σ²_ε
+	Resids     []float64 // 新息 y_t - ŷ_{t|t-1}，缺失时为 NaN
+	LogLik     float64
+	AIC        float64
+	BIC        float64
+	NObs       int // 计入似然的观测数(有效观测扣除扩散期 k 个)
+
+	filtCov   []*mat.SymDense // P_{t|t}
+	smoothCov []*mat.SymDense // P_{t|n}
+}
+
+// 动态回归(行数据接口)，withConst 时在最左侧添加常数列，与 MultiRegression 约定一致
+func KalmanRegression(X [][]float64, Y []float64, withConst bool, opts KalmanOptions) (*KalmanRegrModel, error) {
+	n := len(Y)
+	if n == 0 || len(X) == 0 {
+		return nil, errorx.New(errCode.EMPTY_VALUE, "输入数据为空")
+	}
+	if n != len(X) {
+		return nil, errorx.New(errCode.INVALID_VALUE, "数据长度不匹配")
+	}
+	if withConst {
+		X = addConstantColumn(X)
+	}
+	k := len(X[0])
+	matX := mat.NewDense(n, k, nil)
+	for i := 0; i < n; i++ {
+		if len(X[i]) != k {
+			return nil, errorx.New(errCode.INVALID_VALUE, "X 各行长度不一致")
+		}
+		matX.SetRow(i, X[i])
+	}
+	m, err := KalmanRegressionMat(matX, mat.NewVecDense(n, append([]float64(nil), Y...)), opts)
+	if err != nil {
+		return nil, err
+	}
+	m.WithConst = withConst
+	return m, nil
+}
+
+// 动态回归，matX 需自带常数列
+func KalmanRegressionMat(matX *mat.Dense, matY *mat.VecDense, opts KalmanOptions) (*KalmanRegrModel, error) {
+	n, k := matX.Dims()
+	if matY.Len() != n {
+		return nil, errorx.New(errCode.INVALID_VALUE, "数据长度不匹配")
+	}
+	if opts.StateVar != nil {
+		if len(opts.StateVar) != k {
+			return nil, errorx.New(errCode.INVALID_VALUE, fmt.Sprintf("StateVar 长度 %d 与参数数 %d 不符", len(opts.StateVar), k))
+		}
+		for _, q := range opts.StateVar {
+			if !(q >= 0) || math.IsInf(q, 1) {
+				return nil, errorx.New(errCode.INVALID_VALUE, "状态噪声方差须为非负有限值")
+			}
+		}
+		if !(opts.ObsVar > 0) || math.IsInf(opts.ObsVar, 1) {
+			return nil, errorx.New(errCode.INVALID_VALUE, "给定 StateVar 时 ObsVar 必须 > 0")
+		}
+	}
+
+	kf := newKalmanRegr(matX, matY)
+	if kf.nValid <= k+1 {
+		return nil, errorx.New(errCode.INVALID_VALUE, fmt.Sprintf("有效样本数 %d 须大于参数数 %d + 1", kf.nValid, k))
+	}
+
+	model := &KalmanRegrModel{}
+	var q []float64
+	var h float64
+	nParams := 0
+	if opts.StateVar != nil {
+		q, h = append([]float64(nil), opts.StateVar...), opts.ObsVar
+	} else {
+		// 集中似然: h = 1，q = ψ，滤波后按 σ̂² 还原
+		psi := kf.estimatePsi(opts.SharedStateVar)
+		pass := kf.filter(psi, 1, false)
+		sigma2 := pass.sumSq / float64(pass.nLik)
+		q = make([]float64, k)
+		for j := range q {
+			q[j] = psi[j] * sigma2
+		}
+		h = sigma2
+		nParams = len(psi)
+		if opts.SharedStateVar {
+			nParams = 1
+		}
+		nParams++ // σ²_ε
+	}
+
+	pass := kf.filter(q, h, true)
+	model.ObsVar, model.StateVar = h, q
+	model.NObs = pass.nLik
+	model.LogLik = -0.5 * (float64(pass.nLik)*math.Log(2*math.Pi) + pass.sumLog + pass.sumSq)
+	model.AIC = -2*model.LogLik + 2*float64(nParams)
+	model.BIC = -2*model.LogLik + float64(nParams)*math.Log(float64(pass.nLik))
+	model.Pred, model.PredVar, model.Resids = pass.pred, pass.F, pass.v
+	model.Filtered, model.filtCov = pass.aFilt, pass.pFilt
+	model.FilteredSE = diagSE(pass.pFilt)
+	if err := model.smooth(pass); err != nil {
+		return nil, err
+	}
+	model.SmoothedSE = diagSE(model.smoothCov)
+	return model, nil
+}
+
+// t 时点的系数推断，整理为 MultiLinearModel 以便与静态回归互换
+// smoothed 为 true 时取 RTS 平滑结果，否则取滤波结果；p值按正态分布，Resids 为全样本新息
+func (m *KalmanRegrModel) At(t int, smoothed bool) (MultiLinearModel, error) {
+	if t < 0 || t >= len(m.Filtered) {
+		return MultiLinearModel{}, errorx.New(errCode.INVALID_VALUE, fmt.Sprintf("时点 %d 越界", t))
+	}
+	beta, cov := m.Filtered[t], m.filtCov[t]
+	if smoothed {
+		beta, cov = m.Smoothed[t], m.smoothCov[t]
+	}
+	k := len(beta)
+	out := MultiLinearModel{
+		Coeffs:  append([]float64(nil), beta...),
+		SE:      make([]float64, k),
+		TStats:  make([]float64, k),
+		PValues: make([]float64, k),
+		Resids:  m.Resids,
+		AIC:     m.AIC,
+		BIC:     m.BIC,
+		Sigma2:  m.ObsVar,
+		Cov:     make([][]float64, k),
+		CovType: COV_NONROBUST,
+		Rank:    k,
+		CondNum: math.NaN(),
+	}
+	for i := 0; i < k; i++ {
+		out.Cov[i] = make([]float64, k)
+		for j := 0; j < k; j++ {
+			out.Cov[i][j] = cov.At(i, j)
+		}
+		out.SE[i] = math.Sqrt(cov.At(i, i))
+		out.TStats[i] = beta[i] / out.SE[i]
+		out.PValues[i] = 2 * distuv.UnitNormal.Survival(math.Abs(out.TStats[i]))
+	}
+	return out, nil
+}
+
+// 样本外一步预测: β_{n+1|n} = β_{n|n}，P_{n+1|n} = P_{n|n} + Q
+// x 不含常数项(WithConst 时自动添加)
+func (m *KalmanRegrModel) Predict(x []float64) (mean, variance float64, err error) {
+	if m.WithConst {
+		x = append([]float64{1}, x...)
+	}
+	last := len(m.Filtered) - 1
+	beta, P := m.Filtered[last], m.filtCov[last]
+	if len(x) != len(beta) {
+		return math.NaN(), math.NaN(), errorx.New(errCode.INVALID_VALUE, "x 长度与参数数不符")
+	}
+	variance = m.ObsVar
+	for i := range x {
+		mean += x[i] * beta[i]
+		variance += x[i] * x[i] * m.StateVar[i]
+		for j := range x {
+			variance += x[i] * P.At(i, j) * x[j]
+		}
+	}
+	return mean, variance, nil
+}
+
+type kalmanRegr struct {
+	X      *mat.Dense
+	Y      *mat.VecDense
+	n, k   int
+	valid  []bool
+	nValid int
+	p0     []float64 // 扩散初值对角元
+}
+
+type kalmanRegrPass struct {
+	sumLog, sumSq float64 // Σ lnF_t, Σ v_t²/F_t (扩散期之后)
+	nLik          int
+	pred, F, v    []float64
+	aPred, aFilt  [][]float64
+	pPred, pFilt  []*mat.SymDense
+}
+
+func newKalmanRegr(matX *mat.Dense, matY *mat.VecDense) *kalmanRegr {
+	n, k := matX.Dims()
+	kf := &kalmanRegr{X: matX, Y: matY, n: n, k: k, valid: make([]bool, n), p0: make([]float64, k)}
+	for t := 0; t < n; t++ {
+		ok := isFinite(matY.AtVec(t))
+		for j := 0; j < k; j++ {
+			ok = ok && isFinite(matX.At(t, j))
+		}
+		kf.valid[t] = ok
+		if !ok {
+			continue
+		}
+		kf.nValid++
+		for j := 0; j < k; j++ {
+			kf.p0[j] += matX.At(t, j) * matX.At(t, j)
+		}
+	}
+	for j := range kf.p0 {
+		ms := kf.p0[j] / math.Max(1, float64(kf.nValid))
+		if ms <= 0 {
+			ms = 1
+		}
+		kf.p0[j] = kalmanDiffuseKappa / ms
+	}
+	return kf
+}
+
+// 滤波; store 为 false 时只累计似然(MLE 内循环)
+func (kf *kalmanRegr) filter(q []float64, h float64, store bool) kalmanRegrPass {
+	n, k := kf.n, kf.k
+	var out kalmanRegrPass
+	if store {
+		out.pred, out.F, out.v = make([]float64, n), make([]float64, n), make([]float64, n)
+		out.aPred, out.aFilt = make([][]float64, n), make([][]float64, n)
+		out.pPred, out.pFilt = make([]*mat.SymDense, n), make([]*mat.SymDense, n)
+	}
+	a := make([]float64, k)
+	P := mat.NewSymDense(k, nil)
+	for j := 0; j < k; j++ {
+		P.SetSym(j, j, kf.p0[j])
+	}
+	x := make([]float64, k)
+	Px := make([]float64, k)
+	seen := 0
+	for t := 0; t < n; t++ {
+		// 预测步: t>0 时 P_{t|t-1} = P_{t-1|t-1} + Q
+		if t > 0 {
+			for j := 0; j < k; j++ {
+				P.SetSym(j, j, P.At(j, j)+q[j])
+			}
+		}
+		if store {
+			out.aPred[t] = append([]float64(nil), a...)
+			out.pPred[t] = mat.NewSymDense(k, nil)
+			out.pPred[t].CopySym(P)
+			out.pred[t], out.F[t], out.v[t] = math.NaN(), math.NaN(), math.NaN()
+		}
+
+		xOK := true
+		for j := 0; j < k; j++ {
+			x[j] = kf.X.At(t, j)
+			xOK = xOK && isFinite(x[j])
+		}
+		if xOK {
+			yHat, F := 0.0, h
+			for i := 0; i < k; i++ {
+				yHat += x[i] * a[i]
+				s := 0.0
+				for j := 0; j < k; j++ {
+					s += P.At(i, j) * x[j]
+				}
+				Px[i] = s
+				F += x[i] * s
+			}
+			if store {
+				out.pred[t], out.F[t] = yHat, F
+			}
+			if kf.valid[t] {
+				v := kf.Y.AtVec(t) - yHat
+				// 更新步: a += Px v/F, P -= Px Px'/F
+				for i := 0; i < k; i++ {
+					a[i] += Px[i] * v / F
+					for j := i; j < k; j++ {
+						P.SetSym(i, j, P.At(i, j)-Px[i]*Px[j]/F)
+					}
+				}
+				if seen >= k {
+					out.sumLog += math.Log(F)
+					out.sumSq += v * v / F
+					out.nLik++
+				}
+				seen++
+				if store {
+					out.v[t] = v
+				}
+			}
+		}
+		if store {
+			out.aFilt[t] = append([]float64(nil), a...)
+			out.pFilt[t] = mat.NewSymDense(k, nil)
+			out.pFilt[t].CopySym(P)
+		}
+	}
+	return out
+}
+
+// 集中似然下估计 ψ: 先在公共 ψ 的对数网格上粗搜，再 Nelder-Mead 细化
+func (kf *kalmanRegr) estimatePsi(shared bool) []float64 {
+	dim := kf.k
+	if shared {
+		dim = 1
+	}
+	expand := func(z []float64) []float64 {
+		psi := make([]float64, kf.k)
+		for j := range psi {
+			v := z[0]
+			if !shared {
+				v = z[j]
+			}
+			psi[j] = math.Exp(math.Max(kalmanLogPsiMin, math.Min(kalmanLogPsiMax, v)))
+		}
+		return psi
+	}
+	obj := func(z []float64) float64 {
+		pass := kf.filter(expand(z), 1, false)
+		if pass.nLik == 0 || !(pass.sumSq > 0) {
+			return math.Inf(1)
+		}
+		// 集中负对数似然(去掉常数)
+		nl := float64(pass.nLik)
+		return 0.5 * (nl*math.Log(pass.sumSq/nl) + pass.sumLog)
+	}
+
+	z0 := make([]float64, dim)
+	bestF := math.Inf(1)
+	for lp := -20.0; lp <= 2; lp += 2 {
+		z := make([]float64, dim)
+		for j := range z {
+			z[j] = lp
+		}
+		if f := obj(z); f < bestF {
+			bestF, z0 = f, z
+		}
+	}
+	if res, _ := optimize.Minimize(optimize.Problem{Func: obj}, z0, nil, &optimize.NelderMead{}); res != nil && res.F < bestF {
+		z0 = res.X
+	}
+	return expand(z0)
+}
+
+// RTS 平滑(随机游走转移矩阵为 I):
+// J_t = P_{t|t} P_{t+1|t}^{-1}, β_{t|n} = β_{t|t} + J_t(β_{t+1|n} - β_{t+1|t}), P_{t|n} = P_{t|t} + J_t(P_{t+1|n} - P_{t+1|t})J_t'
+func (m *KalmanRegrModel) smooth(pass kalmanRegrPass) error {
+	n := len(pass.aFilt)
+	k := len(pass.aFilt[0])
+	m.Smoothed = make([][]float64, n)
+	m.smoothCov = make([]*mat.SymDense, n)
+	m.Smoothed[n-1] = append([]float64(nil), pass.aFilt[n-1]...)
+	m.smoothCov[n-1] = mat.NewSymDense(k, nil)
+	m.smoothCov[n-1].CopySym(pass.pFilt[n-1])
+
+	var chol mat.Cholesky
+	var J, diffP, tmp mat.Dense
+	for t := n - 2; t >= 0; t-- {
+		if !chol.Factorize(pass.pPred[t+1]) {
+			return errorx.New(errCode.INVALID_VALUE, fmt.Sprintf("RTS 平滑: t=%d 预测协方差非正定", t+1))
+		}
+		// J' = P_{t+1|t}^{-1} P_{t|t}
+		var Jt mat.Dense
+		if err := chol.SolveTo(&Jt, pass.pFilt[t]); err != nil {
+			return errorx.New(errCode.INVALID_VALUE, fmt.Sprintf("RTS 平滑: t=%d 求解失败", t))
+		}
+		J.CloneFrom(Jt.T())
+
+		dA := mat.NewVecDense(k, nil)
+		for j := 0; j < k; j++ {
+			dA.SetVec(j, m.Smoothed[t+1][j]-pass.aPred[t+1][j])
+		}
+		var corr mat.VecDense
+		corr.MulVec(&J, dA)
+		m.Smoothed[t] = make([]float64, k)
+		for j := 0; j < k; j++ {
+			m.Smoothed[t][j] = pass.aFilt[t][j] + corr.AtVec(j)
+		}
+
+		diffP.Sub(m.smoothCov[t+1], pass.pPred[t+1])
+		tmp.Mul(&J, &diffP)
+		diffP.Mul(&tmp, J.T())
+		Ps := mat.NewSymDense(k, nil)
+		for i := 0; i < k; i++ {
+			for j := i; j < k; j++ {
+				Ps.SetSym(i, j, pass.pFilt[t].At(i, j)+0.5*(diffP.At(i, j)+diffP.At(j, i)))
+			}
+		}
+		m.smoothCov[t] = Ps
+	}
+	return nil
+}
+
+func diagSE(covs []*mat.SymDense) [][]float64 {
+	out := make([][]float64, len(covs))
+	for t, P := range covs {
+		k := P.SymmetricDim()
+		out[t] = make([]float64, k)
+		for j := 0; j < k; j++ {
+			out[t][j] = math.Sqrt(math.Max(P.At(j, j), 0))
+		}
+	}
+	return out
+}
@@ -0,0 +1,70 @@
+package ols
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// 状态噪声为 0 时末端滤波系数与静态 OLS 一致
+func TestKalmanStaticMatchesOLS(t *testing.T) {
+	X, Y := rollingData(300)
+	m, err := KalmanRegression(X, Y, true, KalmanOptions{StateVar: []float64{0, 0, 0}, ObsVar: 0.09})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ref, _ := MultiRegression(X, Y, true)
+	last, _ := m.At(len(Y)-1, false)
+	for j := range ref.Coeffs {
+		if math.Abs(last.Coeffs[j]-ref.Coeffs[j]) > 1e-5 {
+			t.Errorf("coef %d: %v want %v", j, last.Coeffs[j], ref.Coeffs[j])
+		}
+		// 平滑系数在 q=0 时处处等于全样本估计
+		if math.Abs(m.Smoothed[10][j]-ref.Coeffs[j]) > 1e-5 {
+			t.Errorf("smoothed[10] coef %d: %v want %v", j, m.Smoothed[10][j], ref.Coeffs[j])
+		}
+	}
+}
+
+// 随机游走 β 的 MLE: 方差量级可恢复，平滑路径比滤波路径更贴近真值
+func TestKalmanMLE(t *testing.T) {
+	r := rand.New(rand.NewSource(3))
+	const n = 1500
+	X := make([][]float64, n)
+	Y := make([]float64, n)
+	beta := make([]float64, n)
+	b := 1.0
+	for i := 0; i < n; i++ {
+		b += 0.02 * r.NormFloat64()
+		beta[i] = b
+		x := r.NormFloat64()
+		X[i] = []float64{x}
+		Y[i] = b*x + 0.1*r.NormFloat64()
+	}
+	Y[700] = math.NaN()
+	m, err := KalmanRegression(X, Y, false, KalmanOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q := m.StateVar[0]; q < 0.0002 || q > 0.0008 {
+		t.Errorf("state var %v, true 0.0004", q)
+	}
+	if h := m.ObsVar; math.Abs(h-0.01) > 0.002 {
+		t.Errorf("obs var %v, true 0.01", h)
+	}
+	var errF, errS float64
+	for i := 100; i < n; i++ {
+		errF += math.Pow(m.Filtered[i][0]-beta[i], 2)
+		errS += math.Pow(m.Smoothed[i][0]-beta[i], 2)
+	}
+	if errS >= errF {
+		t.Errorf("smoothed mse %v >= filtered mse %v", errS, errF)
+	}
+	if !math.IsNaN(m.Resids[700]) || math.IsNaN(m.Pred[700]) {
+		t.Error("缺失 y 时应有预测、无新息")
+	}
+	mean, v, err := m.Predict([]float64{1})
+	if err != nil || math.Abs(mean-m.Filtered[n-1][0]) > 1e-12 || v <= m.ObsVar {
+		t.Errorf("predict %v %v %v", mean, v, err)
+	}
+}
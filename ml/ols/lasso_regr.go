@@ -2,8 +2,6 @@ package ols
 
 import (
 	"math"
-	"ofeisInfra/infra/errorx"
-	"ofeisInfra/infra/errorx/errCode"
 
 	"gonum.org/v1/gonum/mat"
 )
@@ -123,9 +121,10 @@ func computePred(X *mat.Dense, beta []float64) *mat.VecDense {
 	return yhat
 }
 
-// -------------------------- 线性 LASSO（与 sklearn 一致） --------------------------
-// 目标: (1/(2n))||y - Xβ||^2 + α||β||_1，截距不惩罚
-func lassoLinearCD(matX *mat.Dense, matY *mat.VecDense, alpha float64, withIntercept bool, tol float64, maxIter int) ([]float64, error) {
+// -------------------------- 线性 LASSO / Elastic Net（与 sklearn 一致） --------------------------
+// 目标: (1/(2n))||y - Xβ||^2 + α·l1Ratio·||β||_1 + (α(1-l1Ratio)/2)||β||^2，截距不惩罚
+// l1Ratio = 1 即 LASSO
+func elasticNetLinearCD(matX *mat.Dense, matY *mat.VecDense, alpha, l1Ratio float64, withIntercept bool, tol float64, maxIter int) ([]float64, error) {
 	X := mat.DenseCopyOf(matX)
 	Y := mat.NewVecDense(matY.Len(), nil)
 	Y.CopyVec(matY)
@@ -186,8 +185,8 @@ func lassoLinearCD(matX *mat.Dense, matY *mat.VecDense, alpha float64, withInter
 				rho += X.At(i, j) * r
 			}
 			rho /= float64(n)
-			// 更新 β_j ← S(rho, α) / gj[j]
-			newBj := softThreshold(rho, alpha) / gj[j]
+			// 更新 β_j ← S(rho, α·l1Ratio) / (gj[j] + α(1-l1Ratio))
+			newBj := softThreshold(rho, alpha*l1Ratio) / (gj[j] + alpha*(1-l1Ratio))
 			if math.IsNaN(newBj) || math.IsInf(newBj, 0) {
 				newBj = 0
 			}
@@ -203,10 +202,11 @@ func lassoLinearCD(matX *mat.Dense, matY *mat.VecDense, alpha float64, withInter
 	return beta, nil
 }
 
-// -------------------------- 逻辑 LASSO（与 sklearn 一致） --------------------------
-// 目标: (1/n)Σ log(1+e^{xβ}) - y xβ + α||β||_1，截距不惩罚
+// -------------------------- 逻辑 LASSO / Elastic Net（与 sklearn 一致） --------------------------
+// 目标: (1/n)Σ log(1+e^{xβ}) - y xβ + α·l1Ratio·||β||_1 + (α(1-l1Ratio)/2)||β||^2，截距不惩罚
 // 使用近端梯度 (ISTA) + 回溯线搜索；X 标准化、截距不惩罚。
-func lassoLogisticISTA(matX *mat.Dense, matY *mat.VecDense, alpha float64, withIntercept bool, tol float64, maxIter int) ([]float64, error) {
+// Elastic Net 的近端算子: prox(z) = S(z, step·α·l1Ratio) / (1 + step·α(1-l1Ratio))
+func elasticNetLogisticISTA(matX *mat.Dense, matY *mat.VecDense, alpha, l1Ratio float64, withIntercept bool, tol float64, maxIter int) ([]float64, error) {
 	X := mat.DenseCopyOf(matX)
 	Y := mat.NewVecDense(matY.Len(), nil)
 	Y.CopyVec(matY)
@@ -243,13 +243,7 @@ func lassoLogisticISTA(matX *mat.Dense, matY *mat.VecDense, alpha float64, withI
 			for j := 0; j < p; j++ {
 				s += X.At(i, j) * beta[j]
 			}
-			if s > 20 {
-				pvec[i] = 1.0
-			} else if s < -20 {
-				pvec[i] = 0.0
-			} else {
-				pvec[i] = 1.0 / (1.0 + math.Exp(-s))
-			}
+			pvec[i] = sigmoid(s)
 		}
 		// 计算梯度 g = (1/n) X^T (p - y)
 		g := make([]float64, p)
@@ -265,18 +259,18 @@ func lassoLogisticISTA(matX *mat.Dense, matY *mat.VecDense, alpha float64, withI
 		if withIntercept {
 			newBeta[0] = beta[0] - step*g[0]
 		}
-		// 其他坐标: 近端算子 soft-threshold(step*alpha)
+		// 其他坐标: 近端算子
 		start := 0
 		if withIntercept {
 			start = 1
 		}
 		for j := start; j < p; j++ {
-			newBeta[j] = softThreshold(beta[j]-step*g[j], step*alpha)
+			newBeta[j] = elasticNetProx(beta[j]-step*g[j], step, alpha, l1Ratio)
 		}
 		// 回溯: 如果目标没有下降，则缩小步长（最多尝试若干次）
-		oldObj := logisticL1Objective(X, Y, beta, alpha)
+		oldObj := logisticENObjective(X, Y, beta, alpha, l1Ratio, withIntercept)
 		for bt := 0; bt < 10; bt++ {
-			newObj := logisticL1Objective(X, Y, newBeta, alpha)
+			newObj := logisticENObjective(X, Y, newBeta, alpha, l1Ratio, withIntercept)
 			if newObj <= oldObj {
 				break
 			}
@@ -285,7 +279,7 @@ func lassoLogisticISTA(matX *mat.Dense, matY *mat.VecDense, alpha float64, withI
 				newBeta[0] = beta[0] - step*g[0]
 			}
 			for j := start; j < p; j++ {
-				newBeta[j] = softThreshold(beta[j]-step*g[j], step*alpha)
+				newBeta[j] = elasticNetProx(beta[j]-step*g[j], step, alpha, l1Ratio)
 			}
 		}
 		// 收敛判据
@@ -304,8 +298,13 @@ func lassoLogisticISTA(matX *mat.Dense, matY *mat.VecDense, alpha float64, withI
 	return beta, nil
 }
 
-// logisticL1Objective: (1/n)Σ [log(1+e^{xβ}) - y xβ] + α||β||_1（截距不惩罚）
-func logisticL1Objective(X *mat.Dense, Y *mat.VecDense, beta []float64, alpha float64) float64 {
+// elasticNetProx: Elastic Net 惩罚在步长 step 下的近端算子
+func elasticNetProx(z, step, alpha, l1Ratio float64) float64 {
+	return softThreshold(z, step*alpha*l1Ratio) / (1 + step*alpha*(1-l1Ratio))
+}
+
+// logisticENObjective: (1/n)Σ [log(1+e^{xβ}) - y xβ] + α·l1Ratio·||β||_1 + (α(1-l1Ratio)/2)||β||^2（截距不惩罚）
+func logisticENObjective(X *mat.Dense, Y *mat.VecDense, beta []float64, alpha, l1Ratio float64, withIntercept bool) float64 {
 	n, p := X.Dims()
 	loss := 0.0
 	for i := 0; i < n; i++ {
@@ -313,25 +312,39 @@ func logisticL1Objective(X *mat.Dense, Y *mat.VecDense, beta []float64, alpha fl
 		for j := 0; j < p; j++ {
 			s += X.At(i, j) * beta[j]
 		}
-		if s > 20 {
-			loss += (s - Y.AtVec(i)*s)
-		} else if s < -20 {
-			loss += (0 - Y.AtVec(i)*s)
-		} else {
-			loss += math.Log1p(math.Exp(s)) - Y.AtVec(i)*s
-		}
+		loss += logisticLoss(s, Y.AtVec(i))
 	}
 	loss /= float64(n)
-	// L1:
-	l1 := 0.0
-	start := 1 // 默认有截距时
-	if p > 0 && math.Abs(X.At(0, 0)-1.0) > 1e-12 {
-		start = 0
-	} // 无截距
+	start := 0
+	if withIntercept {
+		start = 1
+	}
+	l1, l2 := 0.0, 0.0
 	for j := start; j < p; j++ {
 		l1 += math.Abs(beta[j])
+		l2 += beta[j] * beta[j]
+	}
+	return loss + alpha*l1Ratio*l1 + 0.5*alpha*(1-l1Ratio)*l2
+}
+
+// logisticLoss: log(1+e^s) - y·s，|s| > 20 时用渐近式
+func logisticLoss(s, y float64) float64 {
+	if s > 20 {
+		return s - y*s
+	} else if s < -20 {
+		return -y * s
 	}
-	return loss + alpha*l1
+	return math.Log1p(math.Exp(s)) - y*s
+}
+
+// sigmoid: 1/(1+e^{-s})，|s| > 20 时截断为 0/1
+func sigmoid(s float64) float64 {
+	if s > 20 {
+		return 1
+	} else if s < -20 {
+		return 0
+	}
+	return 1 / (1 + math.Exp(-s))
 }
 
 // -------------------------- 对外主函数 --------------------------
@@ -339,49 +352,68 @@ func logisticL1Objective(X *mat.Dense, Y *mat.VecDense, beta []float64, alpha fl
 // alpha: 与 sklearn 相同的正则参数（不是 λ/(2n)，就是 α 本身）。
 // useLogistic: false=线性；true=逻辑
 // withIntercept: 是否包含截距（不参与惩罚）
+// 逻辑回归的 Resids/AIC/BIC/RSquared 保持原实现口径(基于线性预测值 Xβ)，
+// 基于概率 p̂ 的输出请用 MultiRegressionElasticNet(..., l1Ratio=1, ...)，两者系数相同
 func MultiRegressionLasso(matX *mat.Dense, matY *mat.VecDense, alpha float64, useLogistic bool, withIntercept bool) (MultiLinearModel, error) {
-	n, _ := matX.Dims()
-	if matY.Len() != n {
-		return MultiLinearModel{}, errorx.New(errCode.INVALID_VALUE, "Y 长度与 X 行数不匹配")
+	model, err := MultiRegressionElasticNet(matX, matY, alpha, 1, useLogistic, withIntercept)
+	if err != nil || !useLogistic {
+		return model, err
 	}
+	lassoLogisticStats(&model, matX, matY, withIntercept)
+	return model, nil
+}
 
-	// sklearn 对齐：alpha 按样本数缩放
-	alphaEff := alpha / float64(n)
-
-	// 训练
-	var beta []float64
-	var err error
-	const tol = 1e-5
-	const maxIter = 10000
-	if !useLogistic {
-		beta, err = lassoLinearCD(matX, matY, alphaEff, withIntercept, tol, maxIter)
-	} else {
-		beta, err = lassoLogisticISTA(matX, matY, alphaEff, withIntercept, tol, maxIter)
+// lassoLogisticStats: 原 MultiRegressionLasso 的逻辑回归输出，残差为 y - Xβ，
+// 对数似然中的 p 取 Xβ 截断到 [1e-12, 1-1e-12]
+func lassoLogisticStats(model *MultiLinearModel, matX *mat.Dense, matY *mat.VecDense, withIntercept bool) {
+	n, _ := matX.Dims()
+	Xeff := mat.DenseCopyOf(matX)
+	if withIntercept {
+		Xeff = addInterceptColumnDense(Xeff)
+	}
+	yhat := computePred(Xeff, model.Coeffs)
+	k := len(model.Coeffs)
+	logLik, ybar := 0.0, 0.0
+	for i := 0; i < n; i++ {
+		y := matY.AtVec(i)
+		model.Resids[i] = y - yhat.AtVec(i)
+		p := math.Min(math.Max(yhat.AtVec(i), 1e-12), 1-1e-12)
+		logLik += y*math.Log(p) + (1-y)*math.Log(1-p)
+		ybar += y
 	}
-	if err != nil {
-		return MultiLinearModel{}, err
+	ybar /= float64(n)
+	llNull := float64(n) * (ybar*math.Log(ybar+1e-12) + (1-ybar)*math.Log(1-ybar+1e-12))
+	model.AIC = -2*logLik + 2*float64(k)
+	model.BIC = -2*logLik + float64(k)*math.Log(float64(n))
+	model.RSquared = 0
+	if llNull != 0 {
+		model.RSquared = 1 - logLik/llNull
 	}
+}
 
-	// 预测、残差、指标
+// penalizedModel: 把惩罚回归系数整理为 MultiLinearModel（LASSO / Ridge / Elastic Net 共用）
+// 线性: RSS, R^2, AIC/BIC 与 OLS 定义一致；逻辑: 残差为 y - p̂，RSquared 为 McFadden pseudo-R^2
+// 惩罚估计下 SE/TStats/PValues 无经典意义，保留 0
+func penalizedModel(matX *mat.Dense, matY *mat.VecDense, beta []float64, useLogistic bool, withIntercept bool) MultiLinearModel {
+	n, _ := matX.Dims()
 	Xeff := mat.DenseCopyOf(matX)
 	if withIntercept {
 		Xeff = addInterceptColumnDense(Xeff)
 	}
 	yhat := computePred(Xeff, beta)
+	if useLogistic {
+		for i := 0; i < n; i++ {
+			yhat.SetVec(i, sigmoid(yhat.AtVec(i)))
+		}
+	}
 
-	// 组装输出结构
 	k := len(beta)
 	model := MultiLinearModel{
-		Coeffs:      make([]float64, k),
-		SE:          make([]float64, k),
-		TStats:      make([]float64, k),
-		PValues:     make([]float64, k),
-		Resids:      make([]float64, n),
-		AIC:         0,
-		BIC:         0,
-		Sigma2:      0,
-		RSquared:    0,
-		AdjRSquared: 0,
+		Coeffs:  make([]float64, k),
+		SE:      make([]float64, k),
+		TStats:  make([]float64, k),
+		PValues: make([]float64, k),
+		Resids:  make([]float64, n),
 	}
 	copy(model.Coeffs, beta)
 	for i := 0; i < n; i++ {
@@ -413,33 +445,27 @@ func MultiRegressionLasso(matX *mat.Dense, matY *mat.VecDense, alpha float64, us
 			model.AIC = -2*logLik + 2*float64(k)
 			model.BIC = -2*logLik + float64(k)*math.Log(float64(n))
 		}
-		// LASSO 下 SE/TStats/PValues 非经典意义，这里保留 0
-	} else {
-		// 逻辑: logLik, AIC/BIC, pseudo R^2 (McFadden)
-		logLik := 0.0
-		for i := 0; i < n; i++ {
-			p := yhat.AtVec(i)
-			if p < 1e-12 {
-				p = 1e-12
-			} else if p > 1-1e-12 {
-				p = 1 - 1e-12
-			}
-			y := matY.AtVec(i)
-			logLik += y*math.Log(p) + (1-y)*math.Log(1-p)
-		}
-		// 空模型对数似然（只有截距）
-		ybar := 0.0
-		for i := 0; i < n; i++ {
-			ybar += matY.AtVec(i)
-		}
-		ybar /= float64(n)
-		llNull := float64(n) * (ybar*math.Log(ybar+1e-12) + (1-ybar)*math.Log(1-ybar+1e-12))
-		model.AIC = -2*logLik + 2*float64(k)
-		model.BIC = -2*logLik + float64(k)*math.Log(float64(n))
-		if llNull != 0 {
-			model.RSquared = 1 - (logLik)/(llNull)
-		} // McFadden pseudo-R^2
-		// 其余统计量保持 0/默认
+		return model
 	}
-	return model, nil
+
+	// 逻辑: logLik, AIC/BIC, pseudo R^2 (McFadden)
+	logLik := 0.0
+	for i := 0; i < n; i++ {
+		p := math.Min(math.Max(yhat.AtVec(i), 1e-12), 1-1e-12)
+		y := matY.AtVec(i)
+		logLik += y*math.Log(p) + (1-y)*math.Log(1-p)
+	}
+	// 空模型对数似然（只有截距）
+	ybar := 0.0
+	for i := 0; i < n; i++ {
+		ybar += matY.AtVec(i)
+	}
+	ybar /= float64(n)
+	llNull := float64(n) * (ybar*math.Log(ybar+1e-12) + (1-ybar)*math.Log(1-ybar+1e-12))
+	model.AIC = -2*logLik + 2*float64(k)
+	model.BIC = -2*logLik + float64(k)*math.Log(float64(n))
+	if llNull != 0 {
+		model.RSquared = 1 - logLik/llNull
+	}
+	return model
 }
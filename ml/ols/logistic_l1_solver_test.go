@@ -0,0 +1,79 @@
+package ols
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func labelData(n, p int) (*mat.Dense, *mat.VecDense) {
+	r := rand.New(rand.NewSource(6))
+	X := mat.NewDense(n, p, nil)
+	y := mat.NewVecDense(n, nil)
+	for i := 0; i < n; i++ {
+		eta := -0.3
+		for j := 0; j < p; j++ {
+			v := r.NormFloat64()
+			if j > 0 {
+				v = 0.5*X.At(i, j-1) + v // 相邻列相关
+			}
+			X.Set(i, j, v)
+			if j < 5 {
+				eta += 0.8 * v * float64(1-2*(j%2))
+			}
+		}
+		if r.Float64() < 1/(1+math.Exp(-eta)) {
+			y.SetVec(i, 1)
+		}
+	}
+	return X, y
+}
+
+// 原始尺度系数在标准化空间的目标值，用于比较不同求解器
+func logisticENObjectiveOriginal(X *mat.Dense, y *mat.VecDense, coeffs []float64, alpha, l1 float64) float64 {
+	Z := addInterceptColumnDense(X)
+	means, stds := standardizeExceptIntercept(Z, true)
+	std := make([]float64, len(coeffs))
+	copy(std, coeffs)
+	for j := 1; j < len(coeffs); j++ {
+		std[j] = coeffs[j] * stds[j]
+		std[0] += means[j] * coeffs[j]
+	}
+	n, _ := X.Dims()
+	return logisticENObjective(Z, y, std, alpha/float64(n), l1, true)
+}
+
+func TestLogitSolversAgree(t *testing.T) {
+	X, y := labelData(800, 15)
+	for _, l1 := range []float64{1, 0.5} {
+		const alpha = 10.0
+		ista, _ := MultiRegressionElasticNetSolver(X, y, alpha, l1, true, true, LOGIT_ISTA)
+		fista, _ := MultiRegressionElasticNetSolver(X, y, alpha, l1, true, true, LOGIT_FISTA)
+		irls, err := MultiRegressionElasticNetSolver(X, y, alpha, l1, true, true, LOGIT_IRLS_CD)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertClose(t, "fista vs irls", fista.Coeffs, irls.Coeffs, 1e-4)
+		assertClose(t, "ista vs irls", ista.Coeffs, irls.Coeffs, 5e-3)
+		oI := logisticENObjectiveOriginal(X, y, irls.Coeffs, alpha, l1)
+		oS := logisticENObjectiveOriginal(X, y, ista.Coeffs, alpha, l1)
+		if oI > oS+1e-9 {
+			t.Errorf("l1=%v: irls objective %v > ista %v", l1, oI, oS)
+		}
+	}
+}
+
+func benchmarkLogit(b *testing.B, solver LogitSolver) {
+	X, y := labelData(5000, 50)
+	var m MultiLinearModel
+	for i := 0; i < b.N; i++ {
+		m, _ = MultiRegressionElasticNetSolver(X, y, 20, 1, true, true, solver)
+	}
+	b.ReportMetric(logisticENObjectiveOriginal(X, y, m.Coeffs, 20, 1), "objective")
+}
+
+func BenchmarkLogitISTA(b *testing.B)   { benchmarkLogit(b, LOGIT_ISTA) }
+func BenchmarkLogitFISTA(b *testing.B)  { benchmarkLogit(b, LOGIT_FISTA) }
+func BenchmarkLogitIRLSCD(b *testing.B) { benchmarkLogit(b, LOGIT_IRLS_CD) }
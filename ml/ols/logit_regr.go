@@ -0,0 +1,369 @@
+// 无惩罚逻辑回归: IRLS(Newton) 极大似然 + Wald 推断
+// 每步把 Newton 方程写成加权最小二乘 √W X δ = (y - p)/√w，用 solveLeastSquares(QR) 求解，
+// 不显式求 (X'WX)^-1 的逆；对数似然下降时步长减半
+// 完全/准完全分离时 MLE 不存在(系数发散、拟合概率趋于 0/1)，检测到后改用 Firth 惩罚似然 (Firth 1993):
+//
+//	U*(β) = X'(y - p + h∘(1/2 - p))，h 为 W^{1/2}X(X'WX)^{-1}X'W^{1/2} 的对角元
+//	l*(β) = l(β) + 1/2·ln|X'WX|
+package ols
+
+import (
+	"fmt"
+	"math"
+	"ofeisInfra/infra/errorx"
+	"ofeisInfra/infra/errorx/errCode"
+
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+const (
+	logitMaxIter = 100
+	logitTol     = 1e-10 // 对数似然相对变化收敛阈值
+	logitSepEta  = 23.0  // |η| 超过此值即 p 与 0/1 相差 < 1e-10，视为分离信号
+	logitMinW    = 1e-12
+)
+
+type LogitModel struct {
+	MultiLinearModel           // Coeffs/SE/TStats(z 统计量)/PValues(正态)/Cov；Resids 为 y - p̂，RSquared 为 McFadden R²，AdjRSquared 为调整 McFadden R²，Sigma2 恒为 1
+	LogLik           float64   // 对数似然(Firth 时为未惩罚的对数似然)
+	LLNull           float64   // 空模型对数似然(有常数列时为仅截距，否则 β=0)
+	LRStat           float64   // 对空模型的似然比统计量
+	LRPValue         float64   // χ²(rank - 空模型参数数)
+	Probs            []float64 // 拟合概率
+	NIter            int
+	Converged        bool
+	Separation       bool // 检测到(准)完全分离
+	Firth            bool // 系数来自 Firth 惩罚似然
+	WithConst        bool
+}
+
+type LRTestResult struct {
+	Stat   float64
+	Df     int
+	PValue float64
+}
+
+// 逻辑回归(行数据接口)，withConst 时在最左侧添加常数列；Y 须为 0/1
+func LogitRegression(X [][]float64, Y []float64, withConst bool) (LogitModel, error) {
+	n := len(Y)
+	if n == 0 || len(X) == 0 {
+		return LogitModel{}, errorx.New(errCode.EMPTY_VALUE, "输入数据为空")
+	}
+	if n != len(X) {
+		return LogitModel{}, errorx.New(errCode.INVALID_VALUE, "数据长度不匹配")
+	}
+	if withConst {
+		X = addConstantColumn(X)
+	}
+	k := len(X[0])
+	matX := mat.NewDense(n, k, nil)
+	for i := 0; i < n; i++ {
+		if len(X[i]) != k {
+			return LogitModel{}, errorx.New(errCode.INVALID_VALUE, "X 各行长度不一致")
+		}
+		matX.SetRow(i, X[i])
+	}
+	m, err := LogitRegressionMat(matX, mat.NewVecDense(n, append([]float64(nil), Y...)))
+	m.WithConst = withConst
+	return m, err
+}
+
+// 逻辑回归，matX 需自带常数列；检测到分离时自动改用 Firth
+func LogitRegressionMat(matX *mat.Dense, matY *mat.VecDense) (LogitModel, error) {
+	n, k := matX.Dims()
+	if matY.Len() != n {
+		return LogitModel{}, errorx.New(errCode.INVALID_VALUE, "数据长度不匹配")
+	}
+	if n <= k {
+		return LogitModel{}, errorx.New(errCode.INVALID_VALUE, fmt.Sprintf("样本数 %d 须大于参数数 %d", n, k))
+	}
+	for i := 0; i < n; i++ {
+		if y := matY.AtVec(i); y != 0 && y != 1 {
+			return LogitModel{}, errorx.New(errCode.INVALID_VALUE, "Y 须为 0/1")
+		}
+	}
+	fit, err := logitIRLS(matX, matY, false)
+	if err != nil {
+		return LogitModel{}, err
+	}
+	if fit.separation {
+		firth, err := logitIRLS(matX, matY, true)
+		if err != nil {
+			return LogitModel{}, err
+		}
+		firth.separation = true
+		fit = firth
+	}
+	return fit.model(matX, matY), nil
+}
+
+// 逻辑回归，始终使用 Firth 惩罚似然(小样本偏差修正)
+func LogitRegressionFirthMat(matX *mat.Dense, matY *mat.VecDense) (LogitModel, error) {
+	n, k := matX.Dims()
+	if matY.Len() != n {
+		return LogitModel{}, errorx.New(errCode.INVALID_VALUE, "数据长度不匹配")
+	}
+	if n <= k {
+		return LogitModel{}, errorx.New(errCode.INVALID_VALUE, fmt.Sprintf("样本数 %d 须大于参数数 %d", n, k))
+	}
+	fit, err := logitIRLS(matX, matY, true)
+	if err != nil {
+		return LogitModel{}, err
+	}
+	return fit.model(matX, matY), nil
+}
+
+// 嵌套模型似然比检验: LR = 2(l_full - l_restricted) ~ χ²(rank_full - rank_restricted)
+// 两个模型须在同一样本上拟合；Firth 模型比较的是未惩罚对数似然，仅作参考
+func LogitLRTest(full, restricted LogitModel) (LRTestResult, error) {
+	if len(full.Probs) != len(restricted.Probs) {
+		return LRTestResult{}, errorx.New(errCode.INVALID_VALUE, "两个模型的样本量不同")
+	}
+	df := full.Rank - restricted.Rank
+	if df <= 0 {
+		return LRTestResult{}, errorx.New(errCode.INVALID_VALUE, "受限模型的参数数须少于完整模型")
+	}
+	stat := math.Max(2*(full.LogLik-restricted.LogLik), 0)
+	return LRTestResult{Stat: stat, Df: df, PValue: distuv.ChiSquared{K: float64(df)}.Survival(stat)}, nil
+}
+
+// 预测概率，x 不含常数项(WithConst 时自动添加)
+func (m *LogitModel) PredictProba(x []float64) (float64, error) {
+	if m.WithConst {
+		x = append([]float64{1}, x...)
+	}
+	if len(x) != len(m.Coeffs) {
+		return math.NaN(), errorx.New(errCode.INVALID_VALUE, "x 长度与参数数不符")
+	}
+	eta := 0.0
+	for j, c := range m.Coeffs {
+		eta += x[j] * c
+	}
+	return sigmoid(eta), nil
+}
+
+type logitFit struct {
+	beta       *mat.VecDense
+	sol        olsSolution // 末次加权最小二乘，invXTX 即 (X'WX)^-1
+	logLik     float64
+	nIter      int
+	converged  bool
+	separation bool
+	firth      bool
+}
+
+func logitIRLS(matX *mat.Dense, matY *mat.VecDense, firth bool) (logitFit, error) {
+	n, k := matX.Dims()
+	beta := mat.NewVecDense(k, nil)
+	eta := mat.NewVecDense(n, nil)
+	Xw := mat.NewDense(n, k, nil)
+	z := mat.NewVecDense(n, nil)
+	p := make([]float64, n)
+	w := make([]float64, n)
+
+	// 在 beta 处计算 p、w、√W X，并返回(惩罚)对数似然
+	eval := func(b *mat.VecDense) (float64, error) {
+		eta.MulVec(matX, b)
+		ll := 0.0
+		for i := 0; i < n; i++ {
+			e := eta.AtVec(i)
+			ll -= logisticLoss(e, matY.AtVec(i))
+			p[i] = sigmoid(e)
+			w[i] = math.Max(p[i]*(1-p[i]), logitMinW)
+			sw := math.Sqrt(w[i])
+			for j := 0; j < k; j++ {
+				Xw.Set(i, j, sw*matX.At(i, j))
+			}
+		}
+		if !firth {
+			return ll, nil
+		}
+		var xwx mat.SymDense
+		xwx.SymOuterK(1, Xw.T())
+		var chol mat.Cholesky
+		if !chol.Factorize(&xwx) {
+			return math.Inf(-1), errorx.New(errCode.INVALID_VALUE, "X'WX 非正定，请检查自变量是否共线")
+		}
+		return ll + 0.5*chol.LogDet(), nil
+	}
+
+	ll, err := eval(beta)
+	if err != nil {
+		return logitFit{}, err
+	}
+	fit := logitFit{beta: beta, firth: firth}
+	for it := 1; it <= logitMaxIter; it++ {
+		fit.nIter = it
+		// 工作响应: (y - p)/√w，Firth 时加 h(1/2 - p)
+		var h []float64
+		if firth {
+			if h, err = hatDiag(Xw); err != nil {
+				return logitFit{}, err
+			}
+		}
+		for i := 0; i < n; i++ {
+			u := matY.AtVec(i) - p[i]
+			if firth {
+				u += h[i] * (0.5 - p[i])
+			}
+			z.SetVec(i, u/math.Sqrt(w[i]))
+		}
+		sol, err := solveLeastSquares(Xw, z, SOLVER_QR)
+		if err != nil {
+			return logitFit{}, err
+		}
+		fit.sol = sol
+
+		// 步长减半保证(惩罚)对数似然上升
+		next := mat.NewVecDense(k, nil)
+		step := 1.0
+		newLL := math.Inf(-1)
+		for bt := 0; bt < 30; bt++ {
+			next.AddScaledVec(beta, step, sol.beta)
+			if newLL, err = eval(next); err == nil && newLL >= ll-1e-12*math.Abs(ll) {
+				break
+			}
+			step *= 0.5
+		}
+		if err != nil {
+			return logitFit{}, err
+		}
+		beta.CopyVec(next)
+		done := math.Abs(newLL-ll) < logitTol*(math.Abs(ll)+1)
+		ll = newLL
+		if done {
+			fit.converged = true
+			break
+		}
+	}
+	// 末次 eval 后刷新 (X'WX)^-1
+	sol, err := solveLeastSquares(Xw, z, SOLVER_QR)
+	if err != nil {
+		return logitFit{}, err
+	}
+	fit.sol = sol
+	fit.logLik = ll
+
+	if !firth {
+		maxEta := 0.0
+		for i := 0; i < n; i++ {
+			maxEta = math.Max(maxEta, math.Abs(eta.AtVec(i)))
+		}
+		fit.separation = !fit.converged || maxEta > logitSepEta
+	}
+	return fit, nil
+}
+
+// √W X 的帽子矩阵对角元 h_i = x̃_i'(X'WX)^-1 x̃_i = ||L^-1 x̃_i||²，X'WX = LL'，避免构造 n×n 的 Q
+func hatDiag(Xw *mat.Dense) ([]float64, error) {
+	n, k := Xw.Dims()
+	var xwx mat.SymDense
+	xwx.SymOuterK(1, Xw.T())
+	var chol mat.Cholesky
+	if !chol.Factorize(&xwx) {
+		return nil, errorx.New(errCode.INVALID_VALUE, "X'WX 非正定，请检查自变量是否共线")
+	}
+	var L mat.TriDense
+	chol.LTo(&L)
+	h := make([]float64, n)
+	v := mat.NewVecDense(k, nil)
+	for i := 0; i < n; i++ {
+		if err := v.SolveVec(&L, Xw.RowView(i)); err != nil {
+			return nil, errorx.New(errCode.INVALID_VALUE, "X'WX 病态，无法计算杠杆值")
+		}
+		h[i] = mat.Dot(v, v)
+	}
+	return h, nil
+}
+
+func (f logitFit) model(matX *mat.Dense, matY *mat.VecDense) LogitModel {
+	n, k := matX.Dims()
+	eta := mat.NewVecDense(n, nil)
+	eta.MulVec(matX, f.beta)
+	m := LogitModel{
+		Probs:      make([]float64, n),
+		NIter:      f.nIter,
+		Converged:  f.converged,
+		Separation: f.separation,
+		Firth:      f.firth,
+	}
+	ll, ybar := 0.0, 0.0
+	resid := make([]float64, n)
+	for i := 0; i < n; i++ {
+		y := matY.AtVec(i)
+		m.Probs[i] = sigmoid(eta.AtVec(i))
+		resid[i] = y - m.Probs[i]
+		ll -= logisticLoss(eta.AtVec(i), y)
+		ybar += y
+	}
+	ybar /= float64(n)
+	m.LogLik = ll
+
+	// 空模型: 有常数列时仅截距，否则 β = 0
+	nullK := 0
+	if hasConstColumn(matX) {
+		nullK = 1
+		m.LLNull = float64(n) * (xlogx(ybar) + xlogx(1-ybar))
+	} else {
+		m.LLNull = float64(n) * math.Log(0.5)
+	}
+	rank := f.sol.rank
+	if df := rank - nullK; df > 0 {
+		m.LRStat = math.Max(2*(ll-m.LLNull), 0)
+		m.LRPValue = distuv.ChiSquared{K: float64(df)}.Survival(m.LRStat)
+	}
+
+	coeffs := make([]float64, k)
+	se := make([]float64, k)
+	zs := make([]float64, k)
+	ps := make([]float64, k)
+	for j := 0; j < k; j++ {
+		coeffs[j] = f.beta.AtVec(j)
+		se[j] = math.Sqrt(f.sol.invXTX.At(j, j))
+		zs[j] = coeffs[j] / se[j]
+		ps[j] = 2 * distuv.UnitNormal.Survival(math.Abs(zs[j]))
+	}
+	m.MultiLinearModel = MultiLinearModel{
+		Coeffs:  coeffs,
+		SE:      se,
+		TStats:  zs,
+		PValues: ps,
+		Resids:  resid,
+		AIC:     -2*ll + 2*float64(rank),
+		BIC:     -2*ll + float64(rank)*math.Log(float64(n)),
+		Sigma2:  1,
+		Cov:     scaledCov(1, f.sol.invXTX),
+		CovType: COV_NONROBUST,
+		Rank:    rank,
+		CondNum: f.sol.cond,
+		Solver:  f.sol.solver,
+	}
+	if m.LLNull != 0 {
+		m.RSquared = 1 - ll/m.LLNull
+		m.AdjRSquared = 1 - (ll-float64(rank))/m.LLNull
+	}
+	return m
+}
+
+func hasConstColumn(matX *mat.Dense) bool {
+	n, k := matX.Dims()
+	for j := 0; j < k; j++ {
+		isConst := true
+		for i := 0; i < n && isConst; i++ {
+			isConst = matX.At(i, j) == 1
+		}
+		if isConst {
+			return true
+		}
+	}
+	return false
+}
+
+// x·ln(x)，x=0 时取 0
+func xlogx(x float64) float64 {
+	if x <= 0 {
+		return 0
+	}
+	return x * math.Log(x)
+}
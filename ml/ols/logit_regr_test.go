@@ -0,0 +1,167 @@
+package ols
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// η = 0.5 + 1.0·x1 - 0.8·x2 + 0·x3
+func logitData(n int) ([][]float64, []float64) {
+	r := rand.New(rand.NewSource(11))
+	X := make([][]float64, n)
+	Y := make([]float64, n)
+	for i := range X {
+		X[i] = []float64{r.NormFloat64(), r.NormFloat64(), r.NormFloat64()}
+		eta := 0.5 + X[i][0] - 0.8*X[i][1]
+		if r.Float64() < sigmoid(eta) {
+			Y[i] = 1
+		}
+	}
+	return X, Y
+}
+
+func TestLogitRegression(t *testing.T) {
+	X, Y := logitData(4000)
+	m, err := LogitRegression(X, Y, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !m.Converged || m.Separation || m.Firth {
+		t.Fatalf("converged=%v separation=%v firth=%v", m.Converged, m.Separation, m.Firth)
+	}
+	truth := []float64{0.5, 1, -0.8, 0}
+	for j, b := range truth {
+		if math.Abs(m.Coeffs[j]-b) > 4*m.SE[j] {
+			t.Errorf("coef %d: %v, want %v ± %v", j, m.Coeffs[j], b, 4*m.SE[j])
+		}
+	}
+	// n=4000 时 SE 约为 1/√(n·p(1-p)·Var(x)) ≈ 0.035~0.045
+	for j, se := range m.SE {
+		if se < 0.02 || se > 0.08 {
+			t.Errorf("SE %d = %v out of range", j, se)
+		}
+	}
+	if m.PValues[1] > 1e-6 || m.PValues[3] < 1e-3 {
+		t.Errorf("p-values %v", m.PValues)
+	}
+	if m.LRPValue > 1e-10 || m.RSquared <= 0 || m.RSquared >= 1 || m.AdjRSquared >= m.RSquared {
+		t.Errorf("LR p=%v R2=%v adjR2=%v", m.LRPValue, m.RSquared, m.AdjRSquared)
+	}
+
+	// 得分方程 X'(y - p) = 0
+	for j := 0; j < 4; j++ {
+		s := 0.0
+		for i := range Y {
+			x := 1.0
+			if j > 0 {
+				x = X[i][j-1]
+			}
+			s += x * m.Resids[i]
+		}
+		if math.Abs(s) > 1e-6 {
+			t.Errorf("score %d = %v", j, s)
+		}
+	}
+	for _, i := range []int{0, 17, 3999} {
+		p, err := m.PredictProba(X[i])
+		if err != nil || math.Abs(p-m.Probs[i]) > 1e-12 {
+			t.Errorf("PredictProba row %d: %v vs %v (%v)", i, p, m.Probs[i], err)
+		}
+	}
+}
+
+func TestLogitLRTest(t *testing.T) {
+	X, Y := logitData(2000)
+	full, _ := LogitRegression(X, Y, true)
+
+	// 去掉无关变量 x3: 似然比不显著
+	noX3 := make([][]float64, len(X))
+	noX2 := make([][]float64, len(X))
+	for i, row := range X {
+		noX3[i] = []float64{row[0], row[1]}
+		noX2[i] = []float64{row[0], row[2]}
+	}
+	r3, _ := LogitRegression(noX3, Y, true)
+	lr, err := LogitLRTest(full, r3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lr.Df != 1 || lr.PValue < 0.001 {
+		t.Errorf("drop x3: %+v", lr)
+	}
+	// 单参数时 LR 与 Wald z² 渐近相等
+	if z2 := full.TStats[3] * full.TStats[3]; math.Abs(lr.Stat-z2) > 0.1*z2+0.05 {
+		t.Errorf("LR %v vs Wald %v", lr.Stat, z2)
+	}
+	r2, _ := LogitRegression(noX2, Y, true)
+	if lr, _ = LogitLRTest(full, r2); lr.PValue > 1e-10 {
+		t.Errorf("drop x2: %+v", lr)
+	}
+	if _, err = LogitLRTest(r3, full); err == nil {
+		t.Error("expected error for reversed models")
+	}
+}
+
+func TestLogitSeparation(t *testing.T) {
+	// x > 0 完全决定 y: MLE 发散
+	r := rand.New(rand.NewSource(3))
+	n := 60
+	matX := mat.NewDense(n, 2, nil)
+	matY := mat.NewVecDense(n, nil)
+	for i := 0; i < n; i++ {
+		x := r.NormFloat64()
+		matX.Set(i, 0, 1)
+		matX.Set(i, 1, x)
+		if x > 0 {
+			matY.SetVec(i, 1)
+		}
+	}
+	m, err := LogitRegressionMat(matX, matY)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !m.Separation || !m.Firth || !m.Converged {
+		t.Fatalf("separation=%v firth=%v converged=%v", m.Separation, m.Firth, m.Converged)
+	}
+	for j := range m.Coeffs {
+		if math.IsNaN(m.Coeffs[j]) || math.IsInf(m.Coeffs[j], 0) || math.IsNaN(m.SE[j]) {
+			t.Fatalf("non-finite estimate %v ± %v", m.Coeffs[j], m.SE[j])
+		}
+	}
+	if m.Coeffs[1] <= 0 || m.Coeffs[1] > 50 {
+		t.Errorf("firth slope %v", m.Coeffs[1])
+	}
+}
+
+func TestLogitFirthShrinks(t *testing.T) {
+	// 无分离的小样本: Firth 估计向 0 收缩
+	X, Y := logitData(80)
+	rows := make([][]float64, len(X))
+	for i := range X {
+		rows[i] = []float64{1, X[i][0], X[i][1]}
+	}
+	matX := mat.NewDense(len(rows), 3, nil)
+	for i, r := range rows {
+		matX.SetRow(i, r)
+	}
+	matY := mat.NewVecDense(len(Y), Y)
+	ml, _ := LogitRegressionMat(matX, matY)
+	fr, err := LogitRegressionFirthMat(matX, matY)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ml.Firth || !fr.Firth {
+		t.Fatalf("firth flags ml=%v firth=%v", ml.Firth, fr.Firth)
+	}
+	for j := 1; j < 3; j++ {
+		if math.Abs(fr.Coeffs[j]) >= math.Abs(ml.Coeffs[j]) {
+			t.Errorf("coef %d: firth %v not shrunk vs ml %v", j, fr.Coeffs[j], ml.Coeffs[j])
+		}
+	}
+	if fr.LogLik > ml.LogLik {
+		t.Errorf("firth loglik %v > ml %v", fr.LogLik, ml.LogLik)
+	}
+}
@@ -0,0 +1,232 @@
+package ols
+
+// 系数协方差估计方法
+type CovType int
+
+const (
+	COV_NONROBUST CovType = iota // "nonrobust" 经典 σ²(X'X)^-1
+	COV_HC0                      // "HC0" White
+	COV_HC1                      // "HC1" HC0·n/(n-k)
+	COV_HC2                      // "HC2" 按 1/(1-h_ii) 加权
+	COV_HC3                      // "HC3" 按 1/(1-h_ii)² 加权
+	COV_HAC                      // "HAC" Newey-West
+	COV_CLUSTER                  // "cluster" 聚类稳健
+	COV_ERROR                    // "ERROR"
+)
+
+func (s CovType) String() string {
+	switch s {
+	case COV_NONROBUST:
+		return "nonrobust"
+	case COV_HC0:
+		return "HC0"
+	case COV_HC1:
+		return "HC1"
+	case COV_HC2:
+		return "HC2"
+	case COV_HC3:
+		return "HC3"
+	case COV_HAC:
+		return "HAC"
+	case COV_CLUSTER:
+		return "cluster"
+	default:
+		return "ERROR"
+	}
+}
+
+func GetMyCovType(s string) CovType {
+	switch s {
+	case "nonrobust":
+		return COV_NONROBUST
+	case "HC0":
+		return COV_HC0
+	case "HC1":
+		return COV_HC1
+	case "HC2":
+		return COV_HC2
+	case "HC3":
+		return COV_HC3
+	case "HAC":
+		return COV_HAC
+	case "cluster":
+		return COV_CLUSTER
+	default:
+		return COV_ERROR
+	}
+}
+
+// 最小二乘求解器
+type SolverType int
+
+const (
+	SOLVER_QR       SolverType = iota // "qr" Householder QR，条件数不平方，默认
+	SOLVER_CHOLESKY                   // "cholesky" X'X 的 Cholesky 分解，最快，条件数平方
+	SOLVER_SVD                        // "svd" 奇异值分解，最稳健，支持秩亏
+	SOLVER_ERROR                      // "ERROR"
+)
+
+func (s SolverType) String() string {
+	switch s {
+	case SOLVER_QR:
+		return "qr"
+	case SOLVER_CHOLESKY:
+		return "cholesky"
+	case SOLVER_SVD:
+		return "svd"
+	default:
+		return "ERROR"
+	}
+}
+
+func GetMySolverType(s string) SolverType {
+	switch s {
+	case "qr":
+		return SOLVER_QR
+	case "cholesky":
+		return SOLVER_CHOLESKY
+	case "svd":
+		return SOLVER_SVD
+	default:
+		return SOLVER_ERROR
+	}
+}
+
+// 时序交叉验证划分方式
+type CVScheme int
+
+const (
+	CV_WALK_FORWARD CVScheme = iota // "walk_forward" 扩张窗口训练，下一块测试
+	CV_PURGED_KFOLD                 // "purged_kfold" 连续块 k 折，测试块前 purge、后 embargo
+	CV_ERROR                        // "ERROR"
+)
+
+func (s CVScheme) String() string {
+	switch s {
+	case CV_WALK_FORWARD:
+		return "walk_forward"
+	case CV_PURGED_KFOLD:
+		return "purged_kfold"
+	default:
+		return "ERROR"
+	}
+}
+
+func GetMyCVScheme(s string) CVScheme {
+	switch s {
+	case "walk_forward":
+		return CV_WALK_FORWARD
+	case "purged_kfold":
+		return CV_PURGED_KFOLD
+	default:
+		return CV_ERROR
+	}
+}
+
+// L1/Elastic Net 逻辑回归求解器
+type LogitSolver int
+
+const (
+	LOGIT_IRLS_CD LogitSolver = iota // "irls_cd" glmnet 式 IRLS 外层 + 加权坐标下降内层，默认
+	LOGIT_FISTA                      // "fista" 加速近端梯度(Nesterov 动量 + 自适应重启)
+	LOGIT_ISTA                       // "ista" 原近端梯度实现，仅单点拟合可用
+	LOGIT_ERROR                      // "ERROR"
+)
+
+func (s LogitSolver) String() string {
+	switch s {
+	case LOGIT_IRLS_CD:
+		return "irls_cd"
+	case LOGIT_FISTA:
+		return "fista"
+	case LOGIT_ISTA:
+		return "ista"
+	default:
+		return "ERROR"
+	}
+}
+
+func GetMyLogitSolver(s string) LogitSolver {
+	switch s {
+	case "irls_cd":
+		return LOGIT_IRLS_CD
+	case "fista":
+		return LOGIT_FISTA
+	case "ista":
+		return LOGIT_ISTA
+	default:
+		return LOGIT_ERROR
+	}
+}
+
+// GLM 分布族
+type GLMFamily int
+
+const (
+	FAMILY_GAUSSIAN GLMFamily = iota // "gaussian" 正态，V(μ) = 1
+	FAMILY_POISSON                   // "poisson" 泊松，V(μ) = μ
+	FAMILY_NEGBIN                    // "negbin" 负二项(NB2)，V(μ) = μ + αμ²
+	FAMILY_GAMMA                     // "gamma" Gamma，V(μ) = μ²
+	FAMILY_ERROR                     // "ERROR"
+)
+
+func (s GLMFamily) String() string {
+	switch s {
+	case FAMILY_GAUSSIAN:
+		return "gaussian"
+	case FAMILY_POISSON:
+		return "poisson"
+	case FAMILY_NEGBIN:
+		return "negbin"
+	case FAMILY_GAMMA:
+		return "gamma"
+	default:
+		return "ERROR"
+	}
+}
+
+func GetMyGLMFamily(s string) GLMFamily {
+	switch s {
+	case "gaussian":
+		return FAMILY_GAUSSIAN
+	case "poisson":
+		return FAMILY_POISSON
+	case "negbin":
+		return FAMILY_NEGBIN
+	case "gamma":
+		return FAMILY_GAMMA
+	default:
+		return FAMILY_ERROR
+	}
+}
+
+// GLM 连接函数 η = g(μ)
+type GLMLink int
+
+const (
+	LINK_IDENTITY GLMLink = iota // "identity" η = μ
+	LINK_LOG                     // "log" η = ln μ
+	LINK_ERROR                   // "ERROR"
+)
+
+func (s GLMLink) String() string {
+	switch s {
+	case LINK_IDENTITY:
+		return "identity"
+	case LINK_LOG:
+		return "log"
+	default:
+		return "ERROR"
+	}
+}
+
+func GetMyGLMLink(s string) GLMLink {
+	switch s {
+	case "identity":
+		return LINK_IDENTITY
+	case "log":
+		return LINK_LOG
+	default:
+		return LINK_ERROR
+	}
+}
@@ -0,0 +1,164 @@
+// 最小二乘求解: 直接分解 X 而非显式求 (X'X)^-1
+//
+//	QR:       X = QR，Rβ = Q'y，(X'X)^-1 = R^-1 R^-T
+//	Cholesky: X'X = LL'，两次三角回代
+//	SVD:      X = UΣV'，β = VΣ⁺U'y，(X'X)⁺ = VΣ⁺²V'
+//
+// 数值秩判定阈值 max(n,k)·ε·σ_max(QR 用 |R_ii| 近似)，秩亏时 QR/Cholesky 退化为 SVD 最小范数解
+package ols
+
+import (
+	"math"
+	"ofeisInfra/infra/errorx"
+	"ofeisInfra/infra/errorx/errCode"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+type olsSolution struct {
+	beta   *mat.VecDense
+	invXTX *mat.Dense // (X'X)^-1，秩亏时为广义逆
+	rank   int
+	cond   float64
+	solver SolverType
+}
+
+func solveLeastSquares(matX *mat.Dense, matY *mat.VecDense, solver SolverType) (olsSolution, error) {
+	n, k := matX.Dims()
+	if n == 0 || k == 0 {
+		return olsSolution{}, errorx.New(errCode.EMPTY_VALUE, "输入数据为空")
+	}
+	switch solver {
+	case SOLVER_QR:
+		if n >= k {
+			if sol, ok := solveQR(matX, matY); ok {
+				return sol, nil
+			}
+		}
+	case SOLVER_CHOLESKY:
+		if sol, ok := solveCholesky(matX, matY); ok {
+			return sol, nil
+		}
+	case SOLVER_SVD:
+	default:
+		return olsSolution{}, errorx.New(errCode.INVALID_VALUE, "未知的最小二乘求解器")
+	}
+	return solveSVD(matX, matY)
+}
+
+func rankTol(n, k int, maxVal float64) float64 {
+	return float64(max(n, k)) * 2.220446049250313e-16 * maxVal
+}
+
+func solveQR(matX *mat.Dense, matY *mat.VecDense) (olsSolution, bool) {
+	n, k := matX.Dims()
+	var qr mat.QR
+	qr.Factorize(matX)
+	var Rfull mat.Dense
+	qr.RTo(&Rfull)
+	R := mat.NewTriDense(k, mat.Upper, nil)
+	for i := 0; i < k; i++ {
+		for j := i; j < k; j++ {
+			R.SetTri(i, j, Rfull.At(i, j))
+		}
+	}
+	maxDiag := 0.0
+	for i := 0; i < k; i++ {
+		maxDiag = math.Max(maxDiag, math.Abs(R.At(i, i)))
+	}
+	tol := rankTol(n, k, maxDiag)
+	for i := 0; i < k; i++ {
+		if math.Abs(R.At(i, i)) <= tol {
+			return olsSolution{}, false
+		}
+	}
+	beta := mat.NewVecDense(k, nil)
+	if err := qr.SolveVecTo(beta, false, matY); err != nil {
+		return olsSolution{}, false
+	}
+	var Rinv mat.TriDense
+	if err := Rinv.InverseTri(R); err != nil {
+		return olsSolution{}, false
+	}
+	inv := mat.NewDense(k, k, nil)
+	inv.Mul(&Rinv, Rinv.T())
+	return olsSolution{beta: beta, invXTX: inv, rank: k, cond: qr.Cond(), solver: SOLVER_QR}, true
+}
+
+func solveCholesky(matX *mat.Dense, matY *mat.VecDense) (olsSolution, bool) {
+	_, k := matX.Dims()
+	var XTX mat.SymDense
+	XTX.SymOuterK(1, matX.T())
+	var chol mat.Cholesky
+	if !chol.Factorize(&XTX) {
+		return olsSolution{}, false
+	}
+	cond := chol.Cond()
+	if math.IsInf(cond, 1) || cond*2.220446049250313e-16 >= 1 {
+		return olsSolution{}, false
+	}
+	var XTY mat.VecDense
+	XTY.MulVec(matX.T(), matY)
+	beta := mat.NewVecDense(k, nil)
+	if err := chol.SolveVecTo(beta, &XTY); err != nil {
+		return olsSolution{}, false
+	}
+	var invSym mat.SymDense
+	if err := chol.InverseTo(&invSym); err != nil {
+		return olsSolution{}, false
+	}
+	inv := mat.NewDense(k, k, nil)
+	inv.Copy(&invSym)
+	return olsSolution{beta: beta, invXTX: inv, rank: k, cond: math.Sqrt(cond), solver: SOLVER_CHOLESKY}, true
+}
+
+func solveSVD(matX *mat.Dense, matY *mat.VecDense) (olsSolution, error) {
+	n, k := matX.Dims()
+	var svd mat.SVD
+	if !svd.Factorize(matX, mat.SVDThin) {
+		return olsSolution{}, errorx.New(errCode.INVALID_VALUE, "SVD分解失败")
+	}
+	var U, V mat.Dense
+	svd.UTo(&U)
+	svd.VTo(&V)
+	sigma := svd.Values(nil)
+
+	tol := rankTol(n, k, sigma[0])
+	rank := 0
+	for _, s := range sigma {
+		if s > tol {
+			rank++
+		}
+	}
+	if rank == 0 {
+		return olsSolution{}, errorx.New(errCode.INVALID_VALUE, "设计矩阵秩为 0")
+	}
+	cond := math.Inf(1)
+	if len(sigma) == k && sigma[k-1] > 0 {
+		cond = sigma[0] / sigma[k-1]
+	}
+
+	// β = V Σ⁺ U'y
+	var uty mat.VecDense
+	uty.MulVec(U.T(), matY)
+	coef := mat.NewVecDense(len(sigma), nil)
+	for i := 0; i < rank; i++ {
+		coef.SetVec(i, uty.AtVec(i)/sigma[i])
+	}
+	beta := mat.NewVecDense(k, nil)
+	beta.MulVec(&V, coef)
+
+	// (X'X)⁺ = V Σ⁺² V'
+	inv := mat.NewDense(k, k, nil)
+	for a := 0; a < k; a++ {
+		for b := a; b < k; b++ {
+			s := 0.0
+			for i := 0; i < rank; i++ {
+				s += V.At(a, i) * V.At(b, i) / (sigma[i] * sigma[i])
+			}
+			inv.Set(a, b, s)
+			inv.Set(b, a, s)
+		}
+	}
+	return olsSolution{beta: beta, invXTX: inv, rank: rank, cond: cond, solver: SOLVER_SVD}, nil
+}
@@ -0,0 +1,287 @@
+package ols
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"ofeisInfra/infra/errorx"
+	"ofeisInfra/infra/errorx/errCode"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// ------------------- 原实现(07a9763 的 MultiRegressionMat): 显式求 (X'X)^-1，失败时退回 SVD 广义逆 -------------------
+func legacyMultiRegressionMat(matX *mat.Dense, matY *mat.VecDense) (MultiLinearModel, error) {
+	n, k := matX.Dims()
+	// 计算 (X'X)
+	var XT mat.Dense
+	XT.CloneFrom(matX.T())
+
+	var XTX mat.Dense
+	XTX.Mul(&XT, matX)
+
+	// (X'X)^(-1)
+	var invXTX mat.Dense
+	err := invXTX.Inverse(&XTX)
+	if err != nil {
+		pinv, errSVD := legacyPseudoInverse(&XTX)
+		if errSVD != nil {
+			return MultiLinearModel{}, errSVD
+		}
+		invXTX.CloneFrom(pinv)
+	}
+
+	// (X'Y)
+	var XTY mat.VecDense
+	XTY.MulVec(&XT, matY)
+
+	// β = (X'X)^(-1) * (X'Y)
+	var beta mat.VecDense
+	beta.MulVec(&invXTX, &XTY)
+
+	// 预测值 & 残差
+	Yhat := mat.NewVecDense(n, nil)
+	Yhat.MulVec(matX, &beta)
+	resid := mat.NewVecDense(n, nil)
+	resid.SubVec(matY, Yhat)
+
+	// RSS
+	RSS := mat.Dot(resid, resid)
+
+	// 残差方差 σ² = RSS / (n - k)
+	sigma2 := RSS / float64(n-k)
+
+	// 标准误 SE = sqrt( diag(σ² * (X'X)^(-1)) )
+	SE := make([]float64, k)
+	for i := 0; i < k; i++ {
+		SE[i] = math.Sqrt(sigma2 * invXTX.At(i, i))
+	}
+
+	// t统计量
+	tStats := make([]float64, k)
+	for i := 0; i < k; i++ {
+		tStats[i] = beta.AtVec(i) / SE[i]
+	}
+
+	// p值（双尾），使用 Student-t 分布
+	df := float64(n - k)
+	if df <= 0 {
+		return MultiLinearModel{}, errorx.New(errCode.INVALID_VALUE, fmt.Sprintf("自由度 df=%v 非法：样本数 n 必须大于参数数 k", df))
+	}
+
+	tdist := distuv.StudentsT{Mu: 0, Sigma: 1, Nu: df}
+
+	pValues := make([]float64, k)
+	for i := 0; i < k; i++ {
+		t := math.Abs(tStats[i])
+		p := 2 * tdist.Survival(t)
+		pValues[i] = p
+	}
+
+	// R² & 调整后R²
+	Ymean := mat.Sum(matY) / float64(n)
+	TSS := 0.0
+	for i := 0; i < n; i++ {
+		diff := matY.AtVec(i) - Ymean
+		TSS += diff * diff
+	}
+	RSq := 1 - RSS/TSS
+	AdjRSq := 1 - (1-RSq)*float64(n-1)/float64(n-k)
+
+	// AIC / BIC
+	logLik := -0.5 * float64(n) * (1 + math.Log(2*math.Pi*RSS/float64(n)))
+	AIC := -2*logLik + 2*float64(k)
+	BIC := -2*logLik + float64(k)*math.Log(float64(n))
+
+	// 提取 β
+	coeffs := make([]float64, k)
+	for i := 0; i < k; i++ {
+		coeffs[i] = beta.AtVec(i)
+	}
+	return MultiLinearModel{
+		Coeffs:      coeffs,
+		SE:          SE,
+		TStats:      tStats,
+		PValues:     pValues,
+		Resids:      resid.RawVector().Data,
+		AIC:         AIC,
+		BIC:         BIC,
+		Sigma2:      sigma2,
+		RSquared:    RSq,
+		AdjRSquared: AdjRSq,
+	}, nil
+}
+
+// 用SVD 求解广义逆矩阵
+func legacyPseudoInverse(A *mat.Dense) (*mat.Dense, error) {
+	var svd mat.SVD
+	ok := svd.Factorize(A, mat.SVDThin)
+	if !ok {
+		return nil, errorx.New(errCode.INVALID_VALUE, "SVD分解失败")
+	}
+
+	// 提取 U, Σ, Vᵀ
+	var u, v mat.Dense
+	svd.UTo(&u)
+	svd.VTo(&v)
+
+	// 取 Σ 的倒数
+	sigma := svd.Values(nil)
+	m, n := A.Dims()
+	sInv := mat.NewDense(n, m, nil)
+
+	tol := 1e-12 // 小奇异值截断阈值
+	for i, val := range sigma {
+		if val > tol {
+			sInv.Set(i, i, 1.0/val)
+		}
+	}
+
+	// 计算伪逆 A⁺ = V * Σ⁺ * Uᵀ
+	var temp mat.Dense
+	temp.Mul(&v, sInv)
+	var uT mat.Dense
+	uT.CloneFrom(u.T())
+
+	var pinv mat.Dense
+	pinv.Mul(&temp, &uT)
+
+	return &pinv, nil
+}
+
+// 良态设计: n×k 标准正态 + 常数列
+func wellConditioned(n, k int) (*mat.Dense, *mat.VecDense) {
+	r := rand.New(rand.NewSource(1))
+	X := mat.NewDense(n, k, nil)
+	y := mat.NewVecDense(n, nil)
+	for i := 0; i < n; i++ {
+		X.Set(i, 0, 1)
+		s := 0.0
+		for j := 1; j < k; j++ {
+			v := r.NormFloat64()
+			X.Set(i, j, v)
+			s += float64(j) * v
+		}
+		y.SetVec(i, s+r.NormFloat64())
+	}
+	return X, y
+}
+
+// 病态设计: t ∈ [1, 2] 上的多项式 1, t, ..., t^(k-1)，真值 β_j = 1，y 无噪声
+func illConditioned(n, k int) (*mat.Dense, *mat.VecDense) {
+	X := mat.NewDense(n, k, nil)
+	y := mat.NewVecDense(n, nil)
+	for i := 0; i < n; i++ {
+		t := 1 + float64(i)/float64(n-1)
+		s := 0.0
+		for j := 0; j < k; j++ {
+			v := math.Pow(t, float64(j))
+			X.Set(i, j, v)
+			s += v
+		}
+		y.SetVec(i, s)
+	}
+	return X, y
+}
+
+func maxAbsErr(beta []float64) float64 {
+	if beta == nil {
+		return math.Inf(1)
+	}
+	e := 0.0
+	for _, v := range beta {
+		e = math.Max(e, math.Abs(v-1))
+	}
+	return e
+}
+
+func TestSolversAgree(t *testing.T) {
+	X, y := wellConditioned(500, 6)
+	ref, err := MultiRegressionMatSolver(X, y, SOLVER_SVD)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []SolverType{SOLVER_QR, SOLVER_CHOLESKY} {
+		m, err := MultiRegressionMatSolver(X, y, s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if m.Rank != 6 || m.Solver != s {
+			t.Errorf("%s: rank=%d solver=%s", s, m.Rank, m.Solver)
+		}
+		for i := range m.Coeffs {
+			if math.Abs(m.Coeffs[i]-ref.Coeffs[i]) > 1e-10 || math.Abs(m.SE[i]-ref.SE[i]) > 1e-10 {
+				t.Errorf("%s: coef %d = %v±%v, svd %v±%v", s, i, m.Coeffs[i], m.SE[i], ref.Coeffs[i], ref.SE[i])
+			}
+		}
+	}
+}
+
+func TestRankDeficient(t *testing.T) {
+	X, y := wellConditioned(200, 3)
+	Xd := mat.NewDense(200, 4, nil)
+	for i := 0; i < 200; i++ {
+		Xd.Set(i, 0, X.At(i, 0))
+		Xd.Set(i, 1, X.At(i, 1))
+		Xd.Set(i, 2, X.At(i, 2))
+		Xd.Set(i, 3, 2*X.At(i, 1)) // 与第 1 列完全共线
+	}
+	m, err := MultiRegressionMat(Xd, y)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Rank != 3 || m.Solver != SOLVER_SVD || !math.IsInf(m.CondNum, 1) && m.CondNum < 1e12 {
+		t.Errorf("rank=%d solver=%s cond=%v", m.Rank, m.Solver, m.CondNum)
+	}
+	// 最小范数解: β_1 + 2β_3 = 1
+	if got := m.Coeffs[1] + 2*m.Coeffs[3]; math.Abs(got-1) > 0.1 {
+		t.Errorf("β1 + 2β3 = %v", got)
+	}
+}
+
+// 病态设计上的精度: 原实现对 X'X 求逆(条件数平方，求逆失败时退回 X'X 的 SVD 广义逆)，QR/SVD 直接分解 X
+// cholesky 在 cond·eps >= 1 时会退回 SVD，fallback=1 表示该子项实际未使用所选求解器
+func BenchmarkAccuracyIllConditioned(b *testing.B) {
+	X, y := illConditioned(200, 8)
+	b.Run("legacy", func(b *testing.B) {
+		var model MultiLinearModel
+		for i := 0; i < b.N; i++ {
+			model, _ = legacyMultiRegressionMat(X, y)
+		}
+		b.ReportMetric(maxAbsErr(model.Coeffs), "maxerr")
+	})
+	for _, s := range []SolverType{SOLVER_QR, SOLVER_CHOLESKY, SOLVER_SVD} {
+		b.Run(s.String(), func(b *testing.B) {
+			var model MultiLinearModel
+			for i := 0; i < b.N; i++ {
+				model, _ = MultiRegressionMatSolver(X, y, s)
+			}
+			fallback := 0.0
+			if model.Solver != s {
+				fallback = 1
+			}
+			b.ReportMetric(maxAbsErr(model.Coeffs), "maxerr")
+			b.ReportMetric(model.CondNum, "cond")
+			b.ReportMetric(fallback, "fallback")
+		})
+	}
+}
+
+// 完整回归(含 SE/t/p)的耗时
+func BenchmarkMultiRegressionMat(b *testing.B) {
+	X, y := wellConditioned(2000, 10)
+	b.Run("legacy", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_, _ = legacyMultiRegressionMat(X, y)
+		}
+	})
+	for _, s := range []SolverType{SOLVER_QR, SOLVER_CHOLESKY, SOLVER_SVD} {
+		b.Run(s.String(), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				_, _ = MultiRegressionMatSolver(X, y, s)
+			}
+		})
+	}
+}
@@ -0,0 +1,292 @@
+// 带遗忘因子的递推最小二乘(RLS)，用于逐笔更新对冲比例等时变系数
+// 每个观测 O(k²):
+//
+//	e_t = y_t - x_t'β_{t-1}                   (先验新息)
+//	g_t = P_{t-1}x_t / (λ + x_t'P_{t-1}x_t)
+//	β_t = β_{t-1} + g_t e_t
+//	P_t = (P_{t-1} - g_t x_t'P_{t-1}) / λ
+//	J_t = λJ_{t-1} + λe_t² / (λ + x_t'P_{t-1}x_t)   (指数加权 RSS 的精确递推)
+//
+// λ < 1 时旧样本权重按 λ^age 衰减，等效记忆长度约 1/(1-λ)
+// 自变量激励不足时 P 会按 1/λ 指数膨胀(windup)，迹超过 MaxTrace 或失去正定时重置为 Delta·I，β 保持不变
+package ols
+
+import (
+	"fmt"
+	"math"
+	"ofeisInfra/infra/errorx"
+	"ofeisInfra/infra/errorx/errCode"
+)
+
+type RLSOptions struct {
+	Lambda    float64 // 遗忘因子 (0,1]，1 为不遗忘
+	Delta     float64 // 初始/重置时 P = Delta·I，越大先验越弱
+	MaxTrace  float64 // tr(P) 上限，超过即重置，<=0 不检查
+	WithConst bool    // 是否在 x 最左侧添加常数项
+}
+
+// 默认参数: λ=0.999(记忆约 1000 个观测)，Delta=1e3，MaxTrace=1e8
+func DefaultRLSOptions() RLSOptions {
+	return RLSOptions{Lambda: 0.999, Delta: 1e3, MaxTrace: 1e8, WithConst: true}
+}
+
+type RLS struct {
+	opts   RLSOptions
+	k      int         // 参数个数(含常数项)
+	beta   []float64   // 当前系数
+	p      [][]float64 // 系数协方差(未乘 σ²)
+	rss    float64     // 指数加权残差平方和 J_t
+	sumW   float64     // 指数权重和 Σλ^i
+	nObs   int
+	resets int
+
+	x  []float64 // 工作区: 含常数项的 x
+	px []float64 // 工作区: P x
+}
+
+// 快照，字段均导出，可直接 JSON 序列化后持久化
+type RLSState struct {
+	Options RLSOptions
+	K       int // 参数个数(含常数项)
+	Beta    []float64
+	P       [][]float64
+	RSS     float64
+	SumW    float64
+	NObs    int
+	Resets  int
+}
+
+// input: nRegressors 自变量个数(不含常数项)
+func NewRLS(nRegressors int, opts RLSOptions) (*RLS, error) {
+	if nRegressors < 0 || (nRegressors == 0 && !opts.WithConst) {
+		return nil, errorx.New(errCode.INVALID_VALUE, "参数个数必须 > 0")
+	}
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
+	k := nRegressors
+	if opts.WithConst {
+		k++
+	}
+	r := &RLS{opts: opts, k: k, beta: make([]float64, k), p: make([][]float64, k)}
+	for i := range r.p {
+		r.p[i] = make([]float64, k)
+	}
+	r.resetCov()
+	r.initWork()
+	return r, nil
+}
+
+// 由快照恢复
+func RestoreRLS(state RLSState) (*RLS, error) {
+	if err := state.Options.validate(); err != nil {
+		return nil, err
+	}
+	k := state.K
+	if k <= 0 || len(state.Beta) != k || len(state.P) != k {
+		return nil, errorx.New(errCode.INVALID_VALUE, "快照维度不一致")
+	}
+	r := &RLS{
+		opts:   state.Options,
+		k:      k,
+		beta:   append([]float64(nil), state.Beta...),
+		p:      make([][]float64, k),
+		rss:    state.RSS,
+		sumW:   state.SumW,
+		nObs:   state.NObs,
+		resets: state.Resets,
+	}
+	for i := 0; i < k; i++ {
+		if len(state.P[i]) != k {
+			return nil, errorx.New(errCode.INVALID_VALUE, "快照协方差矩阵维度不一致")
+		}
+		r.p[i] = append([]float64(nil), state.P[i]...)
+		if !isFinite(r.beta[i]) || r.p[i][i] <= 0 {
+			return nil, errorx.New(errCode.INVALID_VALUE, "快照系数非有限或协方差非正定")
+		}
+	}
+	r.initWork()
+	return r, nil
+}
+
+func (o RLSOptions) validate() error {
+	if !(o.Lambda > 0 && o.Lambda <= 1) {
+		return errorx.New(errCode.INVALID_VALUE, fmt.Sprintf("遗忘因子 λ=%v 须在 (0,1] 内", o.Lambda))
+	}
+	if !(o.Delta > 0) || math.IsInf(o.Delta, 1) {
+		return errorx.New(errCode.INVALID_VALUE, "Delta 必须为正的有限值")
+	}
+	return nil
+}
+
+func (r *RLS) initWork() {
+	r.x = make([]float64, r.k)
+	r.px = make([]float64, r.k)
+}
+
+// 加入一个观测，返回先验新息 e_t = y_t - x_t'β_{t-1}
+// x 不含常数项(WithConst 时自动添加)；x/y 含 NaN/Inf 时不更新并返回错误
+func (r *RLS) Update(x []float64, y float64) (float64, error) {
+	if err := r.fillX(x); err != nil {
+		return math.NaN(), err
+	}
+	if !isFinite(y) {
+		return math.NaN(), errorx.New(errCode.INVALID_VALUE, "y 含 NaN/Inf")
+	}
+	k, lambda := r.k, r.opts.Lambda
+
+	// P x 与 λ + x'Px
+	denom := lambda
+	for i := 0; i < k; i++ {
+		s := 0.0
+		for j := 0; j < k; j++ {
+			s += r.p[i][j] * r.x[j]
+		}
+		r.px[i] = s
+		denom += r.x[i] * s
+	}
+	e := y
+	for i := 0; i < k; i++ {
+		e -= r.x[i] * r.beta[i]
+	}
+
+	// β 与 P 更新，P 只算上三角再镜像以保持对称
+	for i := 0; i < k; i++ {
+		r.beta[i] += r.px[i] / denom * e
+	}
+	for i := 0; i < k; i++ {
+		for j := i; j < k; j++ {
+			v := (r.p[i][j] - r.px[i]*r.px[j]/denom) / lambda
+			r.p[i][j], r.p[j][i] = v, v
+		}
+	}
+
+	r.sumW = lambda*r.sumW + 1
+	r.rss = lambda*r.rss + lambda*e*e/denom
+	r.nObs++
+	r.guardCov()
+	return e, nil
+}
+
+// 一步预测 x'β
+func (r *RLS) Predict(x []float64) (float64, error) {
+	if err := r.fillX(x); err != nil {
+		return math.NaN(), err
+	}
+	fit := 0.0
+	for i := 0; i < r.k; i++ {
+		fit += r.x[i] * r.beta[i]
+	}
+	return fit, nil
+}
+
+// 当前系数(副本)，WithConst 时常数项在首位
+func (r *RLS) Coeffs() []float64 {
+	return append([]float64(nil), r.beta...)
+}
+
+// 新息方差 σ² = J_t / (Σλ^i - k)，λ=1 时等于全样本 OLS 的 σ²；有效样本不足时为 NaN
+func (r *RLS) InnovationVar() float64 {
+	df := r.sumW - float64(r.k)
+	if df <= 0 {
+		return math.NaN()
+	}
+	return r.rss / df
+}
+
+// x 处的一步预测误差方差 σ²(1 + x'Px)
+func (r *RLS) PredictVar(x []float64) (float64, error) {
+	if err := r.fillX(x); err != nil {
+		return math.NaN(), err
+	}
+	q := 0.0
+	for i := 0; i < r.k; i++ {
+		for j := 0; j < r.k; j++ {
+			q += r.x[i] * r.p[i][j] * r.x[j]
+		}
+	}
+	return r.InnovationVar() * (1 + q), nil
+}
+
+// 系数协方差近似 σ²·P，σ² 取 InnovationVar
+func (r *RLS) Cov() [][]float64 {
+	s2 := r.InnovationVar()
+	cov := make([][]float64, r.k)
+	for i := range cov {
+		cov[i] = make([]float64, r.k)
+		for j := range cov[i] {
+			cov[i][j] = s2 * r.p[i][j]
+		}
+	}
+	return cov
+}
+
+func (r *RLS) NObs() int   { return r.nObs }
+func (r *RLS) Resets() int { return r.resets }
+
+// 手动重置协方差(如检测到结构突变)，保留 β 与 J
+func (r *RLS) ResetCov() {
+	r.resetCov()
+	r.resets++
+}
+
+// 深拷贝当前状态
+func (r *RLS) Snapshot() RLSState {
+	p := make([][]float64, r.k)
+	for i := range p {
+		p[i] = append([]float64(nil), r.p[i]...)
+	}
+	return RLSState{
+		Options: r.opts,
+		K:       r.k,
+		Beta:    append([]float64(nil), r.beta...),
+		P:       p,
+		RSS:     r.rss,
+		SumW:    r.sumW,
+		NObs:    r.nObs,
+		Resets:  r.resets,
+	}
+}
+
+func (r *RLS) fillX(x []float64) error {
+	off := 0
+	if r.opts.WithConst {
+		r.x[0] = 1
+		off = 1
+	}
+	if len(x)+off != r.k {
+		return errorx.New(errCode.INVALID_VALUE, fmt.Sprintf("x 长度 %d 与自变量个数 %d 不符", len(x), r.k-off))
+	}
+	for i, v := range x {
+		if !isFinite(v) {
+			return errorx.New(errCode.INVALID_VALUE, "x 含 NaN/Inf")
+		}
+		r.x[i+off] = v
+	}
+	return nil
+}
+
+func (r *RLS) resetCov() {
+	for i := range r.p {
+		for j := range r.p[i] {
+			r.p[i][j] = 0
+		}
+		r.p[i][i] = r.opts.Delta
+	}
+}
+
+// windup / 数值失稳保护: 迹超限、对角元非正或非有限时重置
+func (r *RLS) guardCov() {
+	tr := 0.0
+	for i := 0; i < r.k; i++ {
+		d := r.p[i][i]
+		if !(d > 0) || math.IsInf(d, 1) {
+			r.ResetCov()
+			return
+		}
+		tr += d
+	}
+	if r.opts.MaxTrace > 0 && tr > r.opts.MaxTrace {
+		r.ResetCov()
+	}
+}
@@ -0,0 +1,75 @@
+package ols
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+)
+
+// λ=1、Delta 很大时 RLS 收敛到全样本 OLS
+func TestRLSMatchesOLS(t *testing.T) {
+	X, Y := rollingData(500)
+	rls, err := NewRLS(2, RLSOptions{Lambda: 1, Delta: 1e8, WithConst: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range Y {
+		if _, err := rls.Update(X[i], Y[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	m, _ := MultiRegression(X, Y, true)
+	for j, c := range rls.Coeffs() {
+		if math.Abs(c-m.Coeffs[j]) > 1e-6 {
+			t.Errorf("coef %d: %v want %v", j, c, m.Coeffs[j])
+		}
+	}
+	if v := rls.InnovationVar(); math.Abs(v-m.Sigma2)/m.Sigma2 > 1e-4 {
+		t.Errorf("innovation var %v, OLS σ² %v", v, m.Sigma2)
+	}
+}
+
+// 快照经 JSON 往返后继续更新，结果与未中断的实例一致
+func TestRLSSnapshotRestore(t *testing.T) {
+	X, Y := rollingData(400)
+	a, _ := NewRLS(2, DefaultRLSOptions())
+	for i := 0; i < 200; i++ {
+		_, _ = a.Update(X[i], Y[i])
+	}
+	raw, err := json.Marshal(a.Snapshot())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var state RLSState
+	if err := json.Unmarshal(raw, &state); err != nil {
+		t.Fatal(err)
+	}
+	b, err := RestoreRLS(state)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 200; i < len(Y); i++ {
+		ea, _ := a.Update(X[i], Y[i])
+		eb, _ := b.Update(X[i], Y[i])
+		if ea != eb {
+			t.Fatalf("t=%d innovation %v != %v", i, ea, eb)
+		}
+	}
+	if _, err := b.Update([]float64{math.NaN(), 0}, 1); err == nil || b.NObs() != a.NObs() {
+		t.Error("NaN 输入应报错且不更新")
+	}
+}
+
+// 自变量恒为 0 时 P 按 1/λ 膨胀，超过 MaxTrace 后重置
+func TestRLSWindupReset(t *testing.T) {
+	rls, _ := NewRLS(1, RLSOptions{Lambda: 0.9, Delta: 10, MaxTrace: 1e4, WithConst: true})
+	for i := 0; i < 200; i++ {
+		_, _ = rls.Update([]float64{0}, 1)
+	}
+	if rls.Resets() == 0 {
+		t.Error("应发生协方差重置")
+	}
+	if c := rls.Coeffs(); math.Abs(c[0]-1) > 1e-6 {
+		t.Errorf("常数项 %v want 1", c[0])
+	}
+}
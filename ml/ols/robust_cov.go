@@ -0,0 +1,240 @@
+// 异方差/自相关稳健协方差(三明治估计): Cov = (X'X)^-1 · Meat · (X'X)^-1
+//
+//	HC0..HC3: Meat = Σ ω_i e_i² x_i x_i'
+//	HAC:      Meat = Γ_0 + Σ_{l=1}^{L} (1 - l/(L+1)) (Γ_l + Γ_l')，Γ_l = Σ_t e_t e_{t-l} x_t x_{t-l}'
+//	cluster:  Meat = G/(G-1)·(n-1)/(n-k) · Σ_g (X_g'e_g)(X_g'e_g)'
+//
+// 稳健口径下 z统计量与 p值使用标准正态分布(与 statsmodels 默认 use_t=False 一致)
+package ols
+
+import (
+	"fmt"
+	"math"
+	"ofeisInfra/infra/errorx"
+	"ofeisInfra/infra/errorx/errCode"
+
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+type CovOptions struct {
+	Type    CovType // 协方差估计方法
+	MaxLags int     // HAC 带宽 L; < 0 时按 Newey-West(1994) 自动选择
+	Groups  []int   // 聚类标签(如按币种或按日编号)，长度与样本一致，仅 COV_CLUSTER 使用
+}
+
+// 带稳健协方差的多元回归
+func MultiRegressionMatRobust(matX *mat.Dense, matY *mat.VecDense, opts CovOptions) (MultiLinearModel, error) {
+	model, err := MultiRegressionMat(matX, matY)
+	if err != nil {
+		return MultiLinearModel{}, err
+	}
+	if opts.Type == COV_NONROBUST {
+		return model, nil
+	}
+	cov, err := RobustCov(matX, model.Resids, opts)
+	if err != nil {
+		return MultiLinearModel{}, err
+	}
+	model.applyCov(cov, opts.Type)
+	return model, nil
+}
+
+// 带稳健协方差的多元回归(行数据接口)
+func MultiRegressionRobust(X [][]float64, Y []float64, withConst bool, opts CovOptions) (MultiLinearModel, error) {
+	model, err := MultiRegression(X, Y, withConst)
+	if err != nil {
+		return MultiLinearModel{}, err
+	}
+	if opts.Type == COV_NONROBUST {
+		return model, nil
+	}
+	if withConst {
+		X = addConstantColumn(X)
+	}
+	n, k := len(X), len(X[0])
+	matX := mat.NewDense(n, k, nil)
+	for i := 0; i < n; i++ {
+		matX.SetRow(i, X[i])
+	}
+	cov, err := RobustCov(matX, model.Resids, opts)
+	if err != nil {
+		return MultiLinearModel{}, err
+	}
+	model.applyCov(cov, opts.Type)
+	return model, nil
+}
+
+// 稳健协方差矩阵
+// input: matX 设计矩阵; resid OLS 残差; opts 估计方法
+func RobustCov(matX *mat.Dense, resid []float64, opts CovOptions) ([][]float64, error) {
+	n, k := matX.Dims()
+	if len(resid) != n {
+		return nil, errorx.New(errCode.INVALID_VALUE, "残差长度与样本量不一致")
+	}
+	if n <= k {
+		return nil, errorx.New(errCode.INVALID_VALUE, "样本数 n 必须大于参数数 k")
+	}
+	bread, err := invertXTX(matX)
+	if err != nil {
+		return nil, err
+	}
+
+	meat := mat.NewSymDense(k, nil)
+	switch opts.Type {
+	case COV_HC0, COV_HC1, COV_HC2, COV_HC3:
+		hx := mat.NewVecDense(k, nil)
+		for i := 0; i < n; i++ {
+			x := matX.RowView(i)
+			w := 1.0
+			if opts.Type == COV_HC2 || opts.Type == COV_HC3 {
+				hx.MulVec(bread, x)
+				h := mat.Dot(x, hx) // 杠杆值 h_ii
+				if h >= 1 {
+					return nil, errorx.New(errCode.INVALID_VALUE, fmt.Sprintf("第 %d 个样本杠杆值为 1，HC2/HC3 无定义", i))
+				}
+				w = 1 / (1 - h)
+				if opts.Type == COV_HC3 {
+					w *= w
+				}
+			}
+			meat.SymRankOne(meat, w*resid[i]*resid[i], x)
+		}
+		if opts.Type == COV_HC1 {
+			meat.ScaleSym(float64(n)/float64(n-k), meat)
+		}
+	case COV_HAC:
+		L := opts.MaxLags
+		if L < 0 {
+			L = neweyWestBandwidth(matX, resid)
+		}
+		hacMeat(meat, matX, resid, L)
+	case COV_CLUSTER:
+		if len(opts.Groups) != n {
+			return nil, errorx.New(errCode.INVALID_VALUE, "聚类标签长度与样本量不一致")
+		}
+		scores := make(map[int]*mat.VecDense)
+		for i := 0; i < n; i++ {
+			g := opts.Groups[i]
+			if scores[g] == nil {
+				scores[g] = mat.NewVecDense(k, nil)
+			}
+			scores[g].AddScaledVec(scores[g], resid[i], matX.RowView(i))
+		}
+		G := len(scores)
+		if G < 2 {
+			return nil, errorx.New(errCode.INVALID_VALUE, "聚类个数须 >= 2")
+		}
+		for _, s := range scores {
+			meat.SymRankOne(meat, 1, s)
+		}
+		adj := float64(G) / float64(G-1) * float64(n-1) / float64(n-k)
+		meat.ScaleSym(adj, meat)
+	default:
+		return nil, errorx.New(errCode.INVALID_VALUE, "未知的协方差估计方法")
+	}
+
+	var tmp, cov mat.Dense
+	tmp.Mul(bread, meat)
+	cov.Mul(&tmp, bread)
+	out := make([][]float64, k)
+	for i := range out {
+		out[i] = make([]float64, k)
+		for j := range out[i] {
+			out[i][j] = 0.5 * (cov.At(i, j) + cov.At(j, i))
+		}
+	}
+	return out, nil
+}
+
+// Newey-West HAC 的 meat 矩阵，Bartlett 核
+func hacMeat(meat *mat.SymDense, matX *mat.Dense, resid []float64, L int) {
+	n, k := matX.Dims()
+	// u_t = x_t e_t
+	u := make([]*mat.VecDense, n)
+	for t := 0; t < n; t++ {
+		u[t] = mat.NewVecDense(k, nil)
+		u[t].ScaleVec(resid[t], matX.RowView(t))
+		meat.SymRankOne(meat, 1, u[t])
+	}
+	for l := 1; l <= L && l < n; l++ {
+		w := 1 - float64(l)/float64(L+1)
+		for t := l; t < n; t++ {
+			// Γ_l + Γ_l' 的对称秩二更新
+			meat.RankTwo(meat, w, u[t], u[t-l])
+		}
+	}
+}
+
+// Newey-West(1994) 自动带宽(Bartlett 核)
+// 对 v_t = Σ_j x_tj e_t 估计 s0 = σ_0 + 2Σσ_j, s1 = 2Σ jσ_j，j <= floor(4(n/100)^(2/9))
+// L = floor(1.1447·((s1/s0)²·n)^(1/3))
+func neweyWestBandwidth(matX *mat.Dense, resid []float64) int {
+	n, k := matX.Dims()
+	v := make([]float64, n)
+	for t := 0; t < n; t++ {
+		s := 0.0
+		for j := 0; j < k; j++ {
+			s += matX.At(t, j)
+		}
+		v[t] = s * resid[t]
+	}
+	lagMax := int(math.Floor(4 * math.Pow(float64(n)/100, 2.0/9)))
+	sigma := func(j int) float64 {
+		s := 0.0
+		for t := j; t < n; t++ {
+			s += v[t] * v[t-j]
+		}
+		return s / float64(n)
+	}
+	s0 := sigma(0)
+	s1 := 0.0
+	for j := 1; j <= lagMax && j < n; j++ {
+		sj := sigma(j)
+		s0 += 2 * sj
+		s1 += 2 * float64(j) * sj
+	}
+	if s0 == 0 {
+		return 0
+	}
+	L := int(math.Floor(1.1447 * math.Cbrt((s1/s0)*(s1/s0)*float64(n))))
+	return max(0, min(L, n-1))
+}
+
+// 用协方差矩阵替换 SE / TStats / PValues
+func (m *MultiLinearModel) applyCov(cov [][]float64, covType CovType) {
+	k := len(m.Coeffs)
+	m.Cov = cov
+	m.CovType = covType
+	m.SE = make([]float64, k)
+	m.TStats = make([]float64, k)
+	m.PValues = make([]float64, k)
+	for i := 0; i < k; i++ {
+		m.SE[i] = math.Sqrt(cov[i][i])
+		m.TStats[i] = m.Coeffs[i] / m.SE[i]
+		m.PValues[i] = 2 * distuv.UnitNormal.Survival(math.Abs(m.TStats[i]))
+	}
+}
+
+// (X'X)^-1，秩亏时为广义逆
+func invertXTX(matX *mat.Dense) (*mat.Dense, error) {
+	n, _ := matX.Dims()
+	sol, err := solveLeastSquares(matX, mat.NewVecDense(n, nil), SOLVER_QR)
+	if err != nil {
+		return nil, err
+	}
+	return sol.invXTX, nil
+}
+
+// σ²·(X'X)^-1
+func scaledCov(sigma2 float64, invXTX *mat.Dense) [][]float64 {
+	k, _ := invXTX.Dims()
+	out := make([][]float64, k)
+	for i := range out {
+		out[i] = make([]float64, k)
+		for j := range out[i] {
+			out[i][j] = sigma2 * invXTX.At(i, j)
+		}
+	}
+	return out
+}
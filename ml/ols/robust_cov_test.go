@@ -0,0 +1,82 @@
+package ols
+
+import (
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// y = 1 + 0.8x + e，e 为 AR(1)(ρ=0.5) 且方差随 |x| 增大，n=20(保留 4 位小数)
+var robustX = []float64{
+	-0.4759, -1.0771, -1.3359, 1.6553, 0.3118, 0.7605, 0.2122, -0.4658,
+	0.9265, 0.3057, 1.1248, 0.6463, 1.3572, -0.2424, -1.3798, -1.4007,
+	0.8927, -1.33, 0.6186, 0.4831,
+}
+
+var robustY = []float64{
+	1.4188, 1.0056, 2.0046, 3.7588, 2.1271, 1.6929, 1.1191, -0.1788,
+	2.0442, 1.8407, 3.3794, 3.1233, 2.0051, -0.2637, 0.0646, 0.212,
+	1.2243, -1.0881, 1.4567, 2.3065,
+}
+
+// 每 4 个连续样本为一组
+var robustGroups = []int{0, 0, 0, 0, 1, 1, 1, 1, 2, 2, 2, 2, 3, 3, 3, 3, 4, 4, 4, 4}
+
+func robustDesign() (*mat.Dense, *mat.VecDense) {
+	matX := mat.NewDense(len(robustX), 2, nil)
+	for i, v := range robustX {
+		matX.Set(i, 0, 1)
+		matX.Set(i, 1, v)
+	}
+	return matX, mat.NewVecDense(len(robustY), append([]float64(nil), robustY...))
+}
+
+// 对照值由独立实现按三明治公式计算，定义同 statsmodels cov_type="HC0".."HC3"、"HAC"(Bartlett 核)、
+// "cluster"(G/(G-1)·(n-1)/(n-k) 修正)；Newey-West 自动带宽为 3
+func TestRobustCovReference(t *testing.T) {
+	matX, matY := robustDesign()
+	tests := []struct {
+		name string
+		opts CovOptions
+		cov  [3]float64 // Cov[0][0], Cov[0][1], Cov[1][1]
+	}{
+		{"HC0", CovOptions{Type: COV_HC0}, [3]float64{0.03807128037501174, -0.015879258769847696, 0.04729302782411386}},
+		{"HC1", CovOptions{Type: COV_HC1}, [3]float64{0.04230142263890193, -0.017643620855386326, 0.052547808693459847}},
+		{"HC2", CovOptions{Type: COV_HC2}, [3]float64{0.04306554735850137, -0.018945327975755346, 0.05547372205943231}},
+		{"HC3", CovOptions{Type: COV_HC3}, [3]float64{0.048849297035554975, -0.022594619227273672, 0.06515567977371786}},
+		{"HAC L=2", CovOptions{Type: COV_HAC, MaxLags: 2}, [3]float64{0.061922558749726436, -0.02241755994227787, 0.030338127033993634}},
+		{"HAC auto", CovOptions{Type: COV_HAC, MaxLags: -1}, [3]float64{0.06126928655230697, -0.025461456723987252, 0.02861269748801256}},
+		{"cluster", CovOptions{Type: COV_CLUSTER, Groups: robustGroups}, [3]float64{0.10327320358657698, -0.028461081234456856, 0.033725659847182586}},
+	}
+	for _, tt := range tests {
+		model, err := MultiRegressionMatRobust(matX, matY, tt.opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := [3]float64{model.Cov[0][0], model.Cov[0][1], model.Cov[1][1]}
+		for i := range got {
+			if math.Abs(got[i]-tt.cov[i]) > 1e-12 {
+				t.Errorf("%s: cov %v, want %v", tt.name, got, tt.cov)
+				break
+			}
+		}
+		if model.Cov[1][0] != model.Cov[0][1] || model.CovType != tt.opts.Type {
+			t.Errorf("%s: cov not symmetric or type %v", tt.name, model.CovType)
+		}
+		for j := range model.SE {
+			if math.Abs(model.SE[j]-math.Sqrt(model.Cov[j][j])) > 1e-15 || model.TStats[j] != model.Coeffs[j]/model.SE[j] {
+				t.Errorf("%s: se/t inconsistent with cov", tt.name)
+			}
+		}
+	}
+
+	_, err := MultiRegressionMatRobust(matX, matY, CovOptions{Type: COV_CLUSTER, Groups: robustGroups[:10]})
+	if err == nil {
+		t.Error("cluster labels of wrong length accepted")
+	}
+	model, _ := MultiRegressionMat(matX, matY)
+	if L := neweyWestBandwidth(matX, model.Resids); L != 3 {
+		t.Errorf("Newey-West bandwidth %d, want 3", L)
+	}
+}
@@ -0,0 +1,211 @@
+// 滚动窗口 / 扩张窗口 OLS
+// 维护窗口内的 X'X、X'y、y'y 与 Σy，行进出窗口时 O(k²) 增删，每个时点再做一次 k×k Cholesky 求解，
+// 总耗时与窗口长度无关。为抑制长序列上反复增删的累积误差，每移出 window 行后从窗口数据重算一次累加量
+package ols
+
+import (
+	"fmt"
+	"math"
+	"ofeisInfra/infra/errorx"
+	"ofeisInfra/infra/errorx/errCode"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+type RollingResult struct {
+	Window   int         // 窗口长度(按时点计，含 NaN 行)，扩张窗口为 0
+	MinObs   int         // 每个窗口的最少有效样本数
+	NObs     []int       // 各时点窗口内的有效样本数
+	Coeffs   [][]float64 // Coeffs[t] 为截至 t(含)的窗口回归系数，不足 MinObs 或奇异时全为 NaN
+	SE       [][]float64 // 标准误
+	RSquared []float64
+	Sigma2   []float64 // 残差方差 RSS / (n - k)
+	Resids   []float64 // y_t - x_t'β_t，t 行含 NaN 或该时点无估计时为 NaN
+}
+
+// 滚动窗口 OLS
+// input: X 行数据(不含常数列); Y 因变量; window 窗口长度; minObs 窗口最少有效样本数(<=0 取 k+1，须 > k);
+// withConst 是否在最左侧添加常数列。任一列含 NaN/Inf 的行不进入窗口
+func RollingOLS(X [][]float64, Y []float64, window, minObs int, withConst bool) (RollingResult, error) {
+	if window <= 0 {
+		return RollingResult{}, errorx.New(errCode.INVALID_VALUE, "窗口长度必须 > 0")
+	}
+	return rollingOLS(X, Y, window, minObs, withConst)
+}
+
+// 扩张窗口 OLS，t 时点使用 [0, t] 内的全部有效样本
+func ExpandingOLS(X [][]float64, Y []float64, minObs int, withConst bool) (RollingResult, error) {
+	return rollingOLS(X, Y, 0, minObs, withConst)
+}
+
+// window = 0 表示扩张窗口
+func rollingOLS(X [][]float64, Y []float64, window, minObs int, withConst bool) (RollingResult, error) {
+	n := len(Y)
+	if n == 0 || len(X) == 0 {
+		return RollingResult{}, errorx.New(errCode.EMPTY_VALUE, "输入数据为空")
+	}
+	if n != len(X) {
+		return RollingResult{}, errorx.New(errCode.INVALID_VALUE, "数据长度不匹配")
+	}
+	if withConst {
+		X = addConstantColumn(X)
+	}
+	k := len(X[0])
+	for i := 0; i < n; i++ {
+		if len(X[i]) != k {
+			return RollingResult{}, errorx.New(errCode.INVALID_VALUE, "X 各行长度不一致")
+		}
+	}
+	if minObs <= 0 {
+		minObs = k + 1
+	}
+	if minObs <= k {
+		return RollingResult{}, errorx.New(errCode.INVALID_VALUE, fmt.Sprintf("最少样本数 %d 须大于参数数 %d", minObs, k))
+	}
+	if window > 0 && window < minObs {
+		return RollingResult{}, errorx.New(errCode.INVALID_VALUE, fmt.Sprintf("窗口长度 %d 小于最少样本数 %d", window, minObs))
+	}
+
+	valid := make([]bool, n)
+	for i := 0; i < n; i++ {
+		valid[i] = isFinite(Y[i])
+		for _, v := range X[i] {
+			valid[i] = valid[i] && isFinite(v)
+		}
+	}
+
+	result := RollingResult{
+		Window:   window,
+		MinObs:   minObs,
+		NObs:     make([]int, n),
+		Coeffs:   make([][]float64, n),
+		SE:       make([][]float64, n),
+		RSquared: make([]float64, n),
+		Sigma2:   make([]float64, n),
+		Resids:   make([]float64, n),
+	}
+	acc := newWindowAcc(k)
+	removed := 0
+	for t := 0; t < n; t++ {
+		if valid[t] {
+			acc.update(X[t], Y[t], 1)
+		}
+		if window > 0 && t >= window {
+			if old := t - window; valid[old] {
+				acc.update(X[old], Y[old], -1)
+				removed++
+			}
+			if removed >= window {
+				acc.reset()
+				for i := t - window + 1; i <= t; i++ {
+					if valid[i] {
+						acc.update(X[i], Y[i], 1)
+					}
+				}
+				removed = 0
+			}
+		}
+
+		result.NObs[t] = acc.n
+		result.Coeffs[t] = nanSlice(k)
+		result.SE[t] = nanSlice(k)
+		result.RSquared[t], result.Sigma2[t], result.Resids[t] = math.NaN(), math.NaN(), math.NaN()
+		if acc.n < minObs {
+			continue
+		}
+		beta, se, r2, sigma2, ok := acc.solve()
+		if !ok {
+			continue
+		}
+		result.Coeffs[t], result.SE[t] = beta, se
+		result.RSquared[t], result.Sigma2[t] = r2, sigma2
+		if valid[t] {
+			fit := 0.0
+			for j := 0; j < k; j++ {
+				fit += X[t][j] * beta[j]
+			}
+			result.Resids[t] = Y[t] - fit
+		}
+	}
+	return result, nil
+}
+
+// 窗口充分统计量
+type windowAcc struct {
+	k   int
+	n   int
+	xtx *mat.SymDense
+	xty []float64
+	yy  float64
+	sy  float64
+}
+
+func newWindowAcc(k int) *windowAcc {
+	return &windowAcc{k: k, xtx: mat.NewSymDense(k, nil), xty: make([]float64, k)}
+}
+
+// sign = +1 加入一行，-1 移出一行
+func (a *windowAcc) update(x []float64, y float64, sign float64) {
+	for i := 0; i < a.k; i++ {
+		for j := i; j < a.k; j++ {
+			a.xtx.SetSym(i, j, a.xtx.At(i, j)+sign*x[i]*x[j])
+		}
+		a.xty[i] += sign * x[i] * y
+	}
+	a.yy += sign * y * y
+	a.sy += sign * y
+	a.n += int(sign)
+}
+
+func (a *windowAcc) reset() {
+	a.xtx.Zero()
+	for i := range a.xty {
+		a.xty[i] = 0
+	}
+	a.yy, a.sy, a.n = 0, 0, 0
+}
+
+// 由累加量求解: RSS = y'y - β'X'y，TSS = y'y - n·ȳ²
+func (a *windowAcc) solve() (beta, se []float64, r2, sigma2 float64, ok bool) {
+	var chol mat.Cholesky
+	if !chol.Factorize(a.xtx) {
+		return nil, nil, 0, 0, false
+	}
+	if cond := chol.Cond(); math.IsInf(cond, 1) || cond*2.220446049250313e-16 >= 1 {
+		return nil, nil, 0, 0, false
+	}
+	b := mat.NewVecDense(a.k, nil)
+	if err := chol.SolveVecTo(b, mat.NewVecDense(a.k, append([]float64(nil), a.xty...))); err != nil {
+		return nil, nil, 0, 0, false
+	}
+	var inv mat.SymDense
+	if err := chol.InverseTo(&inv); err != nil {
+		return nil, nil, 0, 0, false
+	}
+	beta = b.RawVector().Data
+	rss := a.yy
+	for i := 0; i < a.k; i++ {
+		rss -= beta[i] * a.xty[i]
+	}
+	rss = math.Max(rss, 0)
+	sigma2 = rss / float64(a.n-a.k)
+	se = make([]float64, a.k)
+	for i := 0; i < a.k; i++ {
+		se[i] = math.Sqrt(sigma2 * inv.At(i, i))
+	}
+	tss := a.yy - a.sy*a.sy/float64(a.n)
+	r2 = 1 - rss/tss
+	return beta, se, r2, sigma2, true
+}
+
+func isFinite(v float64) bool {
+	return !math.IsNaN(v) && !math.IsInf(v, 0)
+}
+
+func nanSlice(k int) []float64 {
+	s := make([]float64, k)
+	for i := range s {
+		s[i] = math.NaN()
+	}
+	return s
+}
@@ -0,0 +1,100 @@
+package ols
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func rollingData(n int) ([][]float64, []float64) {
+	r := rand.New(rand.NewSource(2))
+	X := make([][]float64, n)
+	Y := make([]float64, n)
+	for i := 0; i < n; i++ {
+		x1, x2 := r.NormFloat64(), r.NormFloat64()
+		X[i] = []float64{x1, x2}
+		Y[i] = 0.5 + 1.5*x1 - x2 + 0.3*r.NormFloat64()
+	}
+	return X, Y
+}
+
+// 与逐窗口调用 MultiRegression 的结果对比，窗口内含 NaN 行
+func TestRollingOLSMatchesLoop(t *testing.T) {
+	X, Y := rollingData(300)
+	Y[50] = math.NaN()
+	X[120][1] = math.NaN()
+	const window, minObs = 40, 10
+	res, err := RollingOLS(X, Y, window, minObs, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < len(Y); i++ {
+		var wx [][]float64
+		var wy []float64
+		for j := max(0, i-window+1); j <= i; j++ {
+			if !math.IsNaN(Y[j]) && !math.IsNaN(X[j][1]) {
+				wx = append(wx, X[j])
+				wy = append(wy, Y[j])
+			}
+		}
+		if res.NObs[i] != len(wy) {
+			t.Fatalf("t=%d nobs=%d want %d", i, res.NObs[i], len(wy))
+		}
+		if len(wy) < minObs {
+			if !math.IsNaN(res.Coeffs[i][0]) {
+				t.Fatalf("t=%d 样本不足时应为 NaN", i)
+			}
+			continue
+		}
+		m, err := MultiRegression(wx, wy, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for j := range m.Coeffs {
+			if math.Abs(m.Coeffs[j]-res.Coeffs[i][j]) > 1e-9 || math.Abs(m.SE[j]-res.SE[i][j]) > 1e-9 {
+				t.Fatalf("t=%d coef %d: %v±%v want %v±%v", i, j, res.Coeffs[i][j], res.SE[i][j], m.Coeffs[j], m.SE[j])
+			}
+		}
+		if math.Abs(m.RSquared-res.RSquared[i]) > 1e-9 || math.Abs(m.Sigma2-res.Sigma2[i]) > 1e-9 {
+			t.Fatalf("t=%d R²=%v σ²=%v want %v %v", i, res.RSquared[i], res.Sigma2[i], m.RSquared, m.Sigma2)
+		}
+	}
+	if !math.IsNaN(res.Resids[50]) || !math.IsNaN(res.Resids[120]) {
+		t.Error("NaN 行的残差应为 NaN")
+	}
+}
+
+func TestExpandingOLS(t *testing.T) {
+	X, Y := rollingData(200)
+	res, err := ExpandingOLS(X, Y, 0, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, _ := MultiRegression(X, Y, true)
+	last := len(Y) - 1
+	for j := range m.Coeffs {
+		if math.Abs(m.Coeffs[j]-res.Coeffs[last][j]) > 1e-9 {
+			t.Errorf("coef %d: %v want %v", j, res.Coeffs[last][j], m.Coeffs[j])
+		}
+	}
+	if res.NObs[2] != 3 || !math.IsNaN(res.Coeffs[2][0]) || math.IsNaN(res.Coeffs[3][0]) {
+		t.Errorf("默认 minObs 应为 k+1=4")
+	}
+}
+
+func BenchmarkRollingOLS(b *testing.B) {
+	X, Y := rollingData(5000)
+	const window = 250
+	b.Run("loop", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			for t := window; t <= len(Y); t++ {
+				_, _ = MultiRegression(X[t-window:t], Y[t-window:t], true)
+			}
+		}
+	})
+	b.Run("rolling", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_, _ = RollingOLS(X, Y, window, 0, true)
+		}
+	})
+}
@@ -0,0 +1,235 @@
+// 回归系数稳定性检验: Chow 检验、OLS-CUSUM、递归残差 CUSUM
+// matX 需自带常数列，与 MultiRegressionMat 的输入约定一致
+package ols
+
+import (
+	"fmt"
+	"math"
+	"ofeisInfra/infra/errorx"
+	"ofeisInfra/infra/errorx/errCode"
+
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/mathext"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+type ChowResult struct {
+	BreakIdx int     // 断点，第二段的起始下标
+	FStat    float64 // F 统计量
+	PValue   float64
+	Df1      int // k
+	Df2      int // n - 2k
+}
+
+type CusumResult struct {
+	Stat      float64            // 检验统计量(越界程度 sup|W_t|/边界形状)
+	PValue    float64            // 渐近 p值
+	Path      []float64          // CUSUM 路径
+	Bound     []float64          // 5% 显著性边界(上界，下界取相反数)，与 Path 等长
+	Criticals map[string]float64 // 统计量临界值（1%, 5%, 10%）
+	StartIdx  int                // Path[0] 对应的样本下标
+}
+
+// Chow 检验(已知断点)
+// F = ((SSR_p - SSR_1 - SSR_2)/k) / ((SSR_1 + SSR_2)/(n - 2k)) ~ F(k, n-2k)
+// input: breakIdx 第二段起始下标，两段样本量均须 > k
+func ChowTest(matX *mat.Dense, matY *mat.VecDense, breakIdx int) (ChowResult, error) {
+	n, k := matX.Dims()
+	if matY.Len() != n {
+		return ChowResult{}, errorx.New(errCode.INVALID_VALUE, "数据长度不匹配")
+	}
+	if breakIdx <= k || n-breakIdx <= k {
+		return ChowResult{}, errorx.New(errCode.INVALID_VALUE, fmt.Sprintf("断点 %d 两侧样本量须大于参数数 %d", breakIdx, k))
+	}
+	pooled, err := MultiRegressionMat(matX, matY)
+	if err != nil {
+		return ChowResult{}, err
+	}
+	first, err := MultiRegressionMat(rowSlice(matX, 0, breakIdx), matY.SliceVec(0, breakIdx).(*mat.VecDense))
+	if err != nil {
+		return ChowResult{}, err
+	}
+	second, err := MultiRegressionMat(rowSlice(matX, breakIdx, n), matY.SliceVec(breakIdx, n).(*mat.VecDense))
+	if err != nil {
+		return ChowResult{}, err
+	}
+	ssrP := sumSq(pooled.Resids)
+	ssrU := sumSq(first.Resids) + sumSq(second.Resids)
+	df2 := n - 2*k
+	f := ((ssrP - ssrU) / float64(k)) / (ssrU / float64(df2))
+	return ChowResult{
+		BreakIdx: breakIdx,
+		FStat:    f,
+		PValue:   fSurvival(f, float64(k), float64(df2)),
+		Df1:      k,
+		Df2:      df2,
+	}, nil
+}
+
+// OLS-CUSUM 检验(Ploberger & Krämer 1992)
+// W_t = Σ_{i<=t} e_i / (σ̂√n)，H0 下渐近为布朗桥，统计量 sup|W_t| 服从 Kolmogorov 分布
+func OLSCusumTest(matX *mat.Dense, matY *mat.VecDense) (CusumResult, error) {
+	n, k := matX.Dims()
+	if matY.Len() != n {
+		return CusumResult{}, errorx.New(errCode.INVALID_VALUE, "数据长度不匹配")
+	}
+	model, err := MultiRegressionMat(matX, matY)
+	if err != nil {
+		return CusumResult{}, err
+	}
+	sigma := math.Sqrt(sumSq(model.Resids) / float64(n-k))
+	scale := sigma * math.Sqrt(float64(n))
+
+	result := CusumResult{
+		Path:      make([]float64, n),
+		Bound:     make([]float64, n),
+		Criticals: map[string]float64{"1%": 1.628, "5%": 1.358, "10%": 1.224},
+	}
+	cum := 0.0
+	for t, e := range model.Resids {
+		cum += e
+		result.Path[t] = cum / scale
+		result.Bound[t] = result.Criticals["5%"]
+		result.Stat = math.Max(result.Stat, math.Abs(result.Path[t]))
+	}
+	result.PValue = kolmogorovSurvival(result.Stat)
+	return result, nil
+}
+
+// 递归残差 CUSUM 检验(Brown, Durbin & Evans 1975)
+// W_r = Σ_{t=k+1}^{r} w_t / σ̂_w，边界 ±a[√(n-k) + 2(r-k)/√(n-k)]
+// 统计量为 max_r |W_r| / [√(n-k) + 2(r-k)/√(n-k)]，越界概率 2[1 - Φ(3a) + e^{-4a²}Φ(a)]
+func RecursiveCusumTest(matX *mat.Dense, matY *mat.VecDense) (CusumResult, error) {
+	n, _ := matX.Dims()
+	if matY.Len() != n {
+		return CusumResult{}, errorx.New(errCode.INVALID_VALUE, "数据长度不匹配")
+	}
+	w, start, _, err := recursiveResiduals(matX, matY, 0, n)
+	if err != nil {
+		return CusumResult{}, err
+	}
+	m := len(w)
+	if m < 3 {
+		return CusumResult{}, errorx.New(errCode.INVALID_VALUE, "样本量过小, 无法计算递归残差")
+	}
+	mean := 0.0
+	for _, v := range w {
+		mean += v
+	}
+	mean /= float64(m)
+	ss := 0.0
+	for _, v := range w {
+		ss += (v - mean) * (v - mean)
+	}
+	sigma := math.Sqrt(ss / float64(m-1))
+
+	result := CusumResult{
+		Path:      make([]float64, m),
+		Bound:     make([]float64, m),
+		Criticals: map[string]float64{"1%": 1.143, "5%": 0.948, "10%": 0.850},
+		StartIdx:  start,
+	}
+	sq := math.Sqrt(float64(m))
+	cum := 0.0
+	for r, v := range w {
+		cum += v
+		result.Path[r] = cum / sigma
+		shape := sq + 2*float64(r+1)/sq
+		result.Bound[r] = result.Criticals["5%"] * shape
+		result.Stat = math.Max(result.Stat, math.Abs(result.Path[r])/shape)
+	}
+	a := result.Stat
+	p := 2 * (distuv.UnitNormal.Survival(3*a) + math.Exp(-4*a*a)*distuv.UnitNormal.CDF(a))
+	result.PValue = math.Min(1, p)
+	return result, nil
+}
+
+// 递归残差 w_t = (y_t - x_t'β_{t-1}) / √(1 + x_t'(X'X)_{t-1}^{-1}x_t)，t 从 start+k0 到 end-1
+// 以使 X'X 可逆的最少前 k0(>=k) 行初始化，之后用 Sherman-Morrison 秩一更新，每步 O(k²)
+// output: w 递归残差; first w[0] 对应的样本下标; initSSR 初始 k0 行的 OLS 残差平方和
+// 区间 [start, t] 的 OLS SSR = initSSR + Σ w²
+func recursiveResiduals(matX *mat.Dense, matY *mat.VecDense, start, end int) (w []float64, first int, initSSR float64, err error) {
+	_, k := matX.Dims()
+	var inv mat.Dense
+	var beta mat.VecDense
+	k0 := k
+	for ; start+k0 <= end; k0++ {
+		X0 := rowSlice(matX, start, start+k0)
+		var xtx mat.Dense
+		xtx.Mul(X0.T(), X0)
+		if inv.Inverse(&xtx) != nil {
+			continue
+		}
+		var xty mat.VecDense
+		xty.MulVec(X0.T(), matY.SliceVec(start, start+k0))
+		beta.MulVec(&inv, &xty)
+		for i := start; i < start+k0; i++ {
+			e := matY.AtVec(i) - mat.Dot(matX.RowView(i), &beta)
+			initSSR += e * e
+		}
+		break
+	}
+	if start+k0 > end {
+		return nil, 0, 0, errorx.New(errCode.INVALID_VALUE, "X'X 不可逆，请检查自变量是否共线")
+	}
+
+	first = start + k0
+	w = make([]float64, 0, end-first)
+	Px := mat.NewVecDense(k, nil)
+	for t := first; t < end; t++ {
+		x := matX.RowView(t)
+		Px.MulVec(&inv, x)
+		f := 1 + mat.Dot(x, Px)
+		e := matY.AtVec(t) - mat.Dot(x, &beta)
+		w = append(w, e/math.Sqrt(f))
+		// β_t = β_{t-1} + P x e / f;  P_t = P - P x x' P / f
+		beta.AddScaledVec(&beta, e/f, Px)
+		for i := 0; i < k; i++ {
+			for j := 0; j < k; j++ {
+				inv.Set(i, j, inv.At(i, j)-Px.AtVec(i)*Px.AtVec(j)/f)
+			}
+		}
+	}
+	return w, first, initSSR, nil
+}
+
+// Kolmogorov 分布尾概率 P(sup|B| > x) = 2Σ(-1)^{j-1}e^{-2j²x²}
+func kolmogorovSurvival(x float64) float64 {
+	if x <= 0 {
+		return 1
+	}
+	s := 0.0
+	for j := 1; j <= 100; j++ {
+		term := math.Exp(-2 * float64(j*j) * x * x)
+		if j%2 == 1 {
+			s += term
+		} else {
+			s -= term
+		}
+		if term < 1e-16 {
+			break
+		}
+	}
+	return math.Max(0, math.Min(1, 2*s))
+}
+
+// F 分布尾概率，直接用正则化不完全 Beta 避免 1-CDF 精度损失
+func fSurvival(f, d1, d2 float64) float64 {
+	if f <= 0 || math.IsNaN(f) {
+		return 1
+	}
+	return mathext.RegIncBeta(d2/2, d1/2, d2/(d2+d1*f))
+}
+
+func rowSlice(X *mat.Dense, from, to int) *mat.Dense {
+	_, k := X.Dims()
+	return X.Slice(from, to, 0, k).(*mat.Dense)
+}
+
+func sumSq(x []float64) float64 {
+	s := 0.0
+	for _, v := range x {
+		s += v * v
+	}
+	return s
+}
@@ -0,0 +1,210 @@
+package ols
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// y = 1 + 0.5x + e (t < 30)，y = 2 + 1.2x + e (t >= 30)，e ~ N(0, 0.5²)，n=60(保留 4 位小数)
+var chowX = []float64{
+	0.2463, 0.9555, 0.3396, 0.9443, -1.3305, -0.5529, -0.0276, 0.9379,
+	0.7995, 1.6529, -0.5652, 1.9919, 0.1177, 0.0588, -0.9298, 0.4822,
+	0.0288, -2.0097, -0.8413, -0.0708, -0.2339, -1.0855, -0.0414, 0.8784,
+	1.5711, 0.0052, 0.6192, 0.182, -1.0159, -0.9502, -1.0403, -0.9621,
+	0.7562, -2.2845, 0.9699, -0.1989, -0.3205, 0.4351, -0.5595, 0.2561,
+	1.531, -0.676, 0.1394, 1.5277, -0.1457, -0.4332, 0.5899, -1.5149,
+	-2.3645, 0.4122, 0.2233, 0.1381, -0.2475, -1.4595, 1.3636, 0.0362,
+	-0.8218, -0.5254, 1.2927, 1.3192,
+}
+
+var chowY = []float64{
+	1.6188, 1.0781, 1.2823, 2.0865, -0.031, 0.2442, 1.103, 1.1297,
+	1.9909, 1.2942, 0.8645, 2.1546, 1.1375, 0.5881, 1.4709, 0.5604,
+	0.8547, 0.8004, 0.6982, 1.5421, 1.2156, 1.2883, 1.6802, 2.3136,
+	1.5206, 1.3157, 1.6432, 1.1759, 0.8189, 0.662, 1.4755, 0.7524,
+	3.0846, -0.5835, 2.1752, 2.0217, 1.1658, 2.6568, 1.1978, 2.0841,
+	4.0833, 1.7531, 2.4486, 3.3522, 1.6738, 2.0816, 2.8156, -0.1412,
+	-0.4908, 2.9691, 2.1165, 2.4019, 1.9386, 1.2191, 3.7713, 2.4783,
+	1.4688, 1.2081, 4.4395, 3.2384,
+}
+
+func chowDesign() (*mat.Dense, *mat.VecDense) {
+	matX := mat.NewDense(len(chowX), 2, nil)
+	for i, v := range chowX {
+		matX.Set(i, 0, 1)
+		matX.Set(i, 1, v)
+	}
+	return matX, mat.NewVecDense(len(chowY), append([]float64(nil), chowY...))
+}
+
+// 对照值由独立的 Householder QR 最小二乘实现分段回归得到
+func TestChowReference(t *testing.T) {
+	matX, matY := chowDesign()
+	tests := []struct {
+		breakIdx int
+		f, p     float64
+	}{
+		{30, 55.165323741093516, 5.781505842069512e-14},
+		{20, 23.59883219445905, 3.685602081141541e-08},
+	}
+	for _, tt := range tests {
+		res, err := ChowTest(matX, matY, tt.breakIdx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.Df1 != 2 || res.Df2 != 56 || math.Abs(res.FStat-tt.f) > 1e-9 || math.Abs(res.PValue-tt.p) > 1e-6*tt.p {
+			t.Errorf("break %d: F=%v p=%v df=(%d, %d)", tt.breakIdx, res.FStat, res.PValue, res.Df1, res.Df2)
+		}
+	}
+	// 真实断点处 F 值最大
+	best, _ := ChowTest(matX, matY, 30)
+	for b := 3; b <= 57; b++ {
+		if res, err := ChowTest(matX, matY, b); err != nil || res.FStat > best.FStat {
+			t.Fatalf("break %d: F=%v exceeds planted break F=%v (err %v)", b, res.FStat, best.FStat, err)
+		}
+	}
+	if _, err := ChowTest(matX, matY, 2); err == nil {
+		t.Error("break leaving k observations accepted")
+	}
+}
+
+// 递归残差对照: 每一步用前 t 行重新做 OLS，w_t = (y_t - x_t'β_{t-1}) / √(1 + x_t'(X'X)^{-1}x_t)
+func TestRecursiveResidualsBruteForce(t *testing.T) {
+	matX, matY := chowDesign()
+	n, k := matX.Dims()
+	w, first, initSSR, err := recursiveResiduals(matX, matY, 0, n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first != k || initSSR > 1e-20 || len(w) != n-k {
+		t.Fatalf("first=%d initSSR=%v len=%d", first, initSSR, len(w))
+	}
+	for i, v := range w {
+		tt := first + i
+		X0 := rowSlice(matX, 0, tt)
+		var xtx, inv mat.Dense
+		xtx.Mul(X0.T(), X0)
+		if err := inv.Inverse(&xtx); err != nil {
+			t.Fatal(err)
+		}
+		var xty, beta, px mat.VecDense
+		xty.MulVec(X0.T(), matY.SliceVec(0, tt))
+		beta.MulVec(&inv, &xty)
+		x := matX.RowView(tt)
+		px.MulVec(&inv, x)
+		want := (matY.AtVec(tt) - mat.Dot(x, &beta)) / math.Sqrt(1+mat.Dot(x, &px))
+		if math.Abs(v-want) > 1e-9 {
+			t.Errorf("t=%d: w=%v, want %v", tt, v, want)
+		}
+	}
+}
+
+// BaiPerron 的区间 SSR 由递归残差累加得到，须与每个区间 [i, j] 单独做 MultiRegressionMat 的 SSR 一致
+func TestRecursiveResidualsSegmentSSR(t *testing.T) {
+	matX, matY := chowDesign()
+	n, k := matX.Dims()
+	for i := 0; i+k < n; i++ {
+		w, first, initSSR, err := recursiveResiduals(matX, matY, i, n)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cum := initSSR
+		for idx, v := range w {
+			cum += v * v
+			j := first + idx
+			if j-i+1 <= k {
+				continue
+			}
+			model, err := MultiRegressionMat(rowSlice(matX, i, j+1), matY.SliceVec(i, j+1).(*mat.VecDense))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := sumSq(model.Resids); math.Abs(cum-want) > 1e-9*math.Max(1, want) {
+				t.Errorf("segment [%d, %d]: recursive SSR %v, OLS SSR %v", i, j, cum, want)
+			}
+		}
+	}
+}
+
+// 首次越过 5% 边界的位置，未越界返回 -1
+func firstCrossing(res CusumResult) int {
+	for r, v := range res.Path {
+		if math.Abs(v) > res.Bound[r] {
+			return res.StartIdx + r
+		}
+	}
+	return -1
+}
+
+// chowY 在 t=30 处截距与斜率同时变化，两种 CUSUM 均应拒绝稳定性
+func TestCusumDetectsShift(t *testing.T) {
+	matX, matY := chowDesign()
+	n, _ := matX.Dims()
+
+	olsRes, err := OLSCusumTest(matX, matY)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 路径为 OLS 残差累加和 / (σ̂√n)，含常数项时终点回到 0
+	model, _ := MultiRegressionMat(matX, matY)
+	scale := math.Sqrt(sumSq(model.Resids)/float64(n-2)) * math.Sqrt(float64(n))
+	cum, peak := 0.0, 0
+	for i, e := range model.Resids {
+		cum += e
+		if math.Abs(olsRes.Path[i]-cum/scale) > 1e-12 {
+			t.Fatalf("OLS-CUSUM path[%d] = %v, want %v", i, olsRes.Path[i], cum/scale)
+		}
+		if math.Abs(olsRes.Path[i]) > math.Abs(olsRes.Path[peak]) {
+			peak = i
+		}
+	}
+	if math.Abs(olsRes.Path[n-1]) > 1e-9 || peak < 25 || peak > 35 {
+		t.Errorf("OLS-CUSUM end %v, peak at %d", olsRes.Path[n-1], peak)
+	}
+	if olsRes.Stat <= olsRes.Criticals["5%"] || olsRes.PValue >= 0.05 || firstCrossing(olsRes) < 0 {
+		t.Errorf("OLS-CUSUM: stat=%v p=%v", olsRes.Stat, olsRes.PValue)
+	}
+
+	recRes, err := RecursiveCusumTest(matX, matY)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if recRes.StartIdx != 2 || len(recRes.Path) != n-2 {
+		t.Fatalf("recursive CUSUM start=%d len=%d", recRes.StartIdx, len(recRes.Path))
+	}
+	if recRes.Stat <= recRes.Criticals["5%"] || recRes.PValue >= 0.05 {
+		t.Errorf("recursive CUSUM: stat=%v p=%v", recRes.Stat, recRes.PValue)
+	}
+	// 断点前递归残差无系统偏离，越界发生在断点之后
+	if c := firstCrossing(recRes); c < 30 {
+		t.Errorf("recursive CUSUM first crossing at %d", c)
+	}
+}
+
+func TestCusumStable(t *testing.T) {
+	const n = 200
+	rng := rand.New(rand.NewSource(39))
+	matX := mat.NewDense(n, 2, nil)
+	matY := mat.NewVecDense(n, nil)
+	for i := 0; i < n; i++ {
+		x := rng.NormFloat64()
+		matX.Set(i, 0, 1)
+		matX.Set(i, 1, x)
+		matY.SetVec(i, 1+0.5*x+0.5*rng.NormFloat64())
+	}
+	for name, test := range map[string]func(*mat.Dense, *mat.VecDense) (CusumResult, error){
+		"ols":       OLSCusumTest,
+		"recursive": RecursiveCusumTest,
+	} {
+		res, err := test(matX, matY)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.PValue < 0.10 || firstCrossing(res) >= 0 {
+			t.Errorf("%s: stat=%v p=%v crossing at %d", name, res.Stat, res.PValue, firstCrossing(res))
+		}
+	}
+}
@@ -1,13 +1,12 @@
 package adfuller
 
 import (
+	"fmt"
 	"math"
-	"math/rand"
 	"method/ml/ols"
 	"ofeisInfra/infra/errorx"
 	"ofeisInfra/infra/errorx/errCode"
 	"strategyCrypto/pkg/utils/myTools"
-	"time"
 
 	"github.com/gonum/stat"
 	"gonum.org/v1/gonum/mat"
@@ -19,15 +18,17 @@ type ADFResult struct {
 	TStat     float64            // ADF统计量 (t值)
 	PValue    float64            // 对应p值
 	UsedLag   int                // 选用的滞后阶数
+	MaxLag    int                // 最大滞后阶数(已解析 Schwert 默认值)
 	NObs      int                // 有效样本量
 	AIC       float64            // Akaike信息准则
 	BIC       float64            // 贝叶斯信息准则
 	Method    LagMode            // autolag选择方法
-	Trend     string             // 趋势类型 ("nc"、"c"、"ct")
+	Trend     string             // 回归确定项 ("n"、"c"、"ct"、"ctt")，与 Coeffs 排列一致
 	Criticals map[string]float64 // 临界值（1%, 5%, 10%）
 	Tail      string             // 左尾or右尾
 	Resid     []float64          // 残差
 	Coeffs    []float64          // 回归系数
+	Detrend   string             // DF-GLS 的 GLS 去趋势类型 ("c"、"ct")，此时 Trend 为 "n"；AdfTest 结果为空
 }
 
 // adf单位根检验, H0: 非平稳(存在单位根); H1: 序列平稳(无单位根)
@@ -62,6 +63,18 @@ func buildADFData(series []float64, lag int) (dy, ylag []float64) {
 	return dy, ylag
 }
 
+// Schwert(1989) 默认最大滞后阶数 ceil(12·(n/100)^(1/4))，并保证回归自由度
+func SchwertMaxLag(nobs int) int {
+	maxLag := int(math.Ceil(12 * math.Pow(float64(nobs)/100, 0.25)))
+	if limit := nobs/2 - 2; maxLag > limit {
+		maxLag = limit
+	}
+	if maxLag < 0 {
+		maxLag = 0
+	}
+	return maxLag
+}
+
 // 方差
 func variance(x []float64) float64 {
 	mean := myTools.ArrMean(x)
@@ -87,245 +100,187 @@ func calcTStat(y, x []float64, gamma float64) float64 {
 	return gamma / se
 }
 
-// ADF检验主函数
-// input: logPrice 对数价格序列; regr: 趋势类型 "n"、"c"、"ct"; maxLag: 最大滞后阶数 0 为1阶; autolag: 滞后阶数选择方法; tail: "LEFT_TAIL" or "RIGHT_TAIL"
-func AdfTest(logPrice []float64, regr string, maxLag int, autolag LagMode, tail string) (ADFResult, error) {
+// ADF 回归设计矩阵，各行依次为 y_{t-1}、常数(regr != "n")、趋势 i+1(regr 为 "ct"/"ctt")、
+// 二次趋势 (i+1)²(regr == "ctt")、Δy_{t-lag..t-1}，
+// 之后追加 extra 中的列(长度须等于行数)
+// 所有滞后阶数共用去掉前 maxLag 个观测后的样本，保证信息准则可比；
+// 第 i 行对应原序列下标 t = maxLag+i+1: 因变量 Δy_t = dy[maxLag+i]，y_{t-1} = ylag[maxLag+i]
+func buildADFRegression(dy, ylag []float64, regr string, lag, maxLag int, extra ...[]float64) (*mat.Dense, *mat.VecDense) {
+	dy1 := dy[maxLag:]
+	ylag1 := ylag[maxLag:]
+	nRow := len(dy1)
+	nCol := lag + 1 + len(extra)
+	if regr != "n" {
+		nCol++
+	}
+	if regr == "ct" || regr == "ctt" {
+		nCol++
+	}
+	if regr == "ctt" {
+		nCol++
+	}
+	X := make([]float64, nRow*nCol)
+	pos := 0
+	for i := 0; i < nRow; i++ {
+		X[pos] = ylag1[i]
+		pos++
+		if regr != "n" {
+			X[pos] = 1
+			pos++
+		}
+		if regr == "ct" || regr == "ctt" {
+			X[pos] = float64(i + 1)
+			pos++
+		}
+		if regr == "ctt" {
+			X[pos] = float64((i + 1) * (i + 1))
+			pos++
+		}
+		for j := lag; j > 0; j-- {
+			X[pos] = dy[maxLag-j+i]
+			pos++
+		}
+		for _, col := range extra {
+			X[pos] = col[i]
+			pos++
+		}
+	}
+	return mat.NewDense(nRow, nCol, X), mat.NewVecDense(nRow, dy1)
+}
+
+// 按 autolag 判断新回归是否优于当前最优(AIC/BIC 取小，t-stat 取 y_{t-1} 系数 t 值最小，fixed 只回归一次)
+func lagImproves(autolag LagMode, model ols.MultiLinearModel, bestAIC, bestBIC, bestT float64) bool {
+	switch autolag {
+	case LAG_MODE_AIC:
+		return model.AIC < bestAIC
+	case LAG_MODE_BIC:
+		return model.BIC < bestBIC
+	case LAG_MODE_TSTAT:
+		return model.TStats[0] < bestT || bestT == 0
+	case LAG_MODE_FIXED:
+		return true
+	}
+	return false
+}
+
+// ADF检验参数
+type AdfOptions struct {
+	Trend   Trend   // 确定项: TREND_N / TREND_C / TREND_CT / TREND_CTT
+	Tail    Tail    // TAIL_LEFT 平稳性检验; TAIL_RIGHT 爆炸性检验
+	MaxLag  int     // 最大滞后阶数; < 0 时取 Schwert 默认 ceil(12·(n/100)^(1/4))
+	AutoLag LagMode // LAG_MODE_AIC / LAG_MODE_BIC / LAG_MODE_TSTAT 在 0..MaxLag 中选择; LAG_MODE_FIXED 固定使用 MaxLag
+}
+
+// 默认参数: 含常数项、左尾、Schwert 最大滞后、AIC 选阶
+func DefaultAdfOptions() AdfOptions {
+	return AdfOptions{Trend: TREND_C, Tail: TAIL_LEFT, MaxLag: -1, AutoLag: LAG_MODE_AIC}
+}
+
+// ADF检验主函数(参数结构体版本)，非法参数直接返回错误；Trend 与 Tail 须显式指定，零值 AdfOptions{} 非法
+// 所有滞后阶数共用去掉前 MaxLag 个观测后的样本
+func AdfTestWithOptions(series []float64, opts AdfOptions) (ADFResult, error) {
+	if opts.Trend <= TREND_UNSET || opts.Trend >= TREND_ERROR {
+		return ADFResult{}, errorx.New(errCode.INVALID_VALUE, "未知的趋势类型")
+	}
+	if opts.Tail != TAIL_LEFT && opts.Tail != TAIL_RIGHT {
+		return ADFResult{}, errorx.New(errCode.INVALID_VALUE, "未知的检验尾部")
+	}
+	switch opts.AutoLag {
+	case LAG_MODE_AIC, LAG_MODE_BIC, LAG_MODE_TSTAT, LAG_MODE_FIXED:
+	default:
+		return ADFResult{}, errorx.New(errCode.INVALID_VALUE, fmt.Sprintf("AdfTest 不支持的滞后阶数选择方法 %s", opts.AutoLag))
+	}
+	for _, v := range series {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return ADFResult{}, errorx.New(errCode.INVALID_VALUE, "序列含 NaN/Inf")
+		}
+	}
+	maxLag := opts.MaxLag
+	if maxLag < 0 {
+		maxLag = SchwertMaxLag(len(series))
+	}
+	regr := opts.Trend.String()
+	nDet := opts.Trend.nDeterministic()
+	// 有效样本至少 10 个，且多于最大回归列数
+	if nobs := len(series) - 1 - maxLag; nobs < 10 || nobs <= maxLag+1+nDet {
+		return ADFResult{}, errorx.New(errCode.INVALID_VALUE, fmt.Sprintf("样本量 %d 不足以支持 maxLag=%d", len(series), maxLag))
+	}
 
 	result := ADFResult{
 		AIC:       math.Inf(1),
 		BIC:       math.Inf(1),
-		Tail:      tail,
-		Method:    autolag,
+		Tail:      opts.Tail.String(),
+		Method:    opts.AutoLag,
+		MaxLag:    maxLag,
 		Criticals: make(map[string]float64),
 		Resid:     make([]float64, 0),
 		Trend:     regr,
 	}
-
-	dy := diff(logPrice) // len = n-1
-	ylag := logPrice[:len(logPrice)-1]
-
-	switch tail {
-	case LEFT_TAIL:
+	if opts.Tail == TAIL_LEFT {
 		result.Criticals = adfLeftTailCriticalValues[regr]
-		for lag := 0; lag <= maxLag; lag++ {
-			dy1 := dy[maxLag:]
-			if len(dy1) < 10 {
-				continue
-			}
-			ylag1 := ylag[:len(ylag)-maxLag]
-
-			// 构建endog
-			nRow := len(dy1)
-			var nCol int
-			if regr == "c" || regr == "n" {
-				nCol = lag + 2
-			}
-			if regr == "ct" {
-				nCol = lag + 3
-			}
-			X := make([]float64, nRow*nCol)
-			pos := 0
-			for i := 0; i < nRow; i++ {
-				X[pos] = ylag1[i]
-				pos++
-				if regr != "n" {
-					X[pos] = 1
-					pos++
-				}
-				if regr == "ct" {
-					X[pos] = float64(i + 1)
-					pos++
-				}
-				if lag != 0 {
-					for j := lag; j > 0; j-- {
-						X[pos] = dy[maxLag-j : len(dy)-j][i]
-						pos++
-					}
-				}
-
-			}
-
-			matX := mat.NewDense(nRow, nCol, X)
-			matY := mat.NewVecDense(nRow, dy1)
-			model, err := ols.MultiRegressionMat(matX, matY)
-			if err != nil {
-				continue
-			}
-			gamma := model.Coeffs[0]
-			tStat := model.TStats[0]
-			AIC := model.AIC
-			BIC := model.BIC
-			nobs := nRow
-			pValues := model.PValues[0]
-
-			switch autolag {
-			case LAG_MODE_AIC:
-				if model.AIC < result.AIC {
-					result.Gamma = gamma
-					result.TStat = tStat
-					result.AIC = AIC
-					result.BIC = BIC
-					result.UsedLag = lag
-					result.NObs = nobs
-					result.Resid = model.Resids
-					result.PValue = pValues
-					result.Coeffs = model.Coeffs
-				}
-			case LAG_MODE_BIC:
-				if model.BIC < result.BIC {
-					result.Gamma = gamma
-					result.TStat = tStat
-					result.AIC = AIC
-					result.BIC = BIC
-					result.UsedLag = lag
-					result.NObs = nobs
-					result.Resid = model.Resids
-					result.PValue = pValues
-					result.Coeffs = model.Coeffs
-				}
-			case LAG_MODE_TSTAT:
-				if tStat < result.TStat || result.TStat == 0 {
-					result.Gamma = gamma
-					result.TStat = tStat
-					result.AIC = AIC
-					result.BIC = BIC
-					result.UsedLag = lag
-					result.NObs = nobs
-					result.Resid = model.Resids
-					result.PValue = pValues
-					result.Coeffs = model.Coeffs
-				}
-
-			}
-		}
-	case RIGHT_TAIL:
+	} else {
 		result.Criticals = adfRightTailCriticalValues[regr]
-		for lag := 0; lag <= maxLag; lag++ {
-			dy1 := dy[maxLag:]
-			if len(dy1) < 10 {
-				continue
-			}
-			ylag1 := ylag[:len(ylag)-maxLag]
-
-			// 构建endog
-			nRow := len(dy1)
-			var nCol int
-			if regr == "c" || regr == "n" {
-				nCol = lag + 2
-			}
-			if regr == "ct" {
-				nCol = lag + 3
-			}
-			X := make([]float64, nRow*nCol)
-			pos := 0
-			for i := 0; i < nRow; i++ {
-				X[pos] = ylag1[i]
-				pos++
-				if regr != "n" {
-					X[pos] = 1
-					pos++
-				}
-				if regr == "ct" {
-					X[pos] = float64(i + 1)
-					pos++
-				}
-				if lag != 0 {
-					for j := lag; j > 0; j-- {
-						X[pos] = dy[maxLag-j : len(dy)-j][i]
-						pos++
-					}
-				}
-
-			}
-
-			matX := mat.NewDense(nRow, nCol, X)
-			matY := mat.NewVecDense(nRow, dy1)
-			model, err := ols.MultiRegressionMat(matX, matY)
-			if err != nil {
-				continue
-			}
-			gamma := model.Coeffs[0]
-			tStat := model.TStats[0]
-			AIC := model.AIC
-			BIC := model.BIC
-			nobs := nRow
-			pValues := model.PValues[0]
-
-			switch autolag {
-			case LAG_MODE_AIC:
-				if model.AIC < result.AIC {
-					result.Gamma = gamma
-					result.TStat = tStat
-					result.AIC = AIC
-					result.BIC = BIC
-					result.UsedLag = lag
-					result.NObs = nobs
-					result.Resid = model.Resids
-					result.PValue = pValues
-					result.Coeffs = model.Coeffs
-				}
-			case LAG_MODE_BIC:
-				if model.BIC < result.BIC {
-					result.Gamma = gamma
-					result.TStat = tStat
-					result.AIC = AIC
-					result.BIC = BIC
-					result.UsedLag = lag
-					result.NObs = nobs
-					result.Resid = model.Resids
-					result.PValue = pValues
-					result.Coeffs = model.Coeffs
-				}
-			case LAG_MODE_TSTAT:
-				if tStat < result.TStat || result.TStat == 0 {
-					result.Gamma = gamma
-					result.TStat = tStat
-					result.AIC = AIC
-					result.BIC = BIC
-					result.UsedLag = lag
-					result.NObs = nobs
-					result.Resid = model.Resids
-					result.PValue = pValues
-					result.Coeffs = model.Coeffs
-				}
+	}
 
-			}
+	dy := diff(series) // len = n-1
+	ylag := series[:len(series)-1]
+	minLag := 0
+	if opts.AutoLag == LAG_MODE_FIXED {
+		minLag = maxLag
+	}
+	for lag := minLag; lag <= maxLag; lag++ {
+		matX, matY := buildADFRegression(dy, ylag, regr, lag, maxLag)
+		model, err := ols.MultiRegressionMat(matX, matY)
+		if err != nil {
+			continue
+		}
+		if lagImproves(opts.AutoLag, model, result.AIC, result.BIC, result.TStat) {
+			result.Gamma = model.Coeffs[0]
+			result.TStat = model.TStats[0]
+			result.AIC = model.AIC
+			result.BIC = model.BIC
+			result.UsedLag = lag
+			result.NObs = matY.Len()
+			result.Resid = model.Resids
+			result.PValue = model.PValues[0]
+			result.Coeffs = model.Coeffs
 		}
 	}
 
 	if math.IsInf(result.AIC, 1) || math.IsInf(result.BIC, 1) || result.TStat == 0 || math.IsNaN(result.TStat) {
 		return result, errorx.New(errCode.INVALID_VALUE, "ADF检验失败, 可能样本量过小或数据异常")
 	}
-
-	// 输出结果判定
-	// if tail == LEFT_TAIL {
-	// 	if result.TStat < result.Criticals["5%"] {
-	// 		fmt.Println("序列平稳（拒绝单位根假设）", result.TStat, result.Criticals["5%"])
-	// 	} else {
-	// 		fmt.Println("非平稳（存在单位根）", result.TStat, result.Criticals["5%"])
-	// 	}
-	// } else {
-	// 	if result.TStat > result.Criticals["5%"] {
-	// 		fmt.Println("检测到爆炸性（价格泡沫）", result.TStat, result.Criticals["5%"])
-	// 	} else {
-	// 		fmt.Println("无爆炸性迹象", result.TStat, result.Criticals["5%"])
-	// 	}
-	// }
-
 	return result, nil
 }
 
+// ADF检验主函数(兼容旧接口)，参数解析后转调 AdfTestWithOptions
+// input: logPrice 对数价格序列; regr: 趋势类型 "n"、"c"、"ct"、"ctt"; maxLag: 最大滞后阶数, < 0 取 Schwert 默认; autolag: 滞后阶数选择方法; tail: LEFT_TAIL or RIGHT_TAIL
+func AdfTest(logPrice []float64, regr string, maxLag int, autolag LagMode, tail string) (ADFResult, error) {
+	trend := GetMyTrend(regr)
+	if trend == TREND_ERROR {
+		return ADFResult{}, errorx.New(errCode.INVALID_VALUE, fmt.Sprintf("未知的趋势类型 %q", regr))
+	}
+	t := GetMyTail(tail)
+	if t == TAIL_ERROR {
+		return ADFResult{}, errorx.New(errCode.INVALID_VALUE, fmt.Sprintf("未知的检验尾部 %q", tail))
+	}
+	return AdfTestWithOptions(logPrice, AdfOptions{Trend: trend, Tail: t, MaxLag: maxLag, AutoLag: autolag})
+}
+
 var adfLeftTailCriticalValues = map[string]map[string]float64{
 	"n":  {"1%": -2.58, "5%": -1.95, "10%": -1.62},
 	"c":  {"1%": -3.43, "5%": -2.86, "10%": -2.57},
 	"ct": {"1%": -3.96, "5%": -3.41, "10%": -3.13},
+	// MacKinnon(2010) 二次趋势渐近临界值
+	"ctt": {"1%": -4.37, "5%": -3.83, "10%": -3.55},
 }
 
 // 右尾ADF临界值
 var adfRightTailCriticalValues = map[string]map[string]float64{
-	"n":  {"1%": 2.58, "5%": 1.95, "10%": 1.62},
-	"c":  {"1%": 3.43, "5%": 2.86, "10%": 2.57},
-	"ct": {"1%": 3.96, "5%": 3.41, "10%": 3.13},
+	"n":   {"1%": 2.58, "5%": 1.95, "10%": 1.62},
+	"c":   {"1%": 3.43, "5%": 2.86, "10%": 2.57},
+	"ct":  {"1%": 3.96, "5%": 3.41, "10%": 3.13},
+	"ctt": {"1%": 4.37, "5%": 3.83, "10%": 3.55},
 }
 
 type ARResult struct {
@@ -401,16 +356,16 @@ func DetectAR(resid []float64, pMax int) (bestP int, info []ARResult) {
 
 // 从adf检验结果获取data的估计
 func (f *ADFResult) GetEstmate() (regr string, muHat, tauHat float64) {
-	switch f.Trend {
-	case "n":
+	switch GetMyTrend(f.Trend) {
+	case TREND_N:
 		muHat = 0
 		tauHat = 0
-	case "c":
+	case TREND_C:
 		if len(f.Coeffs) >= 2 {
 			muHat = f.Coeffs[1]
 			tauHat = 0
 		}
-	case "ct":
+	case TREND_CT, TREND_CTT: // ctt 的二次项不参与
 		if len(f.Coeffs) >= 3 {
 			muHat = f.Coeffs[1]
 			tauHat = f.Coeffs[2]
@@ -419,68 +374,79 @@ func (f *ADFResult) GetEstmate() (regr string, muHat, tauHat float64) {
 	return f.Trend, muHat, tauHat
 }
 
-// 残差白噪声采样模拟
-func SimulateWhiteNoise(resid []float64, length int, method whiteNoiseSampleMethod) ([]float64, error) {
-
-	switch method {
-	case PARAMETIC_BOOTSTRAP:
-		return nil, errorx.New(errCode.INVALID_VALUE, "未知的残差采样方法")
+// Ljung-Box 检验结果表的一行
+type LjungBoxRow struct {
+	Lag      int     // 滞后阶数
+	LbStat   float64 // Ljung-Box 统计量
+	LbPValue float64 // Ljung-Box p值
+	BpStat   float64 // Box-Pierce 统计量
+	BpPValue float64 // Box-Pierce p值
+}
 
-	case NONPARAMETIC_BOOTSTRAP:
-		result := make([]float64, length)
-		for i := range result {
-			r := rand.New(rand.NewSource(time.Now().UnixNano()))
-			pos := r.Intn(len(resid))
-			result[i] = resid[pos]
+// Ljung-Box / Box-Pierce 检验, 与 statsmodels.acorr_ljungbox 对齐
+// 给定时序rt，有效样本长度n，滞后阶数 k=1~lags
+// 样本自相关系数: rk = Σ((rt - rmean)(rt-k - rmean)) / Σ((rt - rmean)^2)
+// Ljung-Box统计量: Q_LB(h) = n(n+2)Σ(rk^2/(n-k))  k=1~h
+// Box-Pierce统计量: Q_BP(h) = nΣrk^2  k=1~h
+// Q(h) 服从自由度为 h - modelDf 的卡方分布(自由度 <= 0 时 p 值为 NaN)
+// NaN 按 statsmodels acf(missing="conservative") 处理: 均值与分母只用非 NaN 值，含 NaN 的乘积对跳过
+// input: resid 残差序列; lags 最大滞后阶数; modelDf 已拟合模型的参数个数(如 ARMA(p,q) 取 p+q，白噪声取 0)
+// output: 每个滞后阶数一行的统计量表
+func LjungBoxTest(resid []float64, lags int, modelDf int) ([]LjungBoxRow, error) {
+	if lags <= 0 {
+		return nil, errorx.New(errCode.INVALID_VALUE, "lags must be > 0")
+	}
+	if modelDf < 0 {
+		return nil, errorx.New(errCode.INVALID_VALUE, "modelDf 不能为负")
+	}
+	valid := make([]float64, 0, len(resid))
+	for _, v := range resid {
+		if !math.IsNaN(v) {
+			valid = append(valid, v)
 		}
-		return result, nil
-
-	case WILD_BOOTSTRAP:
-		return nil, errorx.New(errCode.INVALID_VALUE, "未知的残差采样方法")
-
-	default:
-		return nil, errorx.New(errCode.INVALID_VALUE, "未知的残差采样方法")
 	}
-}
+	nobs := len(valid)
+	if nobs <= lags {
+		return nil, errorx.New(errCode.INVALID_VALUE, "样本量过小, 无法进行Ljung-Box检验")
+	}
+	n := float64(nobs)
 
-// Ljung-Box检验
-// 给定时序rt，样本长度n，滞后阶数lags
-// 样本自相关系数: rk = Σ((rt - rmean)(rt-k - rmean)) / Σ((rt - rmean)^2)
-// Ljung-Box统计量: Q = n(n+2)Σ(rk^2/(n-k))  k=1~lags
-// Q服从自由度为lags的卡方分布
-// input: resid 残差序列; lags 滞后阶数; alpha 显著性水平
-// output: result 是否拒绝原假设(残差白噪声); Q 统计量; pValue p值; err 错误
-func LjungBoxTest(resid []float64, lags int, alpha float64) (result bool, Q float64, pValue float64, err error) {
-	n := float64(len(resid))
-	if n <= float64(lags) {
-		return false, 0, 0, errorx.New(errCode.INVALID_VALUE, "样本量过小, 无法进行Ljung-Box检验")
-	}
-	rmean := myTools.ArrMean(resid)
-	r := make([]float64, lags+1)
+	rmean := myTools.ArrMean(valid)
 	var denom float64
-	for _, v := range resid {
+	for _, v := range valid {
 		denom += (v - rmean) * (v - rmean)
 	}
-	for k := 10; k <= lags; k++ {
+	if denom == 0 {
+		return nil, errorx.New(errCode.INVALID_VALUE, "残差方差为0, 无法进行Ljung-Box检验")
+	}
+
+	table := make([]LjungBoxRow, lags)
+	var sumLB, sumBP float64
+	for k := 1; k <= lags; k++ {
 		var num float64
 		for t := k; t < len(resid); t++ {
+			if math.IsNaN(resid[t]) || math.IsNaN(resid[t-k]) {
+				continue
+			}
 			num += (resid[t] - rmean) * (resid[t-k] - rmean)
 		}
-		r[k] = num / denom
-	}
-
-	for k := 1; k <= lags; k++ {
-		Q += r[k] * r[k] / (n - float64(k))
-	}
-	Q = n * (n + 2) * Q
-
-	// 计算 p-value
-	chi2 := distuv.ChiSquared{K: float64(lags)}
-	pValue = 1 - chi2.CDF(Q)
-	if pValue < alpha {
-		result = true // 拒绝原假设, 残差存在自相关
-	} else {
-		result = false // 不拒绝原假设, 残差白噪声
+		rk := num / denom
+		sumLB += rk * rk / (n - float64(k))
+		sumBP += rk * rk
+
+		row := LjungBoxRow{
+			Lag:      k,
+			LbStat:   n * (n + 2) * sumLB,
+			BpStat:   n * sumBP,
+			LbPValue: math.NaN(),
+			BpPValue: math.NaN(),
+		}
+		if df := k - modelDf; df > 0 {
+			chi2 := distuv.ChiSquared{K: float64(df)}
+			row.LbPValue = chi2.Survival(row.LbStat)
+			row.BpPValue = chi2.Survival(row.BpStat)
+		}
+		table[k-1] = row
 	}
-	return result, Q, pValue, nil
+	return table, nil
 }
@@ -0,0 +1,147 @@
+package adfuller
+
+import (
+	"math"
+	"method/ml/ols"
+	"testing"
+)
+
+// 对照值按 statsmodels.acorr_ljungbox 的定义独立计算(含一个 NaN, modelDf=2)
+func TestLjungBoxTest(t *testing.T) {
+	resid := []float64{0.5, -1.2, 0.3, 2.1, -0.7, 0.0, 1.4, -2.2, 0.9, 0.4, -0.3, 1.1, -1.5, 0.8, 0.2, math.NaN(), -0.6, 1.3, -0.9, 0.7}
+	table, err := LjungBoxTest(resid, 4, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantLB := []float64{7.239740705198966, 7.273678100907351, 9.69453253100401, 12.623207300212776}
+	wantBP := []float64{6.205492033027686, 6.232965162886855, 8.077425681055738, 10.169336230490572}
+	for i, row := range table {
+		if row.Lag != i+1 {
+			t.Fatalf("lag=%d want %d", row.Lag, i+1)
+		}
+		if math.Abs(row.LbStat-wantLB[i]) > 1e-9 {
+			t.Fatalf("Q=%v want %v", row.LbStat, wantLB[i])
+		}
+		if math.Abs(row.BpStat-wantBP[i]) > 1e-9 {
+			t.Fatalf("BP=%v want %v", row.BpStat, wantBP[i])
+		}
+	}
+	// 自由度 <= 0 时 p 值为 NaN
+	if !math.IsNaN(table[0].LbPValue) || !math.IsNaN(table[1].BpPValue) {
+		t.Fatal("p-value should be NaN when lag <= modelDf")
+	}
+	if math.Abs(table[3].LbPValue-0.001815120086475953) > 1e-9 || math.Abs(table[3].BpPValue-0.006190941428462757) > 1e-9 {
+		t.Fatal("p-value mismatch")
+	}
+}
+
+// AR(1) φ=0.5、n=120 的模拟序列(保留 4 位小数)
+var ar1Series = []float64{
+	0.0, 0.4163, -0.3164, 0.2274, -1.8445, -2.1771, -1.1069, -0.0253,
+	0.4564, 0.9772, 0.7637, -1.352, -1.5699, -0.2952, 0.9978, 1.1533,
+	0.2309, 0.7451, -1.6674, 0.1884, 0.9497, 1.7034, 0.2533, 0.9805,
+	-1.3648, -2.7435, -1.3893, 1.0911, 1.7828, -0.9563, -0.3056, -1.5863,
+	-1.3232, -0.351, -1.5422, 0.3749, -0.4174, 1.0253, 0.7659, -0.117,
+	-2.1565, -0.7954, -0.1994, -0.3861, -1.265, -0.2315, 2.8136, 0.2246,
+	0.3106, 0.8754, -0.3421, 0.3664, 1.7561, 2.1106, 0.6326, 0.6212,
+	-0.2786, 0.3942, 0.1463, 0.824, 1.0998, 1.1941, 2.6176, 0.2113,
+	1.2134, 0.748, 0.8495, -0.7576, -1.1188, -0.494, 0.3205, -0.2476,
+	-0.1393, 0.8501, 0.2165, -0.2862, -1.0919, -2.375, 0.1503, -0.8168,
+	-1.0288, -0.2346, -0.8349, -1.8093, 0.4007, 0.1109, 0.2448, 0.369,
+	0.0251, 1.0634, -0.1829, -0.4527, -2.6601, -1.0263, -0.5244, -0.1304,
+	-1.0609, -0.5221, -1.2354, 0.1168, -1.5558, 0.0735, 0.7131, 0.6187,
+	0.6944, 0.5249, 0.8417, 2.4721, 0.911, 1.3231, -1.2408, 0.8939,
+	0.924, 1.1648, 1.7753, -0.1823, -0.9769, -0.6575, 0.5695, -0.9463,
+}
+
+// 固定滞后 4(同 statsmodels adfuller(x, maxlag=4, autolag=None))与公共样本 AIC 选阶(maxLag=6)的对照值，
+// 由独立的 Householder QR 最小二乘实现按相同设计矩阵计算
+func TestAdfReference(t *testing.T) {
+	tests := []struct {
+		trend   Trend
+		maxLag  int
+		autoLag LagMode
+		usedLag int
+		gamma   float64
+		tStat   float64
+		nObs    int
+	}{
+		{TREND_N, 4, LAG_MODE_FIXED, 4, -0.6109150725293758, -4.076447332696955, 115},
+		{TREND_C, 4, LAG_MODE_FIXED, 4, -0.6109139393380295, -4.057888893011752, 115},
+		{TREND_CT, 4, LAG_MODE_FIXED, 4, -0.6182137422988793, -4.021833210976662, 115},
+		{TREND_CTT, 4, LAG_MODE_FIXED, 4, -0.6196066971349917, -4.010486260541857, 115},
+		{TREND_C, 6, LAG_MODE_AIC, 0, math.NaN(), -7.198432722263212, 113},
+		{TREND_CT, 6, LAG_MODE_AIC, 0, math.NaN(), -7.157990719913807, 113},
+	}
+	for _, tt := range tests {
+		res, err := AdfTestWithOptions(ar1Series, AdfOptions{Trend: tt.trend, Tail: TAIL_LEFT, MaxLag: tt.maxLag, AutoLag: tt.autoLag})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.UsedLag != tt.usedLag || res.NObs != tt.nObs || math.Abs(res.TStat-tt.tStat) > 1e-9 ||
+			(!math.IsNaN(tt.gamma) && math.Abs(res.Gamma-tt.gamma) > 1e-9) {
+			t.Errorf("%s %s: lag=%d nobs=%d gamma=%v t=%v", tt.trend, tt.autoLag, res.UsedLag, res.NObs, res.Gamma, res.TStat)
+		}
+	}
+}
+
+// 滞后 0、maxLag > 0 的回归须等价于在截掉前 maxLag 个观测的序列上做普通 DF 回归
+func TestBuildADFRegressionAlignment(t *testing.T) {
+	const maxLag = 12
+	dy := diff(ar1Series)
+	ylag := ar1Series[:len(ar1Series)-1]
+	for _, regr := range []string{"n", "c", "ct"} {
+		matX, matY := buildADFRegression(dy, ylag, regr, 0, maxLag)
+		model, err := ols.MultiRegressionMat(matX, matY)
+		if err != nil {
+			t.Fatal(err)
+		}
+		plain, err := AdfTestWithOptions(ar1Series[maxLag:], AdfOptions{Trend: GetMyTrend(regr), Tail: TAIL_LEFT, MaxLag: 0, AutoLag: LAG_MODE_FIXED})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(model.Coeffs) != len(plain.Coeffs) {
+			t.Fatalf("%s: %d vs %d coeffs", regr, len(model.Coeffs), len(plain.Coeffs))
+		}
+		for j := range model.Coeffs {
+			if math.Abs(model.Coeffs[j]-plain.Coeffs[j]) > 1e-12 {
+				t.Errorf("%s coef %d: %v vs %v", regr, j, model.Coeffs[j], plain.Coeffs[j])
+			}
+		}
+	}
+	// 真实 γ = φ - 1 = -0.5，任一滞后阶数下都不应退化到 0 附近
+	for lag := 0; lag <= maxLag; lag++ {
+		matX, matY := buildADFRegression(dy, ylag, "c", lag, maxLag)
+		model, _ := ols.MultiRegressionMat(matX, matY)
+		if g := model.Coeffs[0]; g > -0.25 || g < -0.9 {
+			t.Errorf("lag %d: gamma %v", lag, g)
+		}
+	}
+}
+
+func TestAdfOptionsValidation(t *testing.T) {
+	bad := []AdfOptions{
+		{},
+		{Tail: TAIL_LEFT, AutoLag: LAG_MODE_AIC},
+		{Trend: TREND_C, AutoLag: LAG_MODE_AIC},
+		{Trend: TREND_ERROR, Tail: TAIL_LEFT},
+		{Trend: TREND_C, Tail: TAIL_LEFT, AutoLag: LAG_MODE_MAIC},
+		{Trend: TREND_C, Tail: TAIL_LEFT, MaxLag: 60},
+	}
+	for _, opts := range bad {
+		if _, err := AdfTestWithOptions(ar1Series, opts); err == nil {
+			t.Errorf("%+v accepted", opts)
+		}
+	}
+	// Schwert 默认: ceil(12·(120/100)^(1/4)) = 13，固定滞后即用满 13 阶
+	opts := DefaultAdfOptions()
+	opts.AutoLag = LAG_MODE_FIXED
+	res, err := AdfTestWithOptions(ar1Series, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if SchwertMaxLag(len(ar1Series)) != 13 || res.UsedLag != 13 || res.NObs != len(ar1Series)-1-13 {
+		t.Errorf("schwert lag %d used %d nobs %d", SchwertMaxLag(len(ar1Series)), res.UsedLag, res.NObs)
+	}
+}
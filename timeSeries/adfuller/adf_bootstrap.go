@@ -0,0 +1,168 @@
+package adfuller
+
+import (
+	"math"
+	"math/rand"
+	"ofeisInfra/infra/errorx"
+	"ofeisInfra/infra/errorx/errCode"
+	"strategyCrypto/pkg/utils/myTools"
+
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// 残差白噪声采样模拟
+// 所有方法的随机数均来自注入的 rng，相同种子可复现同一条采样路径
+// PARAMETIC_BOOTSTRAP:    N(mean, var) 高斯采样
+// PARAMETIC_T_BOOTSTRAP:  极大似然拟合 Student-t 自由度后采样，方差与残差一致
+// NONPARAMETIC_BOOTSTRAP: 残差有放回抽样
+// WILD_BOOTSTRAP:         e*_t = e_t·w_t, w_t 取 Rademacher 权重 ±1
+// WILD_MAMMEN_BOOTSTRAP:  e*_t = e_t·w_t, w_t 取 Mammen 两点权重 (E[w]=0, E[w²]=E[w³]=1)
+// BLOCK_BOOTSTRAP:        移动块抽样，块长 ceil(n^(1/3))，保留残差短程相关
+// wild 系列按位置对应残差，length 超过残差长度时循环取用
+func SimulateWhiteNoise(resid []float64, length int, method whiteNoiseSampleMethod, rng *rand.Rand) ([]float64, error) {
+	n := len(resid)
+	if n == 0 {
+		return nil, errorx.New(errCode.EMPTY_VALUE, "残差序列为空")
+	}
+	if length <= 0 {
+		return nil, errorx.New(errCode.INVALID_VALUE, "采样长度必须 > 0")
+	}
+	if rng == nil {
+		return nil, errorx.New(errCode.INVALID_VALUE, "随机数生成器 rng 不能为空")
+	}
+
+	switch method {
+	case PARAMETIC_BOOTSTRAP:
+		mean := myTools.ArrMean(resid)
+		std := math.Sqrt(variance(resid))
+		result := make([]float64, length)
+		for i := range result {
+			result[i] = mean + std*rng.NormFloat64()
+		}
+		return result, nil
+
+	case PARAMETIC_T_BOOTSTRAP:
+		mean, scale, nu, err := FitStudentT(resid)
+		if err != nil {
+			return nil, err
+		}
+		dist := distuv.StudentsT{Mu: mean, Sigma: scale, Nu: nu, Src: rng}
+		result := make([]float64, length)
+		for i := range result {
+			result[i] = dist.Rand()
+		}
+		return result, nil
+
+	case NONPARAMETIC_BOOTSTRAP:
+		result := make([]float64, length)
+		for i := range result {
+			result[i] = resid[rng.Intn(n)]
+		}
+		return result, nil
+
+	case WILD_BOOTSTRAP:
+		result := make([]float64, length)
+		for i := range result {
+			w := 1.0
+			if rng.Intn(2) == 0 {
+				w = -1
+			}
+			result[i] = resid[i%n] * w
+		}
+		return result, nil
+
+	case WILD_MAMMEN_BOOTSTRAP:
+		sqrt5 := math.Sqrt(5)
+		wLow := -(sqrt5 - 1) / 2
+		wHigh := (sqrt5 + 1) / 2
+		pLow := (sqrt5 + 1) / (2 * sqrt5)
+		result := make([]float64, length)
+		for i := range result {
+			w := wHigh
+			if rng.Float64() < pLow {
+				w = wLow
+			}
+			result[i] = resid[i%n] * w
+		}
+		return result, nil
+
+	case BLOCK_BOOTSTRAP:
+		return SimulateBlockBootstrap(resid, length, int(math.Ceil(math.Cbrt(float64(n)))), rng)
+
+	default:
+		return nil, errorx.New(errCode.INVALID_VALUE, "未知的残差采样方法")
+	}
+}
+
+// 固定种子的残差采样，便于复现
+func SimulateWhiteNoiseSeed(resid []float64, length int, method whiteNoiseSampleMethod, seed int64) ([]float64, error) {
+	return SimulateWhiteNoise(resid, length, method, rand.New(rand.NewSource(seed)))
+}
+
+// 移动块 bootstrap: 随机选取长度为 blockLen 的连续残差块拼接，直到长度 length
+func SimulateBlockBootstrap(resid []float64, length int, blockLen int, rng *rand.Rand) ([]float64, error) {
+	n := len(resid)
+	if n == 0 {
+		return nil, errorx.New(errCode.EMPTY_VALUE, "残差序列为空")
+	}
+	if length <= 0 {
+		return nil, errorx.New(errCode.INVALID_VALUE, "采样长度必须 > 0")
+	}
+	if blockLen <= 0 || blockLen > n {
+		return nil, errorx.New(errCode.INVALID_VALUE, "块长度必须在 1~len(resid) 之间")
+	}
+	if rng == nil {
+		return nil, errorx.New(errCode.INVALID_VALUE, "随机数生成器 rng 不能为空")
+	}
+	result := make([]float64, 0, length+blockLen)
+	for len(result) < length {
+		start := rng.Intn(n - blockLen + 1)
+		result = append(result, resid[start:start+blockLen]...)
+	}
+	return result[:length], nil
+}
+
+// 极大似然拟合 Student-t 分布
+// 位置取样本均值，尺度按 scale² = var·(ν-2)/ν 与样本方差匹配，对自由度 ν 做黄金分割搜索
+// output: mean 位置; scale 尺度; nu 自由度
+func FitStudentT(resid []float64) (mean, scale, nu float64, err error) {
+	if len(resid) < 3 {
+		return 0, 0, 0, errorx.New(errCode.INVALID_VALUE, "样本量过小, 无法拟合Student-t分布")
+	}
+	mean = myTools.ArrMean(resid)
+	v := variance(resid)
+	if v <= 0 || math.IsNaN(v) {
+		return 0, 0, 0, errorx.New(errCode.INVALID_VALUE, "残差方差为0, 无法拟合Student-t分布")
+	}
+
+	negLogLik := func(nu float64) float64 {
+		dist := distuv.StudentsT{Mu: mean, Sigma: math.Sqrt(v * (nu - 2) / nu), Nu: nu}
+		ll := 0.0
+		for _, x := range resid {
+			ll += dist.LogProb(x)
+		}
+		return -ll
+	}
+
+	// 在 ν ∈ [2.05, 200] 上黄金分割搜索
+	const nuMin, nuMax = 2.05, 200.0
+	invPhi := (math.Sqrt(5) - 1) / 2
+	a, b := nuMin, nuMax
+	c := b - invPhi*(b-a)
+	d := a + invPhi*(b-a)
+	fc, fd := negLogLik(c), negLogLik(d)
+	for it := 0; it < 100 && b-a > 1e-4; it++ {
+		if fc < fd {
+			b, d, fd = d, c, fc
+			c = b - invPhi*(b-a)
+			fc = negLogLik(c)
+		} else {
+			a, c, fc = c, d, fd
+			d = a + invPhi*(b-a)
+			fd = negLogLik(d)
+		}
+	}
+	nu = (a + b) / 2
+	scale = math.Sqrt(v * (nu - 2) / nu)
+	return mean, scale, nu, nil
+}
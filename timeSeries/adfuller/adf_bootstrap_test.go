@@ -0,0 +1,107 @@
+package adfuller
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+var allSampleMethods = []whiteNoiseSampleMethod{
+	PARAMETIC_BOOTSTRAP, NONPARAMETIC_BOOTSTRAP, WILD_BOOTSTRAP,
+	PARAMETIC_T_BOOTSTRAP, WILD_MAMMEN_BOOTSTRAP, BLOCK_BOOTSTRAP,
+}
+
+// 相同种子得到同一条路径，不同种子得到不同路径
+func TestSimulateWhiteNoiseSeedDeterministic(t *testing.T) {
+	resid := diff(ar1Series)
+	for _, method := range allSampleMethods {
+		a, err := SimulateWhiteNoiseSeed(resid, 300, method, 7)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, _ := SimulateWhiteNoiseSeed(resid, 300, method, 7)
+		c, _ := SimulateWhiteNoiseSeed(resid, 300, method, 8)
+		if len(a) != 300 {
+			t.Fatalf("method %d: length %d", method, len(a))
+		}
+		same := true
+		for i := range a {
+			if a[i] != b[i] {
+				t.Fatalf("method %d: seed 7 not reproducible at %d", method, i)
+			}
+			same = same && a[i] == c[i]
+		}
+		if same {
+			t.Errorf("method %d: seeds 7 and 8 give identical paths", method)
+		}
+	}
+}
+
+// 残差全为 1 时 wild 采样直接输出权重: Rademacher 与 Mammen 均满足 E[w]=0, E[w²]=1，Mammen 另有 E[w³]=1
+func TestWildWeightMoments(t *testing.T) {
+	const n = 200000
+	ones := make([]float64, 10)
+	for i := range ones {
+		ones[i] = 1
+	}
+	for _, tt := range []struct {
+		method whiteNoiseSampleMethod
+		m3     float64
+	}{{WILD_BOOTSTRAP, 0}, {WILD_MAMMEN_BOOTSTRAP, 1}} {
+		w, err := SimulateWhiteNoiseSeed(ones, n, tt.method, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var m1, m2, m3 float64
+		for _, v := range w {
+			m1 += v
+			m2 += v * v
+			m3 += v * v * v
+		}
+		m1, m2, m3 = m1/n, m2/n, m3/n
+		// 样本矩的标准误约 1/√n ≈ 0.0022(三阶矩约 0.005)
+		if math.Abs(m1) > 0.01 || math.Abs(m2-1) > 0.01 || math.Abs(m3-tt.m3) > 0.03 {
+			t.Errorf("method %d: E[w]=%v E[w²]=%v E[w³]=%v", tt.method, m1, m2, m3)
+		}
+	}
+}
+
+func TestFitStudentT(t *testing.T) {
+	rng := rand.New(rand.NewSource(3))
+	dist := distuv.StudentsT{Mu: 0.3, Sigma: 2, Nu: 5, Src: rng}
+	x := make([]float64, 5000)
+	for i := range x {
+		x[i] = dist.Rand()
+	}
+	mean, scale, nu, err := FitStudentT(x)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if nu < 4 || nu > 6.5 {
+		t.Errorf("nu = %v, want about 5", nu)
+	}
+	// 位置取样本均值，尺度与样本方差匹配
+	sum := 0.0
+	for _, v := range x {
+		sum += v
+	}
+	if math.Abs(mean-sum/float64(len(x))) > 1e-12 {
+		t.Errorf("mean = %v", mean)
+	}
+	if v := variance(x); math.Abs(scale*scale*nu/(nu-2)-v) > 1e-9 {
+		t.Errorf("scale %v does not match variance %v", scale, v)
+	}
+	// 高斯样本的自由度应靠近搜索上界
+	g := make([]float64, 5000)
+	for i := range g {
+		g[i] = rng.NormFloat64()
+	}
+	if _, _, nuG, _ := FitStudentT(g); nuG < 30 {
+		t.Errorf("gaussian sample nu = %v", nuG)
+	}
+	if _, _, _, err := FitStudentT([]float64{1, 2}); err == nil {
+		t.Error("expected error for short sample")
+	}
+}
@@ -5,13 +5,104 @@ const (
 	RIGHT_TAIL = "right_tail"
 )
 
+// ADF 回归确定项
+type Trend int
+
+const (
+	TREND_UNSET Trend = iota // 零值，未显式指定，视为非法
+	TREND_N                  // "n" 无常数项
+	TREND_C                  // "c" 常数项
+	TREND_CT                 // "ct" 常数项+线性趋势
+	TREND_CTT                // "ctt" 常数项+线性趋势+二次趋势
+	TREND_ERROR              // "ERROR"
+)
+
+func (s Trend) String() string {
+	switch s {
+	case TREND_N:
+		return "n"
+	case TREND_C:
+		return "c"
+	case TREND_CT:
+		return "ct"
+	case TREND_CTT:
+		return "ctt"
+	default:
+		return "ERROR"
+	}
+}
+
+func GetMyTrend(s string) Trend {
+	switch s {
+	case "n":
+		return TREND_N
+	case "c":
+		return TREND_C
+	case "ct":
+		return TREND_CT
+	case "ctt":
+		return TREND_CTT
+	default:
+		return TREND_ERROR
+	}
+}
+
+// 确定项列数
+func (s Trend) nDeterministic() int {
+	switch s {
+	case TREND_C:
+		return 1
+	case TREND_CT:
+		return 2
+	case TREND_CTT:
+		return 3
+	default:
+		return 0
+	}
+}
+
+// 检验尾部
+type Tail int
+
+const (
+	TAIL_UNSET Tail = iota // 零值，未显式指定，视为非法
+	TAIL_LEFT              // LEFT_TAIL 平稳性检验
+	TAIL_RIGHT             // RIGHT_TAIL 爆炸性检验
+	TAIL_ERROR             // "ERROR"
+)
+
+func (s Tail) String() string {
+	switch s {
+	case TAIL_LEFT:
+		return LEFT_TAIL
+	case TAIL_RIGHT:
+		return RIGHT_TAIL
+	default:
+		return "ERROR"
+	}
+}
+
+func GetMyTail(s string) Tail {
+	switch s {
+	case LEFT_TAIL:
+		return TAIL_LEFT
+	case RIGHT_TAIL:
+		return TAIL_RIGHT
+	default:
+		return TAIL_ERROR
+	}
+}
+
 // 残差为白噪采样方法
 type whiteNoiseSampleMethod int
 
 const (
-	PARAMETIC_BOOTSTRAP    whiteNoiseSampleMethod = iota // "parametic"
+	PARAMETIC_BOOTSTRAP    whiteNoiseSampleMethod = iota // "parametic" 高斯
 	NONPARAMETIC_BOOTSTRAP                               // "nonparametic"
-	WILD_BOOTSTRAP                                       // "wild"
+	WILD_BOOTSTRAP                                       // "wild" Rademacher权重
+	PARAMETIC_T_BOOTSTRAP                                // "parametic-t" 拟合Student-t
+	WILD_MAMMEN_BOOTSTRAP                                // "wild-mammen" Mammen权重
+	BLOCK_BOOTSTRAP                                      // "block" 移动块
 )
 
 type LagMode int
@@ -21,6 +112,9 @@ const (
 	LAG_MODE_BIC                  // "BIC"
 	LAG_MODE_TSTAT                // "t-stat"
 	LAG_MODE_ERROR                // "ERROR"
+	// 新增取值追加在 LAG_MODE_ERROR 之后，不改变已有取值的编号
+	LAG_MODE_MAIC  // "MAIC" Ng-Perron 修正AIC，仅 DF-GLS
+	LAG_MODE_FIXED // "fixed" 固定使用 maxLag
 )
 
 func (s LagMode) String() string {
@@ -31,6 +125,10 @@ func (s LagMode) String() string {
 		return "BIC"
 	case LAG_MODE_TSTAT:
 		return "t-stat"
+	case LAG_MODE_MAIC:
+		return "MAIC"
+	case LAG_MODE_FIXED:
+		return "fixed"
 	default:
 		return "ERROR"
 	}
@@ -43,7 +141,47 @@ func GetMyLagMode(s string) LagMode {
 		return LAG_MODE_BIC
 	case "t-stat":
 		return LAG_MODE_TSTAT
+	case "MAIC":
+		return LAG_MODE_MAIC
+	case "fixed":
+		return LAG_MODE_FIXED
 	default:
 		return LAG_MODE_ERROR
 	}
 }
+
+// Zivot-Andrews 结构突变类型
+type ZABreak int
+
+const (
+	ZA_BREAK_INTERCEPT ZABreak = iota // "c" 截距突变 (模型A)
+	ZA_BREAK_TREND                    // "t" 趋势突变 (模型B)
+	ZA_BREAK_BOTH                     // "ct" 截距与趋势同时突变 (模型C)
+	ZA_BREAK_ERROR                    // "ERROR"
+)
+
+func (s ZABreak) String() string {
+	switch s {
+	case ZA_BREAK_INTERCEPT:
+		return "c"
+	case ZA_BREAK_TREND:
+		return "t"
+	case ZA_BREAK_BOTH:
+		return "ct"
+	default:
+		return "ERROR"
+	}
+}
+
+func GetMyZABreak(s string) ZABreak {
+	switch s {
+	case "c":
+		return ZA_BREAK_INTERCEPT
+	case "t":
+		return ZA_BREAK_TREND
+	case "ct":
+		return ZA_BREAK_BOTH
+	default:
+		return ZA_BREAK_ERROR
+	}
+}
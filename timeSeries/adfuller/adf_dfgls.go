@@ -0,0 +1,186 @@
+// DF-GLS(Elliott-Rothenberg-Stock 1996) 单位根检验，近单位根时功效高于 ADF
+//  1. GLS 去趋势: α = 1 + c̄/T (c̄ = -7 常数, -13.5 趋势)，对 y 与 z = [1, (t)] 做准差分
+//     y_α = (y_1, y_2 - αy_1, ...)，z_α 同理，β = OLS(y_α, z_α)，y^d = y - zβ
+//  2. 对 y^d 做无常数项 ADF 回归: Δy^d_t = γ y^d_{t-1} + Σ φ_j Δy^d_{t-j} + e_t
+//  3. 滞后阶数默认用 Ng-Perron(2001) MAIC:
+//     MAIC(k) = ln σ²_k + 2(τ(k) + k)/(T - kmax),  τ(k) = γ²Σ(y^d_{t-1})² / σ²_k
+package adfuller
+
+import (
+	"math"
+	"method/ml/ols"
+	"ofeisInfra/infra/errorx"
+	"ofeisInfra/infra/errorx/errCode"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// ERS(1996) Table 1 含趋势临界值，按样本量 T 在 1/T 上线性插值
+var ersTrendCriticalT = []float64{50, 100, 200, math.Inf(1)}
+var ersTrendCriticalValues = map[string][]float64{
+	"1%":  {-3.77, -3.58, -3.46, -3.48},
+	"5%":  {-3.19, -3.03, -2.93, -2.89},
+	"10%": {-2.89, -2.74, -2.64, -2.57},
+}
+
+// DF-GLS 检验, H0: 存在单位根
+// input: series 序列; regr "c" 或 "ct"; maxLag 最大滞后阶数; autolag LAG_MODE_MAIC(推荐)、LAG_MODE_AIC、LAG_MODE_BIC 或 LAG_MODE_TSTAT
+// output: ADFResult，Gamma/TStat 为去趋势序列回归的 γ 与 t 值，Coeffs 为 [γ, φ_lag..φ_1]，PValue 为临界值线性插值(超出 1%~10% 临界值范围时为 NaN)；
+// Trend 记录 ADF 阶段回归的确定项("n")，GLS 去趋势类型记录在 Detrend，临界值按 Detrend 选取
+func DfGlsTest(series []float64, regr string, maxLag int, autolag LagMode) (ADFResult, error) {
+	if regr != "c" && regr != "ct" {
+		return ADFResult{}, errorx.New(errCode.INVALID_VALUE, "DF-GLS 仅支持 regr = \"c\" 或 \"ct\"")
+	}
+	if autolag != LAG_MODE_MAIC && autolag != LAG_MODE_AIC && autolag != LAG_MODE_BIC && autolag != LAG_MODE_TSTAT {
+		return ADFResult{}, errorx.New(errCode.INVALID_VALUE, "未知的滞后阶数选择方法")
+	}
+	if maxLag < 0 {
+		return ADFResult{}, errorx.New(errCode.INVALID_VALUE, "maxLag 不能为负")
+	}
+	for _, v := range series {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return ADFResult{}, errorx.New(errCode.INVALID_VALUE, "序列含 NaN/Inf")
+		}
+	}
+	T := len(series)
+	if T-1-maxLag < 10 {
+		return ADFResult{}, errorx.New(errCode.INVALID_VALUE, "样本量过小, 无法进行DF-GLS检验")
+	}
+
+	yd, err := glsDetrend(series, regr)
+	if err != nil {
+		return ADFResult{}, err
+	}
+	dy := diff(yd)
+	ylag := yd[:T-1]
+
+	result := ADFResult{
+		AIC:       math.Inf(1),
+		BIC:       math.Inf(1),
+		Method:    autolag,
+		MaxLag:    maxLag,
+		Trend:     "n",
+		Detrend:   regr,
+		Tail:      LEFT_TAIL,
+		Criticals: dfGlsCriticals(regr, T),
+	}
+	// 公共样本上 Σ(y^d_{t-1})²，供 MAIC 使用
+	sumY2 := 0.0
+	for _, v := range ylag[maxLag:] {
+		sumY2 += v * v
+	}
+	nEff := float64(T - 1 - maxLag)
+	bestMAIC := math.Inf(1)
+
+	for lag := 0; lag <= maxLag; lag++ {
+		matX, matY := buildADFRegression(dy, ylag, "n", lag, maxLag)
+		model, err := ols.MultiRegressionMat(matX, matY)
+		if err != nil || math.IsNaN(model.TStats[0]) {
+			continue
+		}
+		better := false
+		if autolag == LAG_MODE_MAIC {
+			ssr := 0.0
+			for _, e := range model.Resids {
+				ssr += e * e
+			}
+			s2 := ssr / nEff
+			tau := model.Coeffs[0] * model.Coeffs[0] * sumY2 / s2
+			maic := math.Log(s2) + 2*(tau+float64(lag))/nEff
+			if maic < bestMAIC {
+				bestMAIC = maic
+				better = true
+			}
+		} else {
+			better = lagImproves(autolag, model, result.AIC, result.BIC, result.TStat)
+		}
+		if better {
+			result.Gamma = model.Coeffs[0]
+			result.TStat = model.TStats[0]
+			result.AIC = model.AIC
+			result.BIC = model.BIC
+			result.UsedLag = lag
+			result.NObs = matY.Len()
+			result.Resid = model.Resids
+			result.Coeffs = model.Coeffs
+		}
+	}
+	if math.IsInf(result.AIC, 1) || result.TStat == 0 || math.IsNaN(result.TStat) {
+		return result, errorx.New(errCode.INVALID_VALUE, "DF-GLS检验失败, 可能样本量过小或数据异常")
+	}
+	result.PValue = critPValue(result.TStat, result.Criticals)
+	return result, nil
+}
+
+// GLS 准差分去趋势
+func glsDetrend(y []float64, regr string) ([]float64, error) {
+	T := len(y)
+	cBar := -7.0
+	k := 1
+	if regr == "ct" {
+		cBar = -13.5
+		k = 2
+	}
+	alpha := 1 + cBar/float64(T)
+
+	z := func(t, j int) float64 {
+		if j == 0 {
+			return 1
+		}
+		return float64(t + 1)
+	}
+	Z := mat.NewDense(T, k, nil)
+	ya := mat.NewVecDense(T, nil)
+	ya.SetVec(0, y[0])
+	for j := 0; j < k; j++ {
+		Z.Set(0, j, z(0, j))
+	}
+	for t := 1; t < T; t++ {
+		ya.SetVec(t, y[t]-alpha*y[t-1])
+		for j := 0; j < k; j++ {
+			Z.Set(t, j, z(t, j)-alpha*z(t-1, j))
+		}
+	}
+	model, err := ols.MultiRegressionMat(Z, ya)
+	if err != nil {
+		return nil, err
+	}
+	yd := make([]float64, T)
+	for t := range y {
+		fit := 0.0
+		for j := 0; j < k; j++ {
+			fit += model.Coeffs[j] * z(t, j)
+		}
+		yd[t] = y[t] - fit
+	}
+	return yd, nil
+}
+
+// 常数情形与无常数 DF 分布一致；趋势情形按 ERS 表插值
+func dfGlsCriticals(regr string, T int) map[string]float64 {
+	if regr == "c" {
+		out := make(map[string]float64, 3)
+		for k, v := range adfLeftTailCriticalValues["n"] {
+			out[k] = v
+		}
+		return out
+	}
+	inv := 1 / float64(T)
+	out := make(map[string]float64, 3)
+	for level, cv := range ersTrendCriticalValues {
+		switch {
+		case T <= int(ersTrendCriticalT[0]):
+			out[level] = cv[0]
+		default:
+			for i := 0; i+1 < len(ersTrendCriticalT); i++ {
+				lo, hi := 1/ersTrendCriticalT[i], 1/ersTrendCriticalT[i+1]
+				if inv <= lo && inv >= hi {
+					w := (lo - inv) / (lo - hi)
+					out[level] = cv[i] + w*(cv[i+1]-cv[i])
+					break
+				}
+			}
+		}
+	}
+	return out
+}
@@ -0,0 +1,94 @@
+package adfuller
+
+import (
+	"math"
+	"testing"
+)
+
+// 标准正态增量随机游走，n=150(保留 4 位小数)
+var randomWalk = []float64{
+	0.0, -1.589, -2.0909, -2.1248, -4.1259, -4.7066, -2.1767, -3.6918,
+	-4.3672, -4.2964, -6.5966, -6.7408, -5.796, -6.0663, -7.3653, -7.3631,
+	-8.315, -8.1703, -8.2121, -8.3836, -9.3371, -7.8564, -7.6198, -6.9265,
+	-6.6936, -6.9589, -6.4381, -6.2372, -5.9017, -5.6653, -6.0081, -5.7953,
+	-5.4562, -4.3978, -5.0749, -3.896, -6.3032, -7.3052, -7.2753, -7.8761,
+	-7.3722, -9.528, -10.9883, -12.6691, -12.7049, -11.262, -12.4715, -13.074,
+	-14.1815, -13.3863, -12.8248, -12.1725, -13.0338, -13.3606, -14.064, -14.6739,
+	-15.0247, -14.615, -16.4011, -14.9311, -14.7638, -12.7413, -11.3751, -11.1382,
+	-10.6693, -9.591, -10.4539, -9.3365, -9.2602, -8.9741, -9.5027, -9.4889,
+	-10.3202, -11.2579, -10.9, -9.962, -9.6335, -11.0087, -11.2024, -11.7934,
+	-11.9442, -12.5962, -13.106, -12.7381, -13.0051, -12.9348, -13.3514, -14.2095,
+	-14.5686, -16.0448, -15.6627, -15.6372, -15.7189, -14.6355, -15.6028, -14.3716,
+	-13.4395, -13.0856, -13.9834, -13.5542, -13.9659, -14.6835, -14.4778, -14.098,
+	-14.8618, -14.8456, -11.1532, -12.1045, -11.5189, -11.7075, -12.3072, -11.7472,
+	-13.1709, -14.9596, -14.8853, -14.5601, -14.8779, -13.7001, -13.9982, -13.7012,
+	-15.1734, -13.5181, -13.9123, -13.8883, -13.3292, -13.9523, -13.4953, -13.3336,
+	-12.0912, -13.0978, -13.1128, -13.0803, -13.6447, -14.6527, -14.2627, -15.8081,
+	-17.0988, -17.1436, -17.1721, -17.952, -18.4801, -19.6205, -18.9918, -18.6654,
+	-16.9138, -17.5665, -16.8709, -17.1825, -17.274, -18.6593,
+}
+
+// 对照值由独立实现计算: GLS 准差分去趋势(c̄ = -7 / -13.5)后在公共样本(去掉前 maxLag 个观测)上逐滞后阶数回归，
+// MAIC 的 τ(k) 使用同一样本上的 Σ(y^d_{t-1})²
+func TestDfGlsReference(t *testing.T) {
+	tests := []struct {
+		name    string
+		series  []float64
+		regr    string
+		autoLag LagMode
+		usedLag int
+		tStat   float64
+		gamma   float64
+		nObs    int
+	}{
+		{"ar1", ar1Series, "c", LAG_MODE_MAIC, 6, -2.8569362434953276, -0.47870879314734066, 113},
+		{"ar1", ar1Series, "c", LAG_MODE_AIC, 0, -7.207267079361405, -0.6324946665117611, 113},
+		{"ar1", ar1Series, "ct", LAG_MODE_MAIC, 6, -2.8373554535129255, -0.4693487079228514, 113},
+		{"ar1", ar1Series, "ct", LAG_MODE_AIC, 0, -7.184593354013442, -0.629064412026336, 113},
+		{"rw", randomWalk, "c", LAG_MODE_MAIC, 0, 0.5314670294328399, 0.004479126377146305, 143},
+		{"rw", randomWalk, "ct", LAG_MODE_MAIC, 0, -1.7831667811433072, -0.044009123302382415, 143},
+	}
+	for _, tt := range tests {
+		res, err := DfGlsTest(tt.series, tt.regr, 6, tt.autoLag)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.UsedLag != tt.usedLag || res.NObs != tt.nObs || math.Abs(res.TStat-tt.tStat) > 1e-9 || math.Abs(res.Gamma-tt.gamma) > 1e-9 {
+			t.Errorf("%s %s %s: lag=%d nobs=%d t=%v gamma=%v", tt.name, tt.regr, tt.autoLag, res.UsedLag, res.NObs, res.TStat, res.Gamma)
+		}
+	}
+}
+
+// 追加枚举值不得改变已有取值的编号
+func TestLagModeValues(t *testing.T) {
+	if LAG_MODE_AIC != 0 || LAG_MODE_BIC != 1 || LAG_MODE_TSTAT != 2 || LAG_MODE_ERROR != 3 {
+		t.Fatal("existing LagMode values renumbered")
+	}
+	for _, m := range []LagMode{LAG_MODE_AIC, LAG_MODE_BIC, LAG_MODE_TSTAT, LAG_MODE_MAIC, LAG_MODE_FIXED} {
+		if GetMyLagMode(m.String()) != m {
+			t.Errorf("%s does not round-trip", m)
+		}
+	}
+}
+
+// DF-GLS 的 ADF 阶段回归不含确定项: Trend 为 "n"，GetEstmate 无漂移，滞后差分系数直接取 Coeffs[1:]
+func TestDfGlsResultTrend(t *testing.T) {
+	for _, regr := range []string{"c", "ct"} {
+		res, err := DfGlsTest(ar1Series, regr, 6, LAG_MODE_MAIC)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.Trend != "n" || res.Detrend != regr || len(res.Coeffs) != 1+res.UsedLag {
+			t.Fatalf("%s: trend=%q detrend=%q coeffs=%d", regr, res.Trend, res.Detrend, len(res.Coeffs))
+		}
+		if tr, mu, tau := res.GetEstmate(); tr != "n" || mu != 0 || tau != 0 {
+			t.Errorf("%s: GetEstmate = %q %v %v", regr, tr, mu, tau)
+		}
+		phi := res.lagDiffCoeffs()
+		for j := range phi {
+			if phi[j] != res.Coeffs[len(res.Coeffs)-1-j] {
+				t.Errorf("%s: phi_%d = %v, want %v", regr, j+1, phi[j], res.Coeffs[len(res.Coeffs)-1-j])
+			}
+		}
+	}
+}
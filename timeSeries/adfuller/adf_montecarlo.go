@@ -0,0 +1,215 @@
+// 基于已拟合ADF模型的 Monte Carlo 临界值
+// 在单位根原假设 (γ=0) 下用估计的漂移、趋势与滞后差分系数生成路径:
+//
+//	Δy_t = μ + τ·t + Σ φ_j·Δy_{t-j} + e*_t,   y_t = y_{t-1} + Δy_t
+//
+// e*_t 由 SimulateWhiteNoise 对去均值残差重采样得到，每条路径重跑 AdfTest 得到统计量的经验分布。
+// 相比渐近临界值，更适合小样本、厚尾的残差
+package adfuller
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"ofeisInfra/infra/errorx"
+	"ofeisInfra/infra/errorx/errCode"
+	"runtime"
+	"sort"
+	"strategyCrypto/pkg/utils/myTools"
+	"sync"
+)
+
+// 模拟路径的预热长度，消除初始值对滞后差分结构的影响
+const mcBurnIn = 50
+
+type MonteCarloResult struct {
+	Criticals map[string]float64 // 经验临界值（1%, 5%, 10%），右尾时对应上分位
+	PValue    float64            // bootstrap p值
+	Stats     []float64          // 各路径的ADF统计量(升序)
+	NPaths    int                // 有效路径数
+	NFailed   int                // AdfTest 失败的路径数
+}
+
+// Monte Carlo 临界值与 bootstrap p值，仅适用于 AdfTest 的结果(DF-GLS 等其他检验的统计量分布不同)
+// input: maxLag 重跑 AdfTest 的最大滞后阶数，< 0 时取原检验的 MaxLag; nPaths 模拟路径数;
+// method 残差采样方法; rng 随机数生成器，每条路径的子种子由其顺序生成，结果与并发调度无关
+func (f *ADFResult) MonteCarloCriticals(maxLag int, nPaths int, method whiteNoiseSampleMethod, rng *rand.Rand) (MonteCarloResult, error) {
+	return f.monteCarloCriticals(maxLag, nPaths, method, rng, runtime.NumCPU())
+}
+
+func (f *ADFResult) monteCarloCriticals(maxLag int, nPaths int, method whiteNoiseSampleMethod, rng *rand.Rand, numWorkers int) (MonteCarloResult, error) {
+	if nPaths <= 0 {
+		return MonteCarloResult{}, errorx.New(errCode.INVALID_VALUE, "模拟路径数必须 > 0")
+	}
+	if rng == nil {
+		return MonteCarloResult{}, errorx.New(errCode.INVALID_VALUE, "随机数生成器 rng 不能为空")
+	}
+	if f.Detrend != "" {
+		return MonteCarloResult{}, errorx.New(errCode.INVALID_VALUE, "MonteCarloCriticals 仅支持 AdfTest 的结果, 不支持 DF-GLS")
+	}
+	switch f.Method {
+	case LAG_MODE_AIC, LAG_MODE_BIC, LAG_MODE_TSTAT, LAG_MODE_FIXED:
+	default:
+		return MonteCarloResult{}, errorx.New(errCode.INVALID_VALUE, fmt.Sprintf("MonteCarloCriticals 不支持的滞后阶数选择方法 %s", f.Method))
+	}
+	if GetMyTrend(f.Trend) == TREND_ERROR {
+		return MonteCarloResult{}, errorx.New(errCode.INVALID_VALUE, fmt.Sprintf("未知的趋势类型 %q", f.Trend))
+	}
+	if GetMyTail(f.Tail) == TAIL_ERROR {
+		return MonteCarloResult{}, errorx.New(errCode.INVALID_VALUE, fmt.Sprintf("未知的检验尾部 %q", f.Tail))
+	}
+	if len(f.Resid) == 0 || len(f.Coeffs) == 0 {
+		return MonteCarloResult{}, errorx.New(errCode.EMPTY_VALUE, "ADF结果缺少残差或回归系数")
+	}
+	if maxLag < 0 {
+		maxLag = f.MaxLag
+	}
+	if maxLag < f.UsedLag {
+		return MonteCarloResult{}, errorx.New(errCode.INVALID_VALUE, fmt.Sprintf("maxLag=%d 小于原检验选用的滞后阶数 %d", maxLag, f.UsedLag))
+	}
+	if f.Method == LAG_MODE_FIXED && maxLag != f.UsedLag {
+		return MonteCarloResult{}, errorx.New(errCode.INVALID_VALUE, fmt.Sprintf("固定滞后检验的 maxLag=%d 须等于原检验的滞后阶数 %d", maxLag, f.UsedLag))
+	}
+
+	regr, muHat, tauHat := f.GetEstmate()
+	phi := f.lagDiffCoeffs()
+
+	// 残差去均值，使原假设下的漂移只来自 μ
+	rmean := myTools.ArrMean(f.Resid)
+	resid := make([]float64, len(f.Resid))
+	for i, v := range f.Resid {
+		resid[i] = v - rmean
+	}
+
+	// 与原检验等长: AdfTest 有效样本 NObs = n - 1 - MaxLag
+	length := f.NObs + f.MaxLag + 1
+	seeds := make([]int64, nPaths)
+	for i := range seeds {
+		seeds[i] = rng.Int63()
+	}
+
+	stats := make([]float64, nPaths)
+	failed := make([]bool, nPaths)
+	wg := sync.WaitGroup{}
+	tasks := make(chan int, nPaths)
+
+	worker := func() {
+		defer wg.Done()
+		for b := range tasks {
+			r := rand.New(rand.NewSource(seeds[b]))
+			path, err := simulateUnitRootPath(resid, length, muHat, tauHat, phi, method, r)
+			if err != nil {
+				failed[b] = true
+				continue
+			}
+			res, err := AdfTest(path, regr, maxLag, f.Method, f.Tail)
+			if err != nil {
+				failed[b] = true
+				continue
+			}
+			stats[b] = res.TStat
+		}
+	}
+
+	wg.Add(numWorkers)
+	for i := 0; i < numWorkers; i++ {
+		go worker()
+	}
+	for b := 0; b < nPaths; b++ {
+		tasks <- b
+	}
+	close(tasks)
+	wg.Wait()
+
+	valid := make([]float64, 0, nPaths)
+	for b, s := range stats {
+		if !failed[b] && !math.IsNaN(s) {
+			valid = append(valid, s)
+		}
+	}
+	if len(valid) == 0 {
+		return MonteCarloResult{}, errorx.New(errCode.INVALID_VALUE, "所有模拟路径的ADF检验均失败")
+	}
+	sort.Float64s(valid)
+
+	result := MonteCarloResult{
+		Criticals: make(map[string]float64, 3),
+		Stats:     valid,
+		NPaths:    len(valid),
+		NFailed:   nPaths - len(valid),
+	}
+	levels := map[string]float64{"1%": 0.01, "5%": 0.05, "10%": 0.10}
+	for key, alpha := range levels {
+		if f.Tail == RIGHT_TAIL {
+			result.Criticals[key] = empiricalQuantile(valid, 1-alpha)
+		} else {
+			result.Criticals[key] = empiricalQuantile(valid, alpha)
+		}
+	}
+	extreme := 0
+	for _, s := range valid {
+		if (f.Tail == RIGHT_TAIL && s >= f.TStat) || (f.Tail != RIGHT_TAIL && s <= f.TStat) {
+			extreme++
+		}
+	}
+	result.PValue = float64(extreme+1) / float64(len(valid)+1)
+	return result, nil
+}
+
+// 提取滞后差分系数 φ_1..φ_p（AdfTest 中滞后项按 lag, lag-1, ..., 1 排列在确定项之后）
+func (f *ADFResult) lagDiffCoeffs() []float64 {
+	nDet := GetMyTrend(f.Trend).nDeterministic()
+	p := f.UsedLag
+	phi := make([]float64, p)
+	for m := 0; m < p; m++ {
+		idx := 1 + nDet + m
+		if idx < len(f.Coeffs) {
+			phi[p-1-m] = f.Coeffs[idx]
+		}
+	}
+	return phi
+}
+
+// 生成单位根原假设下的价格路径
+func simulateUnitRootPath(resid []float64, length int, mu, tau float64, phi []float64, method whiteNoiseSampleMethod, rng *rand.Rand) ([]float64, error) {
+	total := length + mcBurnIn
+	eps, err := SimulateWhiteNoise(resid, total, method, rng)
+	if err != nil {
+		return nil, err
+	}
+	p := len(phi)
+	dy := make([]float64, total)
+	path := make([]float64, length)
+	level := 0.0
+	for t := 0; t < total; t++ {
+		v := mu + eps[t]
+		if t >= mcBurnIn {
+			v += tau * float64(t-mcBurnIn+1)
+		}
+		for j := 1; j <= p && t-j >= 0; j++ {
+			v += phi[j-1] * dy[t-j]
+		}
+		dy[t] = v
+		level += v
+		if t >= mcBurnIn {
+			path[t-mcBurnIn] = level
+		}
+	}
+	return path, nil
+}
+
+// 线性插值经验分位数(与 numpy 默认 linear 一致)，x 需已升序
+func empiricalQuantile(x []float64, q float64) float64 {
+	n := len(x)
+	if n == 1 {
+		return x[0]
+	}
+	pos := q * float64(n-1)
+	lo := int(math.Floor(pos))
+	hi := lo + 1
+	if hi >= n {
+		return x[n-1]
+	}
+	frac := pos - float64(lo)
+	return x[lo] + frac*(x[hi]-x[lo])
+}
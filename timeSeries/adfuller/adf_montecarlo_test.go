@@ -0,0 +1,92 @@
+package adfuller
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// 与 numpy.quantile(method="linear") 一致
+func TestEmpiricalQuantile(t *testing.T) {
+	x := []float64{1, 2, 3, 4, 10}
+	tests := []struct{ q, want float64 }{
+		{0, 1}, {0.1, 1.4}, {0.5, 3}, {0.95, 8.8}, {1, 10},
+	}
+	for _, tt := range tests {
+		if got := empiricalQuantile(x, tt.q); math.Abs(got-tt.want) > 1e-12 {
+			t.Errorf("q=%v: %v, want %v", tt.q, got, tt.want)
+		}
+	}
+	if got := empiricalQuantile([]float64{-2}, 0.05); got != -2 {
+		t.Errorf("single element: %v", got)
+	}
+}
+
+// 同一 rng 种子下统计量与并发数无关；临界值为统计量的经验分位数，MaxLag 默认取原检验的值
+func TestMonteCarloCriticals(t *testing.T) {
+	res, err := AdfTestWithOptions(ar1Series, AdfOptions{Trend: TREND_C, Tail: TAIL_LEFT, MaxLag: 2, AutoLag: LAG_MODE_AIC})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.MaxLag != 2 {
+		t.Fatalf("MaxLag = %d", res.MaxLag)
+	}
+	serial, err := res.monteCarloCriticals(-1, 200, NONPARAMETIC_BOOTSTRAP, rand.New(rand.NewSource(11)), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parallel, err := res.monteCarloCriticals(2, 200, NONPARAMETIC_BOOTSTRAP, rand.New(rand.NewSource(11)), 8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if serial.NPaths != parallel.NPaths || serial.NPaths+serial.NFailed != 200 {
+		t.Fatalf("paths %d/%d, failed %d", serial.NPaths, parallel.NPaths, serial.NFailed)
+	}
+	for i := range serial.Stats {
+		if serial.Stats[i] != parallel.Stats[i] {
+			t.Fatalf("stat %d differs across worker counts: %v vs %v", i, serial.Stats[i], parallel.Stats[i])
+		}
+	}
+	for key, alpha := range map[string]float64{"1%": 0.01, "5%": 0.05, "10%": 0.10} {
+		if serial.Criticals[key] != empiricalQuantile(serial.Stats, alpha) || serial.Criticals[key] != parallel.Criticals[key] {
+			t.Errorf("critical %s = %v", key, serial.Criticals[key])
+		}
+	}
+	// 含常数项、n≈120 的 5% 临界值约 -2.89
+	if c := serial.Criticals["5%"]; c < -3.6 || c > -2.3 {
+		t.Errorf("5%% critical %v far from Dickey-Fuller value", c)
+	}
+	extreme := 0
+	for _, s := range serial.Stats {
+		if s <= res.TStat {
+			extreme++
+		}
+	}
+	if want := float64(extreme+1) / float64(serial.NPaths+1); serial.PValue != want {
+		t.Errorf("p-value %v, want %v", serial.PValue, want)
+	}
+}
+
+func TestMonteCarloCriticalsRejects(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	gls, err := DfGlsTest(ar1Series, "c", 4, LAG_MODE_AIC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := gls.MonteCarloCriticals(-1, 10, NONPARAMETIC_BOOTSTRAP, rng); err == nil {
+		t.Error("DF-GLS result accepted")
+	}
+	res, _ := AdfTestWithOptions(ar1Series, AdfOptions{Trend: TREND_C, Tail: TAIL_LEFT, MaxLag: 2, AutoLag: LAG_MODE_AIC})
+	maic := res
+	maic.Method = LAG_MODE_MAIC
+	if _, err := maic.MonteCarloCriticals(-1, 10, NONPARAMETIC_BOOTSTRAP, rng); err == nil {
+		t.Error("MAIC lag selection accepted")
+	}
+	fixed, _ := AdfTestWithOptions(ar1Series, AdfOptions{Trend: TREND_C, Tail: TAIL_LEFT, MaxLag: 2, AutoLag: LAG_MODE_FIXED})
+	if _, err := fixed.MonteCarloCriticals(3, 10, NONPARAMETIC_BOOTSTRAP, rng); err == nil {
+		t.Error("fixed-lag result rerun with a different lag")
+	}
+	if _, err := fixed.MonteCarloCriticals(-1, 10, NONPARAMETIC_BOOTSTRAP, rng); err != nil {
+		t.Error(err)
+	}
+}
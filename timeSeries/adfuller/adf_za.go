@@ -0,0 +1,283 @@
+// Zivot-Andrews(1992) 内生结构突变单位根检验
+// H0: 含单位根(无突变); H1: 带一次结构突变的趋势平稳
+// 对每个候选突变点 TB 估计
+//
+//	Δy_t = γ y_{t-1} + c + β t + θ DU_t + δ DT_t + Σ φ_j Δy_{t-j} + e_t
+//	DU_t = 1(t >= TB),  DT_t = (t - TB + 1)·1(t >= TB)
+//
+// 模型A 只含 DU，模型B 只含 DT，模型C 两者都含；统计量取各突变点 γ 的 t 值最小者
+// 突变点已知时 PerronTest 只在给定 TB 处回归，使用 Perron(1989) 临界值
+package adfuller
+
+import (
+	"math"
+	"method/ml/ols"
+	"ofeisInfra/infra/errorx"
+	"ofeisInfra/infra/errorx/errCode"
+	"runtime"
+	"sync"
+)
+
+type ZAResult struct {
+	TStat     float64            // ZA统计量 (各突变点 t 值的最小值)
+	PValue    float64            // 临界值表线性插值 p值，统计量超出 1%~10% 临界值范围时为 NaN
+	BreakIdx  int                // 突变点，原序列下标(DU 从该点起为 1)
+	Gamma     float64            // 突变点处的单位根系数
+	UsedLag   int                // 突变点处选用的滞后阶数
+	NObs      int                // 有效样本量
+	Break     ZABreak            // 突变类型
+	Method    LagMode            // autolag选择方法
+	Criticals map[string]float64 // 渐近临界值（1%, 5%, 10%），ZA 为 trim=0.15，Perron 按突变比例 λ 插值
+	Stats     []float64          // 各候选突变点的 t 值，下标同原序列，非候选点为 NaN (PerronTest 为 nil)
+	Resid     []float64          // 突变点处的残差
+	Coeffs    []float64          // 突变点处的回归系数 [γ, c, β, lags..., (θ), (δ)]
+}
+
+// Zivot & Andrews (1992) Table 2-4 渐近临界值
+var zaCriticalValues = map[ZABreak]map[string]float64{
+	ZA_BREAK_INTERCEPT: {"1%": -5.34, "5%": -4.80, "10%": -4.58},
+	ZA_BREAK_TREND:     {"1%": -4.93, "5%": -4.42, "10%": -4.11},
+	ZA_BREAK_BOTH:      {"1%": -5.57, "5%": -5.08, "10%": -4.82},
+}
+
+// Zivot-Andrews 检验
+// input: series 序列; breakType 突变类型; maxLag 最大滞后阶数; autolag 每个突变点的滞后阶数选择方法;
+// trim 两端剔除比例(常用 0.15)，候选突变点为 [trim·n, (1-trim)·n]
+// 回归设计矩阵复用 AdfTest 的 "ct" 设计并追加突变虚拟变量，候选突变点并行搜索
+func ZivotAndrewsTest(series []float64, breakType ZABreak, maxLag int, autolag LagMode, trim float64) (ZAResult, error) {
+	crit, ok := zaCriticalValues[breakType]
+	if !ok {
+		return ZAResult{}, errorx.New(errCode.INVALID_VALUE, "未知的突变类型")
+	}
+	if autolag != LAG_MODE_AIC && autolag != LAG_MODE_BIC && autolag != LAG_MODE_TSTAT {
+		return ZAResult{}, errorx.New(errCode.INVALID_VALUE, "未知的滞后阶数选择方法")
+	}
+	if !(trim > 0 && trim < 0.5) {
+		return ZAResult{}, errorx.New(errCode.INVALID_VALUE, "trim 必须在 (0, 0.5) 之间")
+	}
+	if maxLag < 0 {
+		return ZAResult{}, errorx.New(errCode.INVALID_VALUE, "maxLag 不能为负")
+	}
+	for _, v := range series {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return ZAResult{}, errorx.New(errCode.INVALID_VALUE, "序列含 NaN/Inf")
+		}
+	}
+	n := len(series)
+	if n-1-maxLag < 20 {
+		return ZAResult{}, errorx.New(errCode.INVALID_VALUE, "样本量过小, 无法进行Zivot-Andrews检验")
+	}
+
+	dy := diff(series)
+	ylag := series[:n-1]
+	// 有效样本内的候选突变点
+	start := max(int(math.Ceil(trim*float64(n))), maxLag+2)
+	end := min(int(math.Floor((1-trim)*float64(n))), n-2)
+	if start > end {
+		return ZAResult{}, errorx.New(errCode.INVALID_VALUE, "trim 过大或样本量过小, 无候选突变点")
+	}
+
+	// 并行搜索
+	fits := make([]ADFResult, end-start+1)
+	oks := make([]bool, end-start+1)
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < runtime.NumCPU(); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for tb := range jobs {
+				fits[tb-start], oks[tb-start] = fitBreak(dy, ylag, breakType, tb, maxLag, autolag)
+			}
+		}()
+	}
+	for tb := start; tb <= end; tb++ {
+		jobs <- tb
+	}
+	close(jobs)
+	wg.Wait()
+
+	result := ZAResult{
+		TStat:     math.Inf(1),
+		BreakIdx:  -1,
+		Break:     breakType,
+		Method:    autolag,
+		Criticals: crit,
+		Stats:     make([]float64, n),
+	}
+	for i := range result.Stats {
+		result.Stats[i] = math.NaN()
+	}
+	for k, fit := range fits {
+		if !oks[k] {
+			continue
+		}
+		result.Stats[start+k] = fit.TStat
+		if fit.TStat < result.TStat {
+			result.TStat = fit.TStat
+			result.BreakIdx = start + k
+			result.Gamma = fit.Gamma
+			result.UsedLag = fit.UsedLag
+			result.NObs = fit.NObs
+			result.Resid = fit.Resid
+			result.Coeffs = fit.Coeffs
+		}
+	}
+	if result.BreakIdx < 0 {
+		return result, errorx.New(errCode.INVALID_VALUE, "Zivot-Andrews检验失败, 所有候选突变点回归均失败")
+	}
+	result.PValue = critPValue(result.TStat, crit)
+	return result, nil
+}
+
+// Perron (1989) Table IV.B/V.B/VI.B 渐近临界值，按突变比例 λ = 0.1, ..., 0.9 排列
+var perronLambdas = []float64{0.1, 0.2, 0.3, 0.4, 0.5, 0.6, 0.7, 0.8, 0.9}
+var perronCriticalValues = map[ZABreak]map[string][]float64{
+	ZA_BREAK_INTERCEPT: {
+		"1%":  {-4.30, -4.39, -4.39, -4.34, -4.32, -4.45, -4.42, -4.33, -4.27},
+		"5%":  {-3.68, -3.77, -3.76, -3.72, -3.76, -3.76, -3.80, -3.75, -3.69},
+		"10%": {-3.40, -3.47, -3.46, -3.44, -3.46, -3.47, -3.51, -3.46, -3.38},
+	},
+	ZA_BREAK_TREND: {
+		"1%":  {-4.27, -4.41, -4.51, -4.55, -4.56, -4.57, -4.51, -4.38, -4.26},
+		"5%":  {-3.65, -3.80, -3.87, -3.94, -3.96, -3.95, -3.85, -3.82, -3.68},
+		"10%": {-3.36, -3.49, -3.58, -3.66, -3.68, -3.66, -3.57, -3.50, -3.35},
+	},
+	ZA_BREAK_BOTH: {
+		"1%":  {-4.38, -4.65, -4.78, -4.81, -4.90, -4.88, -4.75, -4.70, -4.41},
+		"5%":  {-3.75, -3.99, -4.17, -4.22, -4.24, -4.24, -4.18, -4.04, -3.80},
+		"10%": {-3.45, -3.66, -3.87, -3.95, -3.96, -3.95, -3.86, -3.69, -3.46},
+	},
+}
+
+// Perron 临界值在 λ 上线性插值，λ 截断在 [0.1, 0.9]
+func perronCriticals(breakType ZABreak, lambda float64) map[string]float64 {
+	lambda = math.Max(perronLambdas[0], math.Min(lambda, perronLambdas[len(perronLambdas)-1]))
+	i := min(int((lambda-perronLambdas[0])/0.1), len(perronLambdas)-2)
+	w := (lambda - perronLambdas[i]) / (perronLambdas[i+1] - perronLambdas[i])
+	crit := make(map[string]float64, 3)
+	for key, row := range perronCriticalValues[breakType] {
+		crit[key] = row[i] + w*(row[i+1]-row[i])
+	}
+	return crit
+}
+
+// Perron(1989) 已知突变点单位根检验
+// input: series 序列; breakType 突变类型; tb 突变点(原序列下标，DU 从该点起为 1); maxLag 最大滞后阶数;
+// autolag 滞后阶数选择方法(LAG_MODE_FIXED 固定使用 maxLag)
+// 回归同 ZivotAndrewsTest 在 tb 处的回归(不含一次性脉冲虚拟变量 D(TB)，其不影响渐近分布)，
+// 临界值取 Perron(1989) 表按突变比例 λ = tb/n 插值
+func PerronTest(series []float64, breakType ZABreak, tb, maxLag int, autolag LagMode) (ZAResult, error) {
+	if _, ok := perronCriticalValues[breakType]; !ok {
+		return ZAResult{}, errorx.New(errCode.INVALID_VALUE, "未知的突变类型")
+	}
+	if autolag != LAG_MODE_AIC && autolag != LAG_MODE_BIC && autolag != LAG_MODE_TSTAT && autolag != LAG_MODE_FIXED {
+		return ZAResult{}, errorx.New(errCode.INVALID_VALUE, "未知的滞后阶数选择方法")
+	}
+	if maxLag < 0 {
+		return ZAResult{}, errorx.New(errCode.INVALID_VALUE, "maxLag 不能为负")
+	}
+	for _, v := range series {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return ZAResult{}, errorx.New(errCode.INVALID_VALUE, "序列含 NaN/Inf")
+		}
+	}
+	n := len(series)
+	if n-1-maxLag < 20 {
+		return ZAResult{}, errorx.New(errCode.INVALID_VALUE, "样本量过小, 无法进行Perron检验")
+	}
+	if tb < maxLag+2 || tb > n-2 {
+		return ZAResult{}, errorx.New(errCode.INVALID_VALUE, "突变点不在有效样本内")
+	}
+
+	fit, ok := fitBreak(diff(series), series[:n-1], breakType, tb, maxLag, autolag)
+	if !ok {
+		return ZAResult{}, errorx.New(errCode.INVALID_VALUE, "Perron检验失败, 突变点回归失败")
+	}
+	crit := perronCriticals(breakType, float64(tb)/float64(n))
+	return ZAResult{
+		TStat:     fit.TStat,
+		PValue:    critPValue(fit.TStat, crit),
+		BreakIdx:  tb,
+		Gamma:     fit.Gamma,
+		UsedLag:   fit.UsedLag,
+		NObs:      fit.NObs,
+		Break:     breakType,
+		Method:    autolag,
+		Criticals: crit,
+		Resid:     fit.Resid,
+		Coeffs:    fit.Coeffs,
+	}, nil
+}
+
+// 给定突变点: 逐滞后阶数回归，按 autolag 选择；LAG_MODE_FIXED 只用 maxLag
+// 回归设计矩阵复用 AdfTest 的 "ct" 设计并追加突变虚拟变量
+func fitBreak(dy, ylag []float64, breakType ZABreak, tb, maxLag int, autolag LagMode) (ADFResult, bool) {
+	nRow := len(dy) - maxLag
+	extra := breakDummies(breakType, tb, maxLag, nRow)
+	best := ADFResult{AIC: math.Inf(1), BIC: math.Inf(1)}
+	found := false
+	minLag := 0
+	if autolag == LAG_MODE_FIXED {
+		minLag = maxLag
+	}
+	for lag := minLag; lag <= maxLag; lag++ {
+		matX, matY := buildADFRegression(dy, ylag, "ct", lag, maxLag, extra...)
+		model, err := ols.MultiRegressionMat(matX, matY)
+		if err != nil || math.IsNaN(model.TStats[0]) {
+			continue
+		}
+		if lagImproves(autolag, model, best.AIC, best.BIC, best.TStat) {
+			best = ADFResult{
+				Gamma:   model.Coeffs[0],
+				TStat:   model.TStats[0],
+				AIC:     model.AIC,
+				BIC:     model.BIC,
+				UsedLag: lag,
+				MaxLag:  maxLag,
+				NObs:    nRow,
+				Resid:   model.Resids,
+				Coeffs:  model.Coeffs,
+			}
+			found = true
+		}
+	}
+	return best, found
+}
+
+// 突变虚拟变量列，第 i 行对应原序列下标 t = maxLag+i+1
+func breakDummies(breakType ZABreak, tb, maxLag, nRow int) [][]float64 {
+	du := make([]float64, nRow)
+	dt := make([]float64, nRow)
+	for i := 0; i < nRow; i++ {
+		if t := maxLag + i + 1; t >= tb {
+			du[i] = 1
+			dt[i] = float64(t - tb + 1)
+		}
+	}
+	switch breakType {
+	case ZA_BREAK_INTERCEPT:
+		return [][]float64{du}
+	case ZA_BREAK_TREND:
+		return [][]float64{dt}
+	default:
+		return [][]float64{du, dt}
+	}
+}
+
+// 在 1%/5%/10% 临界值之间线性插值 p值
+// 统计量超出临界值表范围(p < 0.01 或 p > 0.10)时无法插值，返回 NaN，需直接比较 TStat 与 Criticals
+func critPValue(stat float64, crit map[string]float64) float64 {
+	levels := []float64{0.01, 0.05, 0.10}
+	cv := []float64{crit["1%"], crit["5%"], crit["10%"]}
+	if stat < cv[0] || stat > cv[2] {
+		return math.NaN()
+	}
+	for i := 0; i < 2; i++ {
+		if stat <= cv[i+1] {
+			w := (stat - cv[i]) / (cv[i+1] - cv[i])
+			return levels[i] + w*(levels[i+1]-levels[i])
+		}
+	}
+	return levels[2]
+}
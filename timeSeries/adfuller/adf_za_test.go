@@ -0,0 +1,112 @@
+package adfuller
+
+import (
+	"math"
+	"testing"
+)
+
+// 趋势平稳 AR(1)(φ=0.6) 序列，t=55 起截距上移 3，n=100(保留 4 位小数)
+var zaSeries = []float64{
+	-1.3057, -0.9735, 0.6699, -1.2903, 0.0391, -0.489, 0.8533, 0.3501,
+	0.246, 0.7758, 0.117, 0.2777, 0.2283, 1.7845, 1.6565, -0.3247,
+	0.4595, 0.8294, 0.8461, 2.1842, 2.6915, 0.542, 2.0145, 1.1597,
+	0.6629, 0.2986, 0.4639, 1.4737, 0.1405, 0.3937, -0.0277, 0.1906,
+	0.1134, 1.1058, 1.8988, 0.6593, 1.1306, -0.3788, 0.4667, 1.5796,
+	1.3303, 3.7823, 3.4032, 1.9469, 2.2302, 1.366, 2.0581, 1.8793,
+	1.9578, -0.6149, -0.3148, 1.397, 2.4543, 3.1505, 3.0852, 6.8149,
+	7.2057, 7.0453, 7.584, 7.7037, 6.4545, 6.7213, 7.3091, 6.5749,
+	4.3747, 4.7378, 5.5286, 3.2944, 3.003, 3.1281, 6.0002, 6.8975,
+	6.3555, 7.2573, 7.2668, 5.4571, 5.5055, 6.8696, 6.4948, 9.0008,
+	7.6476, 6.9265, 6.688, 6.8333, 6.8652, 8.2201, 9.8294, 8.53,
+	6.486, 6.4022, 6.8531, 8.7011, 8.8689, 8.3975, 10.7988, 8.4152,
+	6.9605, 8.6989, 8.9735, 8.5537,
+}
+
+// 对照值由独立的 Householder QR 最小二乘实现逐突变点、逐滞后阶数(AIC)回归得到，设计矩阵同 ZivotAndrewsTest
+func TestZivotAndrewsReference(t *testing.T) {
+	tests := []struct {
+		breakType ZABreak
+		breakIdx  int
+		usedLag   int
+		tStat     float64
+		gamma     float64
+	}{
+		{ZA_BREAK_INTERCEPT, 55, 0, -5.724563675039115, -0.4960433760845011},
+		{ZA_BREAK_TREND, 31, 0, -4.648439785181175, -0.371082314123235},
+		{ZA_BREAK_BOTH, 55, 3, -5.791174726098067, -0.7186996689215781},
+	}
+	for _, tt := range tests {
+		res, err := ZivotAndrewsTest(zaSeries, tt.breakType, 3, LAG_MODE_AIC, 0.15)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.BreakIdx != tt.breakIdx || res.UsedLag != tt.usedLag || res.NObs != 96 ||
+			math.Abs(res.TStat-tt.tStat) > 1e-9 || math.Abs(res.Gamma-tt.gamma) > 1e-9 {
+			t.Errorf("%s: break=%d lag=%d nobs=%d t=%v gamma=%v", tt.breakType, res.BreakIdx, res.UsedLag, res.NObs, res.TStat, res.Gamma)
+		}
+		if res.Stats[res.BreakIdx] != res.TStat || !math.IsNaN(res.Stats[0]) {
+			t.Errorf("%s: Stats not indexed by original position", tt.breakType)
+		}
+	}
+	// 截距突变模型在 1% 水平拒绝单位根，p < 0.01 超出临界值表范围
+	res, _ := ZivotAndrewsTest(zaSeries, ZA_BREAK_INTERCEPT, 3, LAG_MODE_AIC, 0.15)
+	if res.TStat >= res.Criticals["1%"] || !math.IsNaN(res.PValue) {
+		t.Errorf("t=%v p-value %v", res.TStat, res.PValue)
+	}
+}
+
+// 已知突变点时 Perron 统计量即 ZA 在该点的 t 值，临界值按 λ 在 Perron(1989) 表中插值
+func TestPerronKnownBreak(t *testing.T) {
+	za, err := ZivotAndrewsTest(zaSeries, ZA_BREAK_INTERCEPT, 3, LAG_MODE_AIC, 0.15)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := PerronTest(zaSeries, ZA_BREAK_INTERCEPT, 55, 3, LAG_MODE_AIC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.TStat != za.Stats[55] || res.BreakIdx != 55 || res.UsedLag != 0 || res.NObs != 96 || res.Stats != nil {
+		t.Errorf("perron %+v vs za stat %v", res, za.Stats[55])
+	}
+	// λ = 0.55: 5% 临界值在 -3.76 与 -3.76 之间，1% 在 -4.32 与 -4.45 之间
+	if math.Abs(res.Criticals["5%"]+3.76) > 1e-12 || math.Abs(res.Criticals["1%"]+4.385) > 1e-12 || !math.IsNaN(res.PValue) {
+		t.Errorf("criticals %v, p-value %v", res.Criticals, res.PValue)
+	}
+
+	// 固定滞后阶数: 与 ZA 回归在该点用 maxLag 阶的 t 值一致
+	fixed, err := PerronTest(zaSeries, ZA_BREAK_BOTH, 55, 3, LAG_MODE_FIXED)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fixed.UsedLag != 3 || math.Abs(fixed.TStat-(-5.791174726098067)) > 1e-9 {
+		t.Errorf("fixed lag: lag=%d t=%v", fixed.UsedLag, fixed.TStat)
+	}
+	// λ 截断在 [0.1, 0.9]
+	if c := perronCriticals(ZA_BREAK_TREND, 0.02); c["5%"] != -3.65 {
+		t.Errorf("lambda clip: %v", c)
+	}
+
+	if _, err := PerronTest(zaSeries, ZA_BREAK_INTERCEPT, 3, 3, LAG_MODE_AIC); err == nil {
+		t.Error("break inside lag window accepted")
+	}
+	if _, err := PerronTest(zaSeries, ZA_BREAK_INTERCEPT, 99, 3, LAG_MODE_AIC); err == nil {
+		t.Error("break at sample end accepted")
+	}
+}
+
+// 临界值之间线性插值，超出表范围返回 NaN 而不是截断到 0.01/0.10
+func TestCritPValue(t *testing.T) {
+	crit := map[string]float64{"1%": -5.34, "5%": -4.80, "10%": -4.58}
+	tests := []struct {
+		stat, p float64
+	}{
+		{-5.34, 0.01}, {-5.07, 0.03}, {-4.80, 0.05}, {-4.69, 0.075}, {-4.58, 0.10},
+		{-6, math.NaN()}, {-3, math.NaN()},
+	}
+	for _, tt := range tests {
+		p := critPValue(tt.stat, crit)
+		if math.IsNaN(tt.p) != math.IsNaN(p) || (!math.IsNaN(p) && math.Abs(p-tt.p) > 1e-12) {
+			t.Errorf("stat=%v: p=%v, want %v", tt.stat, p, tt.p)
+		}
+	}
+}
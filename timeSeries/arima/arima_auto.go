@@ -0,0 +1,103 @@
+package arima
+
+import (
+	"math"
+	"method/timeSeries/adfuller"
+	"ofeisInfra/infra/errorx"
+	"ofeisInfra/infra/errorx/errCode"
+	"strategyCrypto/pkg/utils/myTools"
+)
+
+// 候选阶数的拟合信息
+type OrderInfo struct {
+	Order Order
+	AIC   float64
+	BIC   float64
+	Err   error // 拟合失败原因，成功为 nil
+}
+
+// 自动定阶
+// d: 从 0 开始逐阶差分做 ADF 检验("c", Schwert 默认滞后, AIC 选滞后)，5% 水平拒绝单位根即停止
+// p/q: 在 0~maxP × 0~maxQ 网格上拟合，按 ic (LAG_MODE_AIC / LAG_MODE_BIC) 取最优
+func AutoArima(y []float64, maxP, maxD, maxQ int, withConst bool, method FitMethod, ic adfuller.LagMode) (best *ArimaModel, info []OrderInfo, err error) {
+	if maxP < 0 || maxD < 0 || maxQ < 0 {
+		return nil, nil, errorx.New(errCode.INVALID_VALUE, "最大阶数不能为负")
+	}
+	if ic != adfuller.LAG_MODE_AIC && ic != adfuller.LAG_MODE_BIC {
+		return nil, nil, errorx.New(errCode.INVALID_VALUE, "定阶准则仅支持 AIC/BIC")
+	}
+
+	d := SelectDiffOrder(y, maxD)
+
+	bestIC := math.Inf(1)
+	for p := 0; p <= maxP; p++ {
+		for q := 0; q <= maxQ; q++ {
+			order := Order{P: p, D: d, Q: q}
+			model, fitErr := Fit(y, order, withConst, method)
+			if fitErr != nil {
+				info = append(info, OrderInfo{Order: order, AIC: math.NaN(), BIC: math.NaN(), Err: fitErr})
+				continue
+			}
+			info = append(info, OrderInfo{Order: order, AIC: model.AIC, BIC: model.BIC})
+			crit := model.AIC
+			if ic == adfuller.LAG_MODE_BIC {
+				crit = model.BIC
+			}
+			if crit < bestIC {
+				bestIC = crit
+				best = model
+			}
+		}
+	}
+	if best == nil {
+		return nil, info, errorx.New(errCode.INVALID_VALUE, "所有候选阶数均拟合失败")
+	}
+	return best, info, nil
+}
+
+// 用 ADF 检验确定差分阶数: 返回使序列在 5% 水平拒绝单位根的最小 d(不超过 maxD)
+func SelectDiffOrder(y []float64, maxD int) int {
+	for d := 0; d < maxD; d++ {
+		w := difference(y, d)
+		res, err := adfuller.AdfTest(w, "c", adfuller.SchwertMaxLag(len(w)), adfuller.LAG_MODE_AIC, adfuller.LEFT_TAIL)
+		if err == nil && res.TStat < res.Criticals["5%"] {
+			return d
+		}
+	}
+	return maxD
+}
+
+type Diagnostics struct {
+	LjungBox  []adfuller.LjungBoxRow // 标准化残差的 Ljung-Box 表(自由度扣除 p+q)
+	ResidMean float64                // 标准化残差均值
+	ResidVar  float64                // 标准化残差方差
+	Skewness  float64                // 偏度
+	Kurtosis  float64                // 超额峰度
+}
+
+// 残差诊断
+func (m *ArimaModel) Diagnose(lags int) (Diagnostics, error) {
+	lb, err := adfuller.LjungBoxTest(m.StdResid, lags, m.Order.P+m.Order.Q)
+	if err != nil {
+		return Diagnostics{}, err
+	}
+	n := float64(len(m.StdResid))
+	mu := myTools.ArrMean(m.StdResid)
+	var m2, m3, m4 float64
+	for _, v := range m.StdResid {
+		d := v - mu
+		m2 += d * d
+		m3 += d * d * d
+		m4 += d * d * d * d
+	}
+	m2 /= n
+	m3 /= n
+	m4 /= n
+	return Diagnostics{
+		LjungBox:  lb,
+		ResidMean: mu,
+		ResidVar:  m2,
+		Skewness:  m3 / math.Pow(m2, 1.5),
+		Kurtosis:  m4/(m2*m2) - 3,
+	}, nil
+}
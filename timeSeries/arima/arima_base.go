@@ -0,0 +1,239 @@
+// ARIMA(p,d,q) 建模
+// 对 d 阶差分后的序列 w_t 拟合 ARMA(p,q):
+//
+//	(w_t - μ) = Σφ_i(w_{t-i} - μ) + ε_t + Σθ_j ε_{t-j}
+//
+// 估计: 精确极大似然(Kalman 滤波) 或 条件平方和(CSS)，AR 初值取自 adfuller.DetectAR 的 OLS 结果
+// 优化在平稳/可逆约束变换后的无约束空间进行，标准误由原参数空间的数值 Hessian 得到
+package arima
+
+import (
+	"fmt"
+	"math"
+	"method/timeSeries/adfuller"
+	"method/timeSeries/internal/mle"
+	"ofeisInfra/infra/errorx"
+	"ofeisInfra/infra/errorx/errCode"
+	"strategyCrypto/pkg/utils/myTools"
+
+	"gonum.org/v1/gonum/diff/fd"
+	"gonum.org/v1/gonum/optimize"
+)
+
+type Order struct {
+	P int // AR阶数
+	D int // 差分阶数
+	Q int // MA阶数
+}
+
+func (o Order) String() string {
+	return fmt.Sprintf("ARIMA(%d,%d,%d)", o.P, o.D, o.Q)
+}
+
+type ArimaModel struct {
+	Order     Order
+	Method    FitMethod // 估计方法
+	WithConst bool      // 差分序列是否含均值项
+	AR        []float64 // φ_1..φ_p
+	MA        []float64 // θ_1..θ_q
+	Const     float64   // 差分序列均值 μ (d>0 时为漂移)
+	Sigma2    float64   // 创新方差
+	Params    []float64 // 参数向量 [φ..., θ..., (μ)]
+	SE        []float64 // 参数标准误
+	ZStats    []float64 // z统计量
+	PValues   []float64 // p值（双尾）
+	LogLik    float64
+	AIC       float64
+	BIC       float64
+	NObs      int       // 似然使用的有效样本量
+	Resid     []float64 // 一步预测误差(创新)
+	StdResid  []float64 // 标准化残差
+
+	series []float64 // 原始序列，预测时逆差分用
+	w      []float64 // 差分序列
+}
+
+// 拟合 ARIMA(p,d,q)
+// input: y 原始序列(不可含 NaN); order 阶数; withConst 差分序列是否含均值项; method 估计方法
+func Fit(y []float64, order Order, withConst bool, method FitMethod) (*ArimaModel, error) {
+	p, d, q := order.P, order.D, order.Q
+	if p < 0 || d < 0 || q < 0 {
+		return nil, errorx.New(errCode.INVALID_VALUE, "ARIMA 阶数不能为负")
+	}
+	if method != METHOD_EXACT_MLE && method != METHOD_CSS {
+		return nil, errorx.New(errCode.INVALID_VALUE, "未知的估计方法")
+	}
+	for _, v := range y {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return nil, errorx.New(errCode.INVALID_VALUE, "序列含 NaN/Inf")
+		}
+	}
+	w := difference(y, d)
+	nParams := p + q
+	if withConst {
+		nParams++
+	}
+	if len(w)-p <= nParams+1 {
+		return nil, errorx.New(errCode.INVALID_VALUE, fmt.Sprintf("样本量过小, 无法拟合 %s", order))
+	}
+
+	// 1. 初值: AR 取 DetectAR 的 OLS 估计，MA 取 0，μ 取样本均值
+	mu0 := 0.0
+	if withConst {
+		mu0 = myTools.ArrMean(w)
+	}
+	ar0 := make([]float64, p)
+	if p > 0 {
+		z := make([]float64, len(w))
+		for i, v := range w {
+			z[i] = v - mu0
+		}
+		_, info := adfuller.DetectAR(z, p)
+		for _, res := range info {
+			if res.P == p {
+				copy(ar0, res.Coeffs[:p])
+			}
+		}
+	}
+	x0 := make([]float64, 0, nParams)
+	x0 = append(x0, arInvTransform(ar0)...)
+	x0 = append(x0, make([]float64, q)...)
+	if withConst {
+		x0 = append(x0, mu0)
+	}
+
+	unpack := func(x []float64) (ar, ma []float64, mu float64) {
+		ar = arTransform(x[:p])
+		ma = maTransform(x[p : p+q])
+		if withConst {
+			mu = x[p+q]
+		}
+		return
+	}
+	negLogLik := func(ar, ma []float64, mu float64) float64 {
+		z := make([]float64, len(w))
+		for i, v := range w {
+			z[i] = v - mu
+		}
+		var ll float64
+		if method == METHOD_CSS {
+			ll, _, _ = cssLogLik(z, ar, ma)
+		} else {
+			var err error
+			ll, _, _, err = exactLogLik(z, ar, ma)
+			if err != nil {
+				return math.Inf(1)
+			}
+		}
+		if math.IsNaN(ll) {
+			return math.Inf(1)
+		}
+		return -ll
+	}
+
+	// 2. 无约束空间极大似然
+	xHat := x0
+	if nParams > 0 {
+		obj := func(x []float64) float64 {
+			ar, ma, mu := unpack(x)
+			return negLogLik(ar, ma, mu)
+		}
+		xHat = minimize(obj, x0)
+	}
+	ar, ma, mu := unpack(xHat)
+
+	// 3. 最终似然与残差
+	model := &ArimaModel{
+		Order:     order,
+		Method:    method,
+		WithConst: withConst,
+		AR:        ar,
+		MA:        ma,
+		Const:     mu,
+		series:    append([]float64(nil), y...),
+		w:         w,
+	}
+	z := make([]float64, len(w))
+	for i, v := range w {
+		z[i] = v - mu
+	}
+	if method == METHOD_CSS {
+		ll, sigma2, resid := cssLogLik(z, ar, ma)
+		model.LogLik, model.Sigma2 = ll, sigma2
+		model.Resid = resid[p:]
+		model.StdResid = make([]float64, len(model.Resid))
+		for i, e := range model.Resid {
+			model.StdResid[i] = e / math.Sqrt(sigma2)
+		}
+	} else {
+		ll, sigma2, out, err := exactLogLik(z, ar, ma)
+		if err != nil {
+			return nil, err
+		}
+		model.LogLik, model.Sigma2 = ll, sigma2
+		model.Resid = out.v
+		model.StdResid = make([]float64, len(out.v))
+		for i, v := range out.v {
+			model.StdResid[i] = v / math.Sqrt(sigma2*out.F[i])
+		}
+	}
+	model.NObs = len(model.Resid)
+	k := float64(nParams + 1) // 含 σ²
+	model.AIC = -2*model.LogLik + 2*k
+	model.BIC = -2*model.LogLik + k*math.Log(float64(model.NObs))
+
+	// 4. 原参数空间数值 Hessian → 标准误
+	model.Params = make([]float64, 0, nParams)
+	model.Params = append(model.Params, ar...)
+	model.Params = append(model.Params, ma...)
+	if withConst {
+		model.Params = append(model.Params, mu)
+	}
+	model.SE, model.ZStats, model.PValues = mle.HessianInference(func(theta []float64) float64 {
+		m := 0.0
+		if withConst {
+			m = theta[p+q]
+		}
+		return negLogLik(theta[:p], theta[p:p+q], m)
+	}, model.Params)
+
+	return model, nil
+}
+
+// 无约束极小化: 数值梯度 BFGS，失败时退化为 Nelder-Mead
+func minimize(obj func([]float64) float64, x0 []float64) []float64 {
+	problem := optimize.Problem{
+		Func: obj,
+		Grad: func(grad, x []float64) {
+			fd.Gradient(grad, obj, x, &fd.Settings{Formula: fd.Central})
+		},
+	}
+	best := x0
+	bestF := obj(x0)
+	if res, err := optimize.Minimize(problem, x0, nil, &optimize.BFGS{}); res != nil && !math.IsNaN(res.F) && res.F <= bestF {
+		best, bestF = res.X, res.F
+		if err == nil {
+			return best
+		}
+	}
+	if res, _ := optimize.Minimize(optimize.Problem{Func: obj}, best, nil, &optimize.NelderMead{}); res != nil && !math.IsNaN(res.F) && res.F < bestF {
+		best = res.X
+	}
+	return best
+}
+
+// d 阶差分
+func difference(y []float64, d int) []float64 {
+	w := append([]float64(nil), y...)
+	for k := 0; k < d; k++ {
+		if len(w) < 2 {
+			return nil
+		}
+		next := make([]float64, len(w)-1)
+		for i := 1; i < len(w); i++ {
+			next[i-1] = w[i] - w[i-1]
+		}
+		w = next
+	}
+	return w
+}
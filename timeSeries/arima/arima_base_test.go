@@ -0,0 +1,287 @@
+package arima
+
+import (
+	"math"
+	"math/rand"
+	"method/timeSeries/adfuller"
+	"testing"
+
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// AR(1): w_t - 2 = 0.6(w_{t-1} - 2) + ε_t，n=200(保留 4 位小数)
+var ar1Sample = []float64{
+	2.0, 1.1985, 1.3182, 3.0213, 2.886, 2.7234, 3.1811, 1.5647,
+	2.6011, 3.4706, 2.8042, 5.6112, 4.0386, 3.241, 3.1326, 3.7097,
+	4.6469, 1.7774, 0.5586, 2.5707, 2.0888, 1.6882, 1.7308, 3.6278,
+	3.2429, 1.9467, 0.471, -0.2888, 0.0265, -0.551, -1.1562, 0.998,
+	2.1532, 1.4085, 3.397, 3.3841, 2.2986, 2.8326, 2.8607, 1.8397,
+	3.478, 1.4827, 2.5841, 1.9671, 3.1364, 3.2423, 4.0718, 1.2572,
+	1.3904, 2.7829, 2.0842, 4.1907, 4.293, 4.4423, 3.0802, 0.3354,
+	1.2103, 1.5085, 2.4732, 1.7781, 0.4555, 0.3396, -0.7232, -0.6747,
+	0.3138, 1.0945, 2.1412, 1.3577, 2.3903, 2.1437, 0.698, 0.243,
+	0.8799, 0.6358, 0.4855, 1.5121, 0.3334, 0.671, 0.2937, -0.6121,
+	0.2534, 2.1336, 1.7503, 1.1924, 2.8956, 3.1972, 3.3343, 4.0426,
+	3.4844, 2.8666, 3.5168, 3.242, 1.3639, 2.6707, 2.6514, 4.2507,
+	3.4289, 0.9085, 1.3881, 1.4618, 3.5304, 4.2133, 4.8655, 3.4192,
+	3.1008, 4.3, 2.4121, 2.7026, 3.6526, 2.4427, 2.3481, -0.1852,
+	1.9029, 0.7073, 2.3737, 2.3347, 0.4917, -0.5473, -0.9264, -0.3805,
+	1.5269, 1.2797, 1.7172, 0.139, 0.9995, 0.8414, 1.0242, 1.4673,
+	2.8296, 3.0248, 3.0274, 2.5844, 1.877, 2.3687, 2.6083, 2.1586,
+	2.0721, 1.5418, 0.5337, 1.2211, -0.1922, 0.4853, -0.0995, 0.2906,
+	2.2468, 2.5727, 2.9254, 2.1378, 0.7994, 1.9819, 2.8121, 1.9093,
+	1.5339, 1.3909, 1.1863, 2.041, 2.9147, 2.1733, 3.653, 2.2816,
+	2.5923, 3.1474, 0.9653, 1.4507, 1.1693, 1.439, 2.1135, 2.0292,
+	0.9018, 3.2974, 4.0709, 3.512, 3.0461, 3.5168, 2.2656, 2.9766,
+	1.5335, 2.4693, 3.148, 2.7023, 2.2338, 2.2984, 1.2635, 0.6811,
+	1.1338, 1.7556, 0.737, 0.8982, 1.4466, 1.5832, 1.1387, 1.4036,
+	1.5811, 2.8422, 3.3876, 2.5523, 3.6484, 2.6535, 1.5192, 0.74,
+}
+
+// MA(1): w_t = ε_t + 0.5ε_{t-1}，n=200(保留 4 位小数)
+var ma1Sample = []float64{
+	1.7537, 1.452, -1.7134, -1.3725, -1.0946, -1.5359, 0.5239, 1.4292,
+	-0.2827, -0.028, 0.6459, 0.6479, 1.5341, 1.2914, -0.1523, 0.0052,
+	-1.7501, -1.7656, -0.0541, 0.003, -1.0896, -1.4515, -1.4335, -0.3543,
+	-0.8345, -0.2606, -0.3987, 0.1493, -0.4842, -2.1797, 0.0298, 0.2873,
+	-0.0149, 0.8, 1.8543, 0.9311, 0.8239, 1.292, 1.4897, -0.6582,
+	-1.7551, -1.8275, -2.0602, -1.9067, -1.1427, -0.7338, 1.9002, 2.0723,
+	1.4379, 1.2711, 1.7628, -0.6597, -0.658, -0.3047, 1.0349, 0.8348,
+	0.6966, 0.7024, 0.1485, 1.7598, 1.591, -1.2995, 0.6667, -0.6491,
+	0.7689, 3.9618, 0.7197, 0.8386, 0.8157, -0.6129, 0.8585, -0.0836,
+	-0.6825, -1.0683, -0.5226, -0.6972, -1.1109, 0.9683, 1.5565, -0.5147,
+	-0.8221, -0.338, -0.7542, -0.2409, -0.2314, 0.8297, -0.3991, -0.4328,
+	-0.1122, -0.5455, 1.2818, 1.1414, -2.5968, -0.9399, -0.7684, -1.3447,
+	-0.8366, -0.4124, -0.7996, -1.1237, 0.0111, 0.6692, 1.0726, 0.4389,
+	-0.9459, 0.3027, 0.6122, -0.1707, 0.0321, 0.2978, -0.9802, -0.376,
+	0.9533, 0.5351, 0.0306, 0.5937, -0.0622, 0.0069, 0.5153, 0.873,
+	-0.0101, 0.8668, -0.4407, 0.6486, 0.217, 0.8009, 2.2907, 2.7357,
+	1.1831, -0.2788, 0.1475, -1.215, 0.7356, -0.9874, -0.3128, -1.3932,
+	-1.7986, -0.1894, -1.1853, -1.2136, -2.4999, 0.3522, -0.268, -0.419,
+	0.2096, -1.0791, -1.3856, 0.0757, 0.1699, -0.7468, -0.2886, -0.8327,
+	-0.086, 0.498, -0.9904, 0.3851, 0.5792, -0.095, 0.9698, -0.1984,
+	0.5974, 0.9518, 1.2159, 2.6795, 0.6753, 0.0829, 1.0197, 0.7573,
+	0.7345, 1.3048, -0.9418, -0.9961, -1.0359, -0.0407, -0.9117, -0.3549,
+	0.5773, 0.5217, 1.5445, 1.5354, 0.3653, -0.4216, 0.3069, 2.5684,
+	1.1419, -0.1479, -2.1266, -0.1736, 0.2661, -0.9586, 0.2596, -0.9404,
+	1.1453, 0.4115, 0.7857, 1.1055, 0.1134, -2.0095, -0.6195, -1.557,
+}
+
+// 固定参数下的 Kalman 精确似然，对照 AR(1) 闭式似然与 MA(1) innovations 算法
+func TestExactLogLikReference(t *testing.T) {
+	z := make([]float64, len(ar1Sample))
+	for i, v := range ar1Sample {
+		z[i] = v - 2
+	}
+	tests := []struct {
+		name       string
+		z          []float64
+		ar, ma     []float64
+		ll, sigma2 float64
+	}{
+		{"ar1", z, []float64{0.6}, nil, -278.1476278235806, 0.9430535381400011},
+		{"ma1", ma1Sample, nil, []float64{0.5}, -279.9797515621152, 0.961252688631383},
+		{"ma1-neg", ma1Sample, nil, []float64{-0.3}, -334.01401550902176, 1.651677660476833},
+	}
+	for _, tt := range tests {
+		ll, sigma2, _, err := exactLogLik(tt.z, tt.ar, tt.ma)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if math.Abs(ll-tt.ll) > 1e-9 || math.Abs(sigma2-tt.sigma2) > 1e-12 {
+			t.Errorf("%s: ll=%v sigma2=%v", tt.name, ll, sigma2)
+		}
+	}
+}
+
+// 估计结果对照: 精确 MLE 为独立实现的似然经 Nelder-Mead 极大化所得，
+// AR(1) 的 CSS 等价于 w_t 对 (1, w_{t-1}) 的 OLS(μ = c/(1-φ))
+func TestFitReference(t *testing.T) {
+	tests := []struct {
+		name      string
+		y         []float64
+		order     Order
+		withConst bool
+		method    FitMethod
+		params    []float64
+		logLik    float64
+		sigma2    float64
+	}{
+		{"ar1-mle", ar1Sample, Order{1, 0, 0}, true, METHOD_EXACT_MLE, []float64{0.6414517486058314, 1.9955370338718639}, -277.85331406799787, 0.9398880744016411},
+		{"ma1-mle", ma1Sample, Order{0, 0, 1}, false, METHOD_EXACT_MLE, []float64{0.4428367781639101}, -279.5928915079172, 0.9578734759658286},
+		{"ar1-css", ar1Sample, Order{1, 0, 0}, true, METHOD_CSS, []float64{0.6446644621040779, 1.9952746521150222}, math.NaN(), 0.9445945299361961},
+		{"ma1-css", ma1Sample, Order{0, 0, 1}, false, METHOD_CSS, []float64{0.4451014518737795}, -279.5810362191858, 0.9588058220472747},
+	}
+	for _, tt := range tests {
+		m, err := Fit(tt.y, tt.order, tt.withConst, tt.method)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i, p := range tt.params {
+			if math.Abs(m.Params[i]-p) > 1e-4 {
+				t.Errorf("%s: param %d = %v, want %v", tt.name, i, m.Params[i], p)
+			}
+		}
+		if (!math.IsNaN(tt.logLik) && math.Abs(m.LogLik-tt.logLik) > 1e-6) || math.Abs(m.Sigma2-tt.sigma2) > 1e-4 {
+			t.Errorf("%s: logLik=%v sigma2=%v", tt.name, m.LogLik, m.Sigma2)
+		}
+		for i, se := range m.SE {
+			if !(se > 0 && se < 0.5) {
+				t.Errorf("%s: SE %d = %v", tt.name, i, se)
+			}
+		}
+	}
+}
+
+// 按 ARMA(p,q) 递推模拟序列，丢弃前 100 个预热值
+func simulateArma(ar, ma []float64, n int, seed int64) []float64 {
+	rng := rand.New(rand.NewSource(seed))
+	const burn = 100
+	w := make([]float64, n+burn)
+	eps := make([]float64, n+burn)
+	for t := range w {
+		eps[t] = rng.NormFloat64()
+		w[t] = eps[t]
+		for i, phi := range ar {
+			if t-i-1 >= 0 {
+				w[t] += phi * w[t-i-1]
+			}
+		}
+		for j, theta := range ma {
+			if t-j-1 >= 0 {
+				w[t] += theta * eps[t-j-1]
+			}
+		}
+	}
+	return w[burn:]
+}
+
+// AR(1) 的 h 步预测闭式解: 均值 μ + φ^h(y_n - μ)，方差 σ²Σ_{j<h}φ^{2j}
+func TestForecastAR1ClosedForm(t *testing.T) {
+	m, err := Fit(ar1Sample, Order{1, 0, 0}, true, METHOD_EXACT_MLE)
+	if err != nil {
+		t.Fatal(err)
+	}
+	const steps, alpha = 10, 0.05
+	fc, err := m.Forecast(steps, alpha)
+	if err != nil {
+		t.Fatal(err)
+	}
+	phi, mu := m.AR[0], m.Const
+	zCrit := distuv.UnitNormal.Quantile(1 - alpha/2)
+	dev := ar1Sample[len(ar1Sample)-1] - mu
+	cum := 0.0
+	for h := 1; h <= steps; h++ {
+		cum += math.Pow(phi, float64(2*(h-1)))
+		mean := mu + math.Pow(phi, float64(h))*dev
+		se := math.Sqrt(m.Sigma2 * cum)
+		if math.Abs(fc.Mean[h-1]-mean) > 1e-9 || math.Abs(fc.SE[h-1]-se) > 1e-9 {
+			t.Errorf("h=%d: mean=%v se=%v, want %v %v", h, fc.Mean[h-1], fc.SE[h-1], mean, se)
+		}
+		if width := fc.Upper[h-1] - fc.Lower[h-1]; math.Abs(width-2*zCrit*se) > 1e-9 {
+			t.Errorf("h=%d: interval width %v, want %v", h, width, 2*zCrit*se)
+		}
+	}
+}
+
+// MA(1) 两步以上的预测回到均值，方差为 σ²(1+θ²)
+func TestForecastMA1(t *testing.T) {
+	m, err := Fit(ma1Sample, Order{0, 0, 1}, false, METHOD_EXACT_MLE)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fc, err := m.Forecast(5, 0.05)
+	if err != nil {
+		t.Fatal(err)
+	}
+	theta := m.MA[0]
+	if math.Abs(fc.SE[0]-math.Sqrt(m.Sigma2)) > 1e-9 {
+		t.Errorf("h=1: se=%v", fc.SE[0])
+	}
+	for h := 1; h < 5; h++ {
+		if math.Abs(fc.Mean[h]) > 1e-12 || math.Abs(fc.SE[h]-math.Sqrt(m.Sigma2*(1+theta*theta))) > 1e-9 {
+			t.Errorf("h=%d: mean=%v se=%v", h+1, fc.Mean[h], fc.SE[h])
+		}
+	}
+}
+
+func TestSelectDiffOrder(t *testing.T) {
+	rng := rand.New(rand.NewSource(30))
+	walk := make([]float64, 500)
+	for i := 1; i < len(walk); i++ {
+		walk[i] = walk[i-1] + rng.NormFloat64()
+	}
+	if d := SelectDiffOrder(walk, 2); d != 1 {
+		t.Errorf("random walk: d=%d, want 1", d)
+	}
+	if d := SelectDiffOrder(ar1Sample, 2); d != 0 {
+		t.Errorf("AR(1): d=%d, want 0", d)
+	}
+}
+
+func TestAutoArimaRecoversARMA11(t *testing.T) {
+	y := simulateArma([]float64{0.6}, []float64{0.4}, 1000, 31)
+	for _, ic := range []adfuller.LagMode{adfuller.LAG_MODE_AIC, adfuller.LAG_MODE_BIC} {
+		best, info, err := AutoArima(y, 2, 1, 2, true, METHOD_EXACT_MLE, ic)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if best.Order != (Order{1, 0, 1}) || len(info) != 9 {
+			t.Errorf("%s: best %s, %d candidates", ic, best.Order, len(info))
+			continue
+		}
+		if math.Abs(best.AR[0]-0.6) > 3*best.SE[0] || math.Abs(best.MA[0]-0.4) > 3*best.SE[1] {
+			t.Errorf("%s: AR=%v MA=%v SE=%v", ic, best.AR, best.MA, best.SE)
+		}
+	}
+}
+
+// Ljung-Box 统计量按定义独立计算，自由度扣除 p+q
+func TestDiagnoseLjungBox(t *testing.T) {
+	const lags = 10
+	tests := []struct {
+		name   string
+		order  Order
+		reject bool
+	}{
+		{"ar1", Order{1, 0, 0}, false},
+		{"white-noise", Order{0, 0, 0}, true},
+	}
+	for _, tt := range tests {
+		m, err := Fit(ar1Sample, tt.order, true, METHOD_EXACT_MLE)
+		if err != nil {
+			t.Fatal(err)
+		}
+		diag, err := m.Diagnose(lags)
+		if err != nil {
+			t.Fatal(err)
+		}
+		e := m.StdResid
+		n := float64(len(e))
+		mu := 0.0
+		for _, v := range e {
+			mu += v / n
+		}
+		var c0, q float64
+		for _, v := range e {
+			c0 += (v - mu) * (v - mu)
+		}
+		for k := 1; k <= lags; k++ {
+			ck := 0.0
+			for i := k; i < len(e); i++ {
+				ck += (e[i] - mu) * (e[i-k] - mu)
+			}
+			q += (ck / c0) * (ck / c0) / (n - float64(k))
+		}
+		q *= n * (n + 2)
+		df := float64(lags - tt.order.P - tt.order.Q)
+		pValue := 1 - distuv.ChiSquared{K: df}.CDF(q)
+		row := diag.LjungBox[lags-1]
+		if math.Abs(row.LbStat-q) > 1e-9 || math.Abs(row.LbPValue-pValue) > 1e-9 {
+			t.Errorf("%s: Q=%v p=%v, want %v %v", tt.name, row.LbStat, row.LbPValue, q, pValue)
+		}
+		if (row.LbPValue < 0.05) != tt.reject {
+			t.Errorf("%s: p=%v", tt.name, row.LbPValue)
+		}
+	}
+}
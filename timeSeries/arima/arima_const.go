@@ -0,0 +1,32 @@
+package arima
+
+// 参数估计方法
+type FitMethod int
+
+const (
+	METHOD_EXACT_MLE FitMethod = iota // "exact-mle" Kalman滤波精确似然
+	METHOD_CSS                        // "css" 条件平方和(条件似然)
+	METHOD_ERROR                      // "ERROR"
+)
+
+func (s FitMethod) String() string {
+	switch s {
+	case METHOD_EXACT_MLE:
+		return "exact-mle"
+	case METHOD_CSS:
+		return "css"
+	default:
+		return "ERROR"
+	}
+}
+
+func GetMyFitMethod(s string) FitMethod {
+	switch s {
+	case "exact-mle":
+		return METHOD_EXACT_MLE
+	case "css":
+		return METHOD_CSS
+	default:
+		return METHOD_ERROR
+	}
+}
@@ -0,0 +1,125 @@
+package arima
+
+import (
+	"math"
+	"ofeisInfra/infra/errorx"
+	"ofeisInfra/infra/errorx/errCode"
+
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+type ForecastResult struct {
+	Mean  []float64 // 点预测(原始序列尺度)
+	SE    []float64 // 预测标准误
+	Lower []float64 // 预测区间下界
+	Upper []float64 // 预测区间上界
+	Alpha float64   // 显著性水平，区间置信度为 1-α
+}
+
+// 多步预测
+// 差分序列的点预测由 Kalman 滤波末端状态递推，再逐阶逆差分回原始尺度；
+// 预测方差按整合模型 φ(B)(1-B)^d 的 ψ 权重计算: Var_h = σ²Σ_{j<h}ψ_j²
+// input: steps 预测步数; alpha 显著性水平(如 0.05 对应 95% 区间)
+func (m *ArimaModel) Forecast(steps int, alpha float64) (ForecastResult, error) {
+	if steps <= 0 {
+		return ForecastResult{}, errorx.New(errCode.INVALID_VALUE, "预测步数必须 > 0")
+	}
+	if alpha <= 0 || alpha >= 1 {
+		return ForecastResult{}, errorx.New(errCode.INVALID_VALUE, "alpha 必须在 (0,1) 之间")
+	}
+
+	// 1. 差分序列预测
+	z := make([]float64, len(m.w))
+	for i, v := range m.w {
+		z[i] = v - m.Const
+	}
+	ssm := newArmaSSM(m.AR, m.MA)
+	out, err := ssm.filter(z)
+	if err != nil {
+		return ForecastResult{}, err
+	}
+	wHat := make([]float64, steps)
+	a := out.aNext
+	next := make([]float64, ssm.r)
+	for h := 0; h < steps; h++ {
+		wHat[h] = m.Const + a[0]
+		for i := 0; i < ssm.r; i++ {
+			sum := 0.0
+			for k := 0; k < ssm.r; k++ {
+				sum += ssm.T[i][k] * a[k]
+			}
+			next[i] = sum
+		}
+		a, next = next, a
+	}
+
+	// 2. 逐阶逆差分: f_k[h] = f_k[h-1] + f_{k+1}[h]，f_k[-1] 为 Δ^k y 的末值
+	fc := wHat
+	for k := m.Order.D - 1; k >= 0; k-- {
+		level := difference(m.series, k)
+		last := level[len(level)-1]
+		undiff := make([]float64, steps)
+		for h := 0; h < steps; h++ {
+			last += fc[h]
+			undiff[h] = last
+		}
+		fc = undiff
+	}
+
+	// 3. ψ 权重与预测区间
+	psi := psiWeights(m.AR, m.MA, m.Order.D, steps)
+	zCrit := distuv.UnitNormal.Quantile(1 - alpha/2)
+	result := ForecastResult{
+		Mean:  fc,
+		SE:    make([]float64, steps),
+		Lower: make([]float64, steps),
+		Upper: make([]float64, steps),
+		Alpha: alpha,
+	}
+	cum := 0.0
+	for h := 0; h < steps; h++ {
+		cum += psi[h] * psi[h]
+		se := math.Sqrt(m.Sigma2 * cum)
+		result.SE[h] = se
+		result.Lower[h] = fc[h] - zCrit*se
+		result.Upper[h] = fc[h] + zCrit*se
+	}
+	return result, nil
+}
+
+// 整合模型 φ*(B) = φ(B)(1-B)^d 的 MA(∞) 权重 ψ_0..ψ_{n-1}
+// ψ_0 = 1, ψ_j = θ_j + Σ_{i=1}^{j} φ*_i ψ_{j-i}
+func psiWeights(ar, ma []float64, d int, n int) []float64 {
+	// φ(B) 多项式系数: [1, -φ_1, ..., -φ_p]
+	poly := make([]float64, len(ar)+1)
+	poly[0] = 1
+	for i, v := range ar {
+		poly[i+1] = -v
+	}
+	for k := 0; k < d; k++ {
+		next := make([]float64, len(poly)+1)
+		for i, c := range poly {
+			next[i] += c
+			next[i+1] -= c
+		}
+		poly = next
+	}
+	phiStar := make([]float64, len(poly)-1)
+	for i := range phiStar {
+		phiStar[i] = -poly[i+1]
+	}
+
+	psi := make([]float64, n)
+	psi[0] = 1
+	for j := 1; j < n; j++ {
+		v := 0.0
+		if j <= len(ma) {
+			v = ma[j-1]
+		}
+		for i := 1; i <= j && i <= len(phiStar); i++ {
+			v += phiStar[i-1] * psi[j-i]
+		}
+		psi[j] = v
+	}
+	return psi
+}
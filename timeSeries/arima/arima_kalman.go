@@ -0,0 +1,197 @@
+// ARMA(p,q) 的状态空间表示 (Harvey 1989) 与 Kalman 滤波精确似然
+// 状态维度 r = max(p, q+1):
+//
+//	α_{t+1} = T α_t + R ε_{t+1},   w_t - μ = Z α_t
+//	T = [φ_1 1 0 .. 0; φ_2 0 1 .. 0; ...; φ_r 0 .. 0],  R = [1, θ_1, ..., θ_{r-1}]',  Z = [1, 0, ..., 0]
+//
+// 滤波时令 σ²=1，再把 σ² 从似然中集中掉: σ²_hat = (1/n)Σ v_t²/F_t
+// logL = -n/2·(ln2π + 1 + lnσ²_hat) - 1/2·Σ lnF_t
+package arima
+
+import (
+	"math"
+	"ofeisInfra/infra/errorx"
+	"ofeisInfra/infra/errorx/errCode"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// 状态空间系统矩阵
+type armaSSM struct {
+	r int
+	T [][]float64 // 转移矩阵 r×r
+	R []float64   // 扰动载荷 r
+}
+
+func newArmaSSM(ar, ma []float64) armaSSM {
+	p, q := len(ar), len(ma)
+	r := p
+	if q+1 > r {
+		r = q + 1
+	}
+	T := make([][]float64, r)
+	for i := range T {
+		T[i] = make([]float64, r)
+		if i < p {
+			T[i][0] = ar[i]
+		}
+		if i+1 < r {
+			T[i][i+1] = 1
+		}
+	}
+	R := make([]float64, r)
+	R[0] = 1
+	for j := 0; j < q; j++ {
+		R[j+1] = ma[j]
+	}
+	return armaSSM{r: r, T: T, R: R}
+}
+
+// 平稳初始协方差: P = T P T' + R R'，化为 (I - T⊗T) vec(P) = vec(RR')
+func (s armaSSM) initialCov() ([][]float64, error) {
+	r := s.r
+	r2 := r * r
+	A := mat.NewDense(r2, r2, nil)
+	b := mat.NewVecDense(r2, nil)
+	for i := 0; i < r; i++ {
+		for j := 0; j < r; j++ {
+			row := i*r + j
+			b.SetVec(row, s.R[i]*s.R[j])
+			for k := 0; k < r; k++ {
+				for l := 0; l < r; l++ {
+					v := -s.T[i][k] * s.T[j][l]
+					if row == k*r+l {
+						v += 1
+					}
+					A.Set(row, k*r+l, v)
+				}
+			}
+		}
+	}
+	var x mat.VecDense
+	if err := x.SolveVec(A, b); err != nil {
+		return nil, errorx.New(errCode.INVALID_VALUE, "初始协方差求解失败, AR 部分可能非平稳")
+	}
+	P := make([][]float64, r)
+	for i := range P {
+		P[i] = make([]float64, r)
+		for j := range P[i] {
+			P[i][j] = x.AtVec(i*r + j)
+		}
+	}
+	return P, nil
+}
+
+// Kalman 滤波输出
+type kalmanOutput struct {
+	v      []float64   // 一步预测误差 (创新)
+	F      []float64   // 创新方差(σ²=1 标度)
+	sumLog float64     // Σ lnF_t
+	sumSq  float64     // Σ v_t²/F_t
+	aNext  []float64   // a_{n+1|n}
+	PNext  [][]float64 // P_{n+1|n}(σ²=1 标度)
+}
+
+// 对去均值序列 z 做 Kalman 滤波
+func (s armaSSM) filter(z []float64) (kalmanOutput, error) {
+	r := s.r
+	P, err := s.initialCov()
+	if err != nil {
+		return kalmanOutput{}, err
+	}
+	a := make([]float64, r)
+	n := len(z)
+	out := kalmanOutput{v: make([]float64, n), F: make([]float64, n)}
+
+	TP := make([][]float64, r)
+	newP := make([][]float64, r)
+	for i := 0; i < r; i++ {
+		TP[i] = make([]float64, r)
+		newP[i] = make([]float64, r)
+	}
+	K := make([]float64, r)
+	newA := make([]float64, r)
+
+	for t := 0; t < n; t++ {
+		v := z[t] - a[0]
+		F := P[0][0]
+		if F <= 0 || math.IsNaN(F) {
+			return kalmanOutput{}, errorx.New(errCode.INVALID_VALUE, "Kalman 滤波创新方差非正")
+		}
+		out.v[t] = v
+		out.F[t] = F
+		out.sumLog += math.Log(F)
+		out.sumSq += v * v / F
+
+		// TP = T·P
+		for i := 0; i < r; i++ {
+			for j := 0; j < r; j++ {
+				sum := 0.0
+				for k := 0; k < r; k++ {
+					sum += s.T[i][k] * P[k][j]
+				}
+				TP[i][j] = sum
+			}
+		}
+		// K = T·P·Z' / F, a_{t+1} = T a_t + K v_t
+		for i := 0; i < r; i++ {
+			K[i] = TP[i][0] / F
+			sum := 0.0
+			for k := 0; k < r; k++ {
+				sum += s.T[i][k] * a[k]
+			}
+			newA[i] = sum + K[i]*v
+		}
+		// P_{t+1} = T P T' - K K' F + R R'
+		for i := 0; i < r; i++ {
+			for j := 0; j < r; j++ {
+				sum := 0.0
+				for k := 0; k < r; k++ {
+					sum += TP[i][k] * s.T[j][k]
+				}
+				newP[i][j] = sum - K[i]*K[j]*F + s.R[i]*s.R[j]
+			}
+		}
+		a, newA = newA, a
+		P, newP = newP, P
+	}
+	out.aNext = a
+	out.PNext = P
+	return out, nil
+}
+
+// 精确对数似然(σ² 已集中)
+func exactLogLik(z []float64, ar, ma []float64) (logLik, sigma2 float64, out kalmanOutput, err error) {
+	out, err = newArmaSSM(ar, ma).filter(z)
+	if err != nil {
+		return math.Inf(-1), math.NaN(), out, err
+	}
+	n := float64(len(z))
+	sigma2 = out.sumSq / n
+	logLik = -0.5*n*(math.Log(2*math.Pi)+1+math.Log(sigma2)) - 0.5*out.sumLog
+	return logLik, sigma2, out, nil
+}
+
+// 条件平方和对数似然: 前 p 个残差取 0，从 t=p 开始递推
+// e_t = z_t - Σφ_i z_{t-i} - Σθ_j e_{t-j}
+func cssLogLik(z []float64, ar, ma []float64) (logLik, sigma2 float64, resid []float64) {
+	p, q := len(ar), len(ma)
+	n := len(z)
+	resid = make([]float64, n)
+	ss := 0.0
+	for t := p; t < n; t++ {
+		e := z[t]
+		for i := 0; i < p; i++ {
+			e -= ar[i] * z[t-i-1]
+		}
+		for j := 0; j < q && t-j-1 >= 0; j++ {
+			e -= ma[j] * resid[t-j-1]
+		}
+		resid[t] = e
+		ss += e * e
+	}
+	nEff := float64(n - p)
+	sigma2 = ss / nEff
+	logLik = -0.5 * nEff * (math.Log(2*math.Pi*sigma2) + 1)
+	return logLik, sigma2, resid
+}
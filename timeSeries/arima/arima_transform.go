@@ -0,0 +1,72 @@
+// 参数约束变换 (Jones 1980 / Monahan 1984，与 statsmodels 一致)
+// 无约束实数 → tanh(x/2) 得到 (-1,1) 的偏自相关 → Durbin-Levinson 递推得到平稳 AR / 可逆 MA 系数
+// 优化器在无约束空间搜索，保证估计结果始终平稳、可逆
+package arima
+
+import "math"
+
+// 无约束参数 → 平稳AR系数
+func arTransform(params []float64) []float64 {
+	p := len(params)
+	newParams := make([]float64, p)
+	for i, v := range params {
+		newParams[i] = math.Tanh(v / 2)
+	}
+	tmp := make([]float64, p)
+	copy(tmp, newParams)
+	for j := 1; j < p; j++ {
+		a := newParams[j]
+		for k := 0; k < j; k++ {
+			tmp[k] -= a * newParams[j-k-1]
+		}
+		copy(newParams[:j], tmp[:j])
+	}
+	return newParams
+}
+
+// 平稳AR系数 → 无约束参数
+func arInvTransform(coeffs []float64) []float64 {
+	p := len(coeffs)
+	params := make([]float64, p)
+	copy(params, coeffs)
+	tmp := make([]float64, p)
+	copy(tmp, params)
+	for j := p - 1; j > 0; j-- {
+		a := clipPacf(params[j])
+		for k := 0; k < j; k++ {
+			tmp[k] = (params[k] + a*params[j-k-1]) / (1 - a*a)
+		}
+		copy(params[:j], tmp[:j])
+	}
+	for i, v := range params {
+		params[i] = 2 * math.Atanh(clipPacf(v))
+	}
+	return params
+}
+
+// 无约束参数 → 可逆MA系数
+func maTransform(params []float64) []float64 {
+	q := len(params)
+	newParams := make([]float64, q)
+	for i, v := range params {
+		newParams[i] = math.Tanh(v / 2)
+	}
+	tmp := make([]float64, q)
+	copy(tmp, newParams)
+	for j := 1; j < q; j++ {
+		b := newParams[j]
+		for k := 0; k < j; k++ {
+			tmp[k] += b * newParams[j-k-1]
+		}
+		copy(newParams[:j], tmp[:j])
+	}
+	return newParams
+}
+
+// 初值非平稳/不可逆时偏自相关可能越界，截断到 (-0.95, 0.95)
+func clipPacf(v float64) float64 {
+	if math.IsNaN(v) {
+		return 0
+	}
+	return math.Max(-0.95, math.Min(0.95, v))
+}
@@ -0,0 +1,150 @@
+// Engle-Granger 两步法协整检验
+// 1) 协整回归: y_t = c (+ δ·t) + β'X_t + u_t
+// 2) 对残差 u_t 做无常数项的 ADF 检验, H0: 残差存在单位根(不协整); H1: 协整
+// 残差是估计出来的，不能使用普通 ADF 临界值，需使用 MacKinnon(2010) 协整临界值(依赖变量个数 N)
+package coint
+
+import (
+	"fmt"
+	"method/ml/ols"
+	"method/timeSeries/adfuller"
+	"ofeisInfra/infra/errorx"
+	"ofeisInfra/infra/errorx/errCode"
+
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+type EGResult struct {
+	TStat     float64            // 残差ADF统计量
+	PValue    float64            // MacKinnon 近似p值
+	Criticals map[string]float64 // 协整临界值（1%, 5%, 10%）
+	UsedLag   int                // 残差ADF选用的滞后阶数
+	NObs      int                // 协整回归样本量
+	Trend     string             // 协整回归趋势类型 ("c"、"ct")
+	Coeffs    []float64          // 协整回归系数: [常数, (趋势), β1..βk]
+	CointVec  []float64          // 协整向量 [1, -β1, ..., -βk]，CointVec·[y, X] = 确定项 + 残差
+	Resid     []float64          // 协整回归残差(价差序列)
+	Adf       adfuller.ADFResult // 残差ADF检验明细
+}
+
+// Engle-Granger 协整检验
+// input: y 被解释序列; X 解释变量(n×k，每行一个观测); trend: "c" 或 "ct";
+// maxLag: 残差ADF最大滞后阶数, <0 时取 adfuller.SchwertMaxLag; autolag: 滞后阶数选择方法
+func EngleGrangerTest(y []float64, X [][]float64, trend string, maxLag int, autolag adfuller.LagMode) (EGResult, error) {
+	n := len(y)
+	if n == 0 || len(X) == 0 {
+		return EGResult{}, errorx.New(errCode.EMPTY_VALUE, "输入数据为空")
+	}
+	if len(X) != n {
+		return EGResult{}, errorx.New(errCode.INVALID_VALUE, "y 长度与 X 行数不匹配")
+	}
+	k := len(X[0])
+	nVars := k + 1
+	if _, ok := mackinnonCritCoef[trend]; !ok {
+		return EGResult{}, errorx.New(errCode.INVALID_VALUE, fmt.Sprintf("不支持的趋势类型 %q, 仅支持 \"c\"、\"ct\"", trend))
+	}
+	if nVars > len(mackinnonCritCoef[trend]) {
+		return EGResult{}, errorx.New(errCode.INVALID_VALUE, fmt.Sprintf("变量个数 N=%d 超出 MacKinnon 临界值表范围", nVars))
+	}
+
+	// 1. 协整回归，确定项放在最左侧
+	nDet := 1
+	if trend == "ct" {
+		nDet = 2
+	}
+	nCol := nDet + k
+	matX := mat.NewDense(n, nCol, nil)
+	for i := 0; i < n; i++ {
+		if len(X[i]) != k {
+			return EGResult{}, errorx.New(errCode.INVALID_VALUE, "X 各行长度不一致")
+		}
+		matX.Set(i, 0, 1)
+		if trend == "ct" {
+			matX.Set(i, 1, float64(i+1))
+		}
+		for j := 0; j < k; j++ {
+			matX.Set(i, nDet+j, X[i][j])
+		}
+	}
+	matY := mat.NewVecDense(n, append([]float64(nil), y...))
+	model, err := ols.MultiRegressionMat(matX, matY)
+	if err != nil {
+		return EGResult{}, err
+	}
+	if model.RSquared >= 1-1e-10 {
+		return EGResult{}, errorx.New(errCode.INVALID_VALUE, "协整回归完全拟合(R²≈1)，残差无法检验")
+	}
+
+	// 2. 残差ADF（无常数项），maxLag < 0 时取 Schwert 默认
+	adf, err := adfuller.AdfTestWithOptions(model.Resids, adfuller.AdfOptions{
+		Trend:   adfuller.TREND_N,
+		Tail:    adfuller.TAIL_LEFT,
+		MaxLag:  maxLag,
+		AutoLag: autolag,
+	})
+	if err != nil {
+		return EGResult{}, err
+	}
+
+	// 3. 协整临界值与p值
+	criticals := mackinnonCrit(trend, nVars, n-1)
+	pValue := mackinnonPValue(adf.TStat, trend, nVars)
+
+	cointVec := make([]float64, nVars)
+	cointVec[0] = 1
+	for j := 0; j < k; j++ {
+		cointVec[j+1] = -model.Coeffs[nDet+j]
+	}
+
+	return EGResult{
+		TStat:     adf.TStat,
+		PValue:    pValue,
+		Criticals: criticals,
+		UsedLag:   adf.UsedLag,
+		NObs:      n,
+		Trend:     trend,
+		Coeffs:    model.Coeffs,
+		CointVec:  cointVec,
+		Resid:     model.Resids,
+		Adf:       adf,
+	}, nil
+}
+
+// MacKinnon(2010) 有限样本临界值
+func mackinnonCrit(trend string, nVars int, nobs int) map[string]float64 {
+	coef := mackinnonCritCoef[trend][nVars-1]
+	inv := 1 / float64(nobs)
+	out := make(map[string]float64, len(critLevels))
+	for i, level := range critLevels {
+		b := coef[i]
+		out[level] = b[0] + b[1]*inv + b[2]*inv*inv + b[3]*inv*inv*inv
+	}
+	return out
+}
+
+// MacKinnon 近似p值，"c"、"ct" 均使用渐近响应面 Φ(Σ a_i τ^i)
+func mackinnonPValue(tStat float64, trend string, nVars int) float64 {
+	surf := mackinnonPCoef[trend]
+	i := nVars - 1
+	if tStat > surf.tauMax[i] {
+		return 1
+	}
+	if tStat < surf.tauMin[i] {
+		return 0
+	}
+	coef := surf.largeP[i]
+	if tStat <= surf.tauStar[i] {
+		coef = surf.smallP[i]
+	}
+	return distuv.UnitNormal.CDF(polyval(coef, tStat))
+}
+
+// 多项式求值 coef[0] + coef[1]·x + coef[2]·x^2 + ...
+func polyval(coef []float64, x float64) float64 {
+	out := 0.0
+	for i := len(coef) - 1; i >= 0; i-- {
+		out = out*x + coef[i]
+	}
+	return out
+}
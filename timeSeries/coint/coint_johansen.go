@@ -0,0 +1,275 @@
+// Johansen 协整秩检验（迹检验 + 最大特征值检验），适用于三个及以上资产
+// VECM: Δx_t = Πx_{t-1} + Σ Γ_i Δx_{t-i} + 确定项 + ε_t
+// 1) Δx_t 与 x_{t-1} 分别对滞后差分回归，得残差 R0t、Rkt
+// 2) S00 = R0'R0/T, Sk0 = Rk'R0/T, Skk = Rk'Rk/T
+// 3) 求解广义特征值问题 Sk0 S00^{-1} S0k v = λ Skk v
+// 迹统计量: LR_trace(r) = -T Σ_{i>r} ln(1-λ_i); 最大特征值统计量: LR_max(r) = -T ln(1-λ_{r+1})
+// 确定项处理与 statsmodels.coint_johansen 一致
+package coint
+
+import (
+	"fmt"
+	"math"
+	"ofeisInfra/infra/errorx"
+	"ofeisInfra/infra/errorx/errCode"
+	"sort"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+type JohansenResult struct {
+	Eig        []float64            // 特征值（降序）
+	Evec       [][]float64          // 协整向量，Evec[i] 对应 Eig[i]，满足 v'Skk v = 1
+	TraceStat  []float64            // 迹统计量，下标 r 对应 H0: 秩 <= r
+	MaxEigStat []float64            // 最大特征值统计量，下标 r 对应 H0: 秩 = r
+	TraceCrit  []map[string]float64 // 迹统计量临界值（10%, 5%, 1%）
+	MaxEigCrit []map[string]float64 // 最大特征值统计量临界值（10%, 5%, 1%）
+	DetOrder   int                  // 确定项: -1 无, 0 常数, 1 线性趋势
+	KArDiff    int                  // 滞后差分阶数
+	NObs       int                  // 有效样本量
+}
+
+// Johansen 协整检验
+// input: endog 多资产序列(n×m，每行一个观测); detOrder: -1 无确定项, 0 常数, 1 线性趋势; kArDiff: VECM 滞后差分阶数
+func JohansenTest(endog [][]float64, detOrder int, kArDiff int) (JohansenResult, error) {
+	nobs := len(endog)
+	if nobs == 0 {
+		return JohansenResult{}, errorx.New(errCode.EMPTY_VALUE, "输入数据为空")
+	}
+	neqs := len(endog[0])
+	if neqs < 2 || neqs > len(johansenTraceCrit[0]) {
+		return JohansenResult{}, errorx.New(errCode.INVALID_VALUE, fmt.Sprintf("变量个数 %d 非法，需在 2~%d 之间", neqs, len(johansenTraceCrit[0])))
+	}
+	if detOrder < -1 || detOrder > 1 {
+		return JohansenResult{}, errorx.New(errCode.INVALID_VALUE, "detOrder 仅支持 -1、0、1")
+	}
+	if kArDiff < 0 {
+		return JohansenResult{}, errorx.New(errCode.INVALID_VALUE, "kArDiff 不能为负")
+	}
+	if nobs-kArDiff-1 <= neqs*(kArDiff+1)+2 {
+		return JohansenResult{}, errorx.New(errCode.INVALID_VALUE, "样本量过小, 无法进行Johansen检验")
+	}
+
+	x := mat.NewDense(nobs, neqs, nil)
+	for i, row := range endog {
+		if len(row) != neqs {
+			return JohansenResult{}, errorx.New(errCode.INVALID_VALUE, "endog 各行长度不一致")
+		}
+		x.SetRow(i, row)
+	}
+
+	// 差分序列的确定项阶数: 有确定项时只去均值
+	f := detOrder
+	if detOrder > -1 {
+		f = 0
+	}
+	x = detrend(x, detOrder)
+
+	// dx: (nobs-1)×m
+	dx := mat.NewDense(nobs-1, neqs, nil)
+	for i := 1; i < nobs; i++ {
+		for j := 0; j < neqs; j++ {
+			dx.Set(i-1, j, x.At(i, j)-x.At(i-1, j))
+		}
+	}
+
+	// z: 滞后差分 [Δx_{t-1}, ..., Δx_{t-k}]，T×(m·k)
+	T := nobs - 1 - kArDiff
+	var z *mat.Dense
+	if kArDiff > 0 {
+		z = mat.NewDense(T, neqs*kArDiff, nil)
+		for t := 0; t < T; t++ {
+			for l := 1; l <= kArDiff; l++ {
+				for j := 0; j < neqs; j++ {
+					z.Set(t, (l-1)*neqs+j, dx.At(t+kArDiff-l, j))
+				}
+			}
+		}
+		z = detrend(z, f)
+	}
+
+	dxT := detrend(mat.DenseCopyOf(dx.Slice(kArDiff, nobs-1, 0, neqs)), f)
+	r0t := residOn(dxT, z)
+
+	lx := detrend(mat.DenseCopyOf(x.Slice(kArDiff, nobs-1, 0, neqs)), f)
+	rkt := residOn(lx, z)
+
+	// 矩矩阵
+	var skk, sk0, s00 mat.Dense
+	skk.Mul(rkt.T(), rkt)
+	skk.Scale(1/float64(T), &skk)
+	sk0.Mul(rkt.T(), r0t)
+	sk0.Scale(1/float64(T), &sk0)
+	s00.Mul(r0t.T(), r0t)
+	s00.Scale(1/float64(T), &s00)
+
+	// sig = Sk0 S00^{-1} S0k
+	var s00Inv mat.Dense
+	if err := s00Inv.Inverse(&s00); err != nil {
+		return JohansenResult{}, errorx.New(errCode.INVALID_VALUE, "S00 矩阵不可逆，请检查序列是否共线")
+	}
+	var sig, tmp mat.Dense
+	tmp.Mul(&sk0, &s00Inv)
+	sig.Mul(&tmp, sk0.T())
+
+	// Skk = LL'，令 C = L^{-1} sig L^{-T}，C 对称，特征向量 w 变换回 v = L^{-T} w 满足 v'Skk v = I
+	var chol mat.Cholesky
+	if ok := chol.Factorize(symmetrize(&skk)); !ok {
+		return JohansenResult{}, errorx.New(errCode.INVALID_VALUE, "Skk 矩阵非正定，请检查序列是否共线")
+	}
+	var L mat.TriDense
+	chol.LTo(&L)
+	var Linv mat.TriDense
+	if err := Linv.InverseTri(&L); err != nil {
+		return JohansenResult{}, errorx.New(errCode.INVALID_VALUE, "Cholesky 因子不可逆")
+	}
+	var C mat.Dense
+	tmp.Reset()
+	tmp.Mul(&Linv, &sig)
+	C.Mul(&tmp, Linv.T())
+
+	var eig mat.EigenSym
+	if ok := eig.Factorize(symmetrize(&C), true); !ok {
+		return JohansenResult{}, errorx.New(errCode.INVALID_VALUE, "特征值分解失败")
+	}
+	values := eig.Values(nil)
+	var W, V mat.Dense
+	eig.VectorsTo(&W)
+	V.Mul(Linv.T(), &W)
+
+	// 按特征值降序
+	order := make([]int, neqs)
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(a, b int) bool { return values[order[a]] > values[order[b]] })
+
+	result := JohansenResult{
+		Eig:        make([]float64, neqs),
+		Evec:       make([][]float64, neqs),
+		TraceStat:  make([]float64, neqs),
+		MaxEigStat: make([]float64, neqs),
+		TraceCrit:  make([]map[string]float64, neqs),
+		MaxEigCrit: make([]map[string]float64, neqs),
+		DetOrder:   detOrder,
+		KArDiff:    kArDiff,
+		NObs:       T,
+	}
+	for i, idx := range order {
+		lambda := values[idx]
+		// 数值误差可能使 λ 略微越界
+		if lambda < 0 {
+			lambda = 0
+		}
+		if lambda >= 1 {
+			lambda = 1 - 1e-12
+		}
+		result.Eig[i] = lambda
+		result.Evec[i] = mat.Col(nil, idx, &V)
+	}
+	for r := 0; r < neqs; r++ {
+		trace := 0.0
+		for i := r; i < neqs; i++ {
+			trace -= float64(T) * math.Log(1-result.Eig[i])
+		}
+		result.TraceStat[r] = trace
+		result.MaxEigStat[r] = -float64(T) * math.Log(1-result.Eig[r])
+		result.TraceCrit[r] = johansenCritMap(johansenTraceCrit[detOrder+1][neqs-r-1])
+		result.MaxEigCrit[r] = johansenCritMap(johansenMaxEigCrit[detOrder+1][neqs-r-1])
+	}
+	return result, nil
+}
+
+// 按显著性水平依次检验，返回迹检验与最大特征值检验判定的协整秩
+// level: "10%"、"5%"、"1%"
+func (r *JohansenResult) Rank(level string) (traceRank, maxEigRank int) {
+	traceRank, maxEigRank = len(r.Eig), len(r.Eig)
+	for i := range r.TraceStat {
+		if r.TraceStat[i] < r.TraceCrit[i][level] {
+			traceRank = i
+			break
+		}
+	}
+	for i := range r.MaxEigStat {
+		if r.MaxEigStat[i] < r.MaxEigCrit[i][level] {
+			maxEigRank = i
+			break
+		}
+	}
+	return traceRank, maxEigRank
+}
+
+// 第 i 个协整向量，按第一个资产系数归一化为 1，便于直接作为对冲比例
+func (r *JohansenResult) CointVec(i int) []float64 {
+	v := r.Evec[i]
+	out := make([]float64, len(v))
+	if v[0] == 0 {
+		copy(out, v)
+		return out
+	}
+	for j := range v {
+		out[j] = v[j] / v[0]
+	}
+	return out
+}
+
+func johansenCritMap(row [3]float64) map[string]float64 {
+	out := make(map[string]float64, len(johansenCritLevels))
+	for i, level := range johansenCritLevels {
+		out[level] = row[i]
+	}
+	return out
+}
+
+// 对每列做 order 阶多项式去趋势(order = -1 时原样返回)
+// 与 statsmodels 一致，时间轴取 linspace(-1, 1, n)
+func detrend(y *mat.Dense, order int) *mat.Dense {
+	if order < 0 {
+		return y
+	}
+	n, _ := y.Dims()
+	V := mat.NewDense(n, order+1, nil)
+	for i := 0; i < n; i++ {
+		t := -1.0
+		if n > 1 {
+			t = -1 + 2*float64(i)/float64(n-1)
+		}
+		p := 1.0
+		for j := 0; j <= order; j++ {
+			V.Set(i, j, p)
+			p *= t
+		}
+	}
+	return residOn(y, V)
+}
+
+// 最小二乘残差 y - x(x'x)^{-1}x'y，x 为空时原样返回
+func residOn(y, x *mat.Dense) *mat.Dense {
+	if x == nil {
+		return y
+	}
+	var b, fit, out mat.Dense
+	if err := b.Solve(x, y); err != nil {
+		// 共线时退化为 SVD 最小范数解
+		var svd mat.SVD
+		if !svd.Factorize(x, mat.SVDThin) {
+			return y
+		}
+		b.Reset()
+		svd.SolveTo(&b, y, svd.Rank(1e-12))
+	}
+	fit.Mul(x, &b)
+	out.Sub(y, &fit)
+	return &out
+}
+
+func symmetrize(a *mat.Dense) *mat.SymDense {
+	n, _ := a.Dims()
+	s := mat.NewSymDense(n, nil)
+	for i := 0; i < n; i++ {
+		for j := i; j < n; j++ {
+			s.SetSym(i, j, 0.5*(a.At(i, j)+a.At(j, i)))
+		}
+	}
+	return s
+}
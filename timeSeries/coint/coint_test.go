@@ -0,0 +1,201 @@
+package coint
+
+import (
+	"math"
+	"method/timeSeries/adfuller"
+	"testing"
+)
+
+// 协整检验样本，n=120(保留 4 位小数):
+// cointX1、cointX3 为独立随机游走，cointX2 = 0.5·cointX1 + AR(1)(φ=0.4) 噪声
+var cointX1 = []float64{
+	0.0, -0.0115, 1.2902, 1.3166, 0.2366, 1.2589, -0.1994, 0.4563,
+	1.4363, 0.2025, 0.7081, 0.9207, 0.9861, 1.609, 1.061, 1.9761,
+	2.504, 0.9842, 1.2678, 1.7028, 1.5756, 1.2191, 1.3325, 3.0204,
+	4.7997, 6.1208, 7.0416, 8.2872, 7.9056, 6.5991, 6.3578, 6.0807,
+	6.3188, 6.5181, 5.5668, 3.2189, 2.2077, 3.2592, 3.7854, 2.3056,
+	1.1617, 1.4322, 2.0175, 1.3726, 1.373, 1.9237, 1.3693, 0.2811,
+	-0.1765, 0.9112, 2.0114, 2.1765, 2.8339, 2.6736, 4.4099, 5.7051,
+	5.759, 6.0714, 5.5557, 5.6994, 4.1991, 4.2873, 4.3791, 4.7319,
+	2.7134, 2.8538, 3.8409, 3.6633, 5.1357, 2.97, 3.4294, 2.6664,
+	4.1906, 5.484, 5.9191, 6.0795, 6.3546, 5.7191, 5.9089, 5.5653,
+	5.7247, 5.2381, 3.967, 3.1653, 0.9196, 0.1224, -0.1676, 0.6634,
+	-0.7119, 0.2207, -0.6799, 0.224, -0.8111, -1.877, -1.0974, 0.0834,
+	1.0952, 2.3023, 1.6051, -0.1756, -1.17, -2.1477, -1.743, -2.1169,
+	-0.2158, -0.6845, 0.9109, 0.2923, -0.4578, -0.7769, -1.9231, -2.8461,
+	-0.4384, 0.3434, -1.4419, -1.6005, -2.9247, -4.7498, -5.1277, -6.179,
+}
+
+var cointX2 = []float64{
+	0.0, 0.371, 1.4, 0.4799, -1.8173, 0.8348, -0.0602, 1.1711,
+	-1.0298, 1.9828, 2.3126, 2.5962, 0.658, 1.2549, 0.1938, -0.2339,
+	0.906, 1.3355, 2.2422, 1.0432, -1.4699, 0.1419, 0.8913, 0.3426,
+	0.2086, 1.5354, 3.0741, 1.3123, 1.5501, 2.4439, 2.9638, 3.5141,
+	4.1433, 3.21, 3.1023, 0.9881, 1.1371, 1.4492, 0.2016, 0.1035,
+	-1.1722, 0.4758, 1.246, 0.6649, -0.1372, 0.6589, 0.2872, -1.7378,
+	-1.9151, 0.9563, 1.7888, 1.3542, 1.2482, 1.2037, 3.5788, 3.0893,
+	5.4088, 3.3623, 2.4856, 2.3901, 2.1921, 2.3163, 2.1982, 5.4767,
+	0.241, -0.0563, -0.2463, -0.9533, 0.9426, 0.8024, 0.0989, 0.5287,
+	2.9753, 2.7721, 2.9767, 2.2514, 1.8275, 2.6706, 4.5096, 4.1813,
+	2.7367, 2.8874, 3.1895, 1.0707, -1.3419, -0.8928, -0.5721, -1.2691,
+	-1.4579, -2.1401, -2.5036, -0.7912, -0.4311, -1.5023, -1.4218, -1.0814,
+	-1.5355, 1.2045, 1.2922, 0.2054, -0.1857, -1.4552, -0.8476, 0.3147,
+	0.5789, -1.4088, -0.8659, 0.0597, -2.8334, -0.9552, -1.5406, -2.2546,
+	-0.2047, -0.794, -0.4181, -2.3057, -3.2867, -1.5962, -0.5612, -1.3318,
+}
+
+var cointX3 = []float64{
+	0.0, -0.6731, 0.9487, 0.6579, -0.9765, -1.0617, -0.8238, -1.0935,
+	0.2338, 1.6461, 1.3084, 1.5192, -0.1866, -1.0312, -0.4747, -0.8387,
+	-0.4708, -0.9117, -2.1048, -2.1309, -1.6285, -2.8994, -2.8727, -0.9873,
+	-0.8697, -1.6752, -1.8727, -1.5186, -2.8608, -2.6666, -1.6146, -0.5944,
+	0.5217, 0.5637, 2.9145, 3.0534, 2.6891, 2.4597, 0.1025, 1.1518,
+	0.2253, 0.0946, 0.8355, 1.1127, 2.4703, 2.8898, 2.8623, 1.8145,
+	4.5115, 4.8889, 5.0547, 5.1192, 3.2894, 2.4186, 2.19, 1.9472,
+	1.7669, 1.8836, 1.2919, 2.0401, 3.5872, 2.4673, 2.9212, 2.9236,
+	3.0817, 2.721, 2.079, 1.6096, 1.2093, 2.4442, 2.9895, 3.812,
+	3.4141, 2.7294, 2.4137, 1.303, 0.9755, 1.2177, 1.3941, 0.8326,
+	0.5242, 0.6499, -2.1475, -2.1903, -3.7914, -4.0602, -2.882, -2.7077,
+	-1.8734, -2.6151, -2.883, -2.7909, -0.8433, 0.399, -0.6106, -1.2199,
+	-1.1894, -1.0769, -0.7789, -1.1777, -0.3657, -1.0007, -1.5647, -1.9918,
+	-1.0665, -1.055, -0.9498, -0.4378, -0.3338, 1.0422, 1.7727, 1.9392,
+	2.8138, 2.7375, 2.7997, 2.4674, 4.2688, 4.4756, 4.4288, 4.3338,
+}
+
+// 对照值由独立实现按 statsmodels coint_johansen 的算法计算(linspace 去趋势、长期形式 x_{t-k}、
+// Cholesky 变换后 Jacobi 对称特征值分解)
+func TestJohansenReference(t *testing.T) {
+	endog := make([][]float64, len(cointX1))
+	for i := range endog {
+		endog[i] = []float64{cointX1[i], cointX2[i], cointX3[i]}
+	}
+	tests := []struct {
+		detOrder int
+		kArDiff  int
+		eig      []float64
+		trace    []float64
+		maxEig   []float64
+	}{
+		{-1, 1, []float64{0.24668541410623432, 0.03513163648911916, 0.015652167444124877},
+			[]float64{39.50780603079399, 6.081667353108183, 1.8615628106415816},
+			[]float64{33.4261386776858, 4.2201045424666015, 1.8615628106415816}},
+		{0, 0, []float64{0.3053750817696967, 0.04099616804454022, 0.006136585562136011},
+			[]float64{49.07547686039553, 5.713868313517513, 0.7325035229451096},
+			[]float64{43.361608546878024, 4.981364790572403, 0.7325035229451096}},
+		{0, 2, []float64{0.2294303796833257, 0.043375688986338465, 0.015778433622778257},
+			[]float64{37.54226306076499, 7.049106355888727, 1.8607958687090365},
+			[]float64{30.493156704876263, 5.18831048717969, 1.8607958687090365}},
+		{1, 1, []float64{0.269302171363502, 0.05115131747951963, 0.03405316186744367},
+			[]float64{47.30710784102052, 10.283985767389634, 4.0882845542212785},
+			[]float64{37.02312207363089, 6.1957012131683555, 4.0882845542212785}},
+	}
+	for _, tt := range tests {
+		res, err := JohansenTest(endog, tt.detOrder, tt.kArDiff)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.NObs != len(endog)-1-tt.kArDiff {
+			t.Errorf("det=%d k=%d: nobs %d", tt.detOrder, tt.kArDiff, res.NObs)
+		}
+		for i := range tt.eig {
+			if math.Abs(res.Eig[i]-tt.eig[i]) > 1e-9 || math.Abs(res.TraceStat[i]-tt.trace[i]) > 1e-7 ||
+				math.Abs(res.MaxEigStat[i]-tt.maxEig[i]) > 1e-7 {
+				t.Errorf("det=%d k=%d r=%d: eig=%v trace=%v maxEig=%v", tt.detOrder, tt.kArDiff, i, res.Eig[i], res.TraceStat[i], res.MaxEigStat[i])
+			}
+		}
+		// 一个协整关系: 5% 水平下两种检验都判定秩为 1
+		if traceRank, maxEigRank := res.Rank("5%"); traceRank != 1 || maxEigRank != 1 {
+			t.Errorf("det=%d k=%d: rank %d/%d", tt.detOrder, tt.kArDiff, traceRank, maxEigRank)
+		}
+	}
+}
+
+// 对照值: 协整回归 OLS 残差上做无常数项、固定滞后的 ADF 回归(同 statsmodels coint(..., maxlag, autolag=None))，
+// p值按 MacKinnon(1994) 响应面 Φ(Σ a_i τ^i) 计算
+func TestEngleGrangerReference(t *testing.T) {
+	tests := []struct {
+		name   string
+		y, x   []float64
+		trend  string
+		lag    int
+		coeffs []float64
+		tStat  float64
+		pValue float64
+	}{
+		{"coint", cointX2, cointX1, "c", 1, []float64{-0.28040056349466436, 0.4813798788157068}, -6.055815633792424, 1.229165133343451e-06},
+		{"coint-ct", cointX2, cointX1, "ct", 0, []float64{-0.020529275651535926, -0.0037159677828760515, 0.46404678815816847}, -6.932645577789384, 6.706996485972283e-08},
+		{"independent", cointX3, cointX1, "c", 1, []float64{0.600925076440756, -0.0053471113684161855}, -2.165181762824847, 0.44238438110763045},
+	}
+	for _, tt := range tests {
+		X := make([][]float64, len(tt.x))
+		for i, v := range tt.x {
+			X[i] = []float64{v}
+		}
+		res, err := EngleGrangerTest(tt.y, X, tt.trend, tt.lag, adfuller.LAG_MODE_FIXED)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.UsedLag != tt.lag || math.Abs(res.TStat-tt.tStat) > 1e-9 {
+			t.Errorf("%s: lag=%d t=%v", tt.name, res.UsedLag, res.TStat)
+		}
+		for j, c := range tt.coeffs {
+			if math.Abs(res.Coeffs[j]-c) > 1e-9 {
+				t.Errorf("%s: coef %d = %v, want %v", tt.name, j, res.Coeffs[j], c)
+			}
+		}
+		if !math.IsNaN(tt.pValue) && math.Abs(res.PValue-tt.pValue) > 1e-12 {
+			t.Errorf("%s: p=%v, want %v", tt.name, res.PValue, tt.pValue)
+		}
+	}
+}
+
+func TestMackinnon(t *testing.T) {
+	// N=2 "c" 有限样本临界值, T = 119
+	crit := mackinnonCrit("c", 2, 119)
+	want := map[string]float64{"1%": -3.9900635505967097, "5%": -3.3879571944071745, "10%": -3.080282412965186}
+	for k, v := range want {
+		if math.Abs(crit[k]-v) > 1e-12 {
+			t.Errorf("crit %s = %v, want %v", k, crit[k], v)
+		}
+	}
+	// 渐近 5% 临界值处的 p值应接近 0.05
+	for _, tt := range []struct {
+		nVars int
+		tau   float64
+	}{{1, -2.86154}, {2, -3.33613}} {
+		if p := mackinnonPValue(tt.tau, "c", tt.nVars); math.Abs(p-0.05) > 1e-3 {
+			t.Errorf("N=%d: p(%v) = %v", tt.nVars, tt.tau, p)
+		}
+	}
+	// "ct" 响应面在渐近 1%/5%/10% 临界值处复现水平
+	for n, coef := range mackinnonCritCoef["ct"] {
+		for j, level := range []float64{0.01, 0.05, 0.10} {
+			if p := mackinnonPValue(coef[j][0], "ct", n+1); math.Abs(p-level) > 0.1*level {
+				t.Errorf("ct N=%d: p(%v) = %v, want %v", n+1, coef[j][0], p, level)
+			}
+		}
+	}
+	// 接受域内不饱和: 对照 Monte Carlo(T=1000, 20 万次) 经验分布，且 p值随统计量单调
+	for _, tt := range []struct {
+		nVars int
+		tau   float64
+		p     float64
+	}{{1, -2.5, 0.3315}, {1, -1.5, 0.8325}, {2, -3, 0.2673}, {2, -2, 0.7722}, {4, -3.5, 0.3406}, {6, -4, 0.3656}} {
+		if p := mackinnonPValue(tt.tau, "ct", tt.nVars); math.Abs(p-tt.p) > 0.01 {
+			t.Errorf("ct N=%d: p(%v) = %v, want %v", tt.nVars, tt.tau, p, tt.p)
+		}
+	}
+	for _, trend := range []string{"c", "ct"} {
+		for n := 1; n <= len(mackinnonPCoef[trend].tauStar); n++ {
+			prev := 0.0
+			for tau := -20.0; tau <= 3; tau += 0.01 {
+				p := mackinnonPValue(tau, trend, n)
+				if p < prev {
+					t.Errorf("%s N=%d: p not monotone at %v", trend, n, tau)
+					break
+				}
+				prev = p
+			}
+		}
+	}
+}
@@ -0,0 +1,76 @@
+// Engle ARCH-LM 检验
+// 辅助回归: e²_t = a0 + a1·e²_{t-1} + ... + aq·e²_{t-q} + u_t
+// LM = n·R² ~ χ²(q)；F = (R²/q) / ((1-R²)/(n-q-1)) ~ F(q, n-q-1)，n 为辅助回归样本量
+// 与 statsmodels.het_arch 一致
+package diagnostics
+
+import (
+	"math"
+	"method/ml/ols"
+	"ofeisInfra/infra/errorx"
+	"ofeisInfra/infra/errorx/errCode"
+
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/mathext"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+type ArchResult struct {
+	Lags     int     // 滞后阶数
+	NObs     int     // 辅助回归样本量
+	LM       float64 // LM 统计量
+	LMPValue float64 // LM p值
+	F        float64 // F 统计量
+	FPValue  float64 // F p值
+}
+
+// ARCH-LM 检验，辅助回归复用 ols.MultiRegressionMat
+// input: resid 残差序列(保持时间顺序，含 NaN 的回归行被跳过); lags 滞后阶数 q
+func ArchLMTest(resid []float64, lags int) (ArchResult, error) {
+	if lags <= 0 {
+		return ArchResult{}, errorx.New(errCode.INVALID_VALUE, "lags must be > 0")
+	}
+	e2 := make([]float64, len(resid))
+	for i, v := range resid {
+		e2[i] = v * v
+	}
+
+	rows := make([]float64, 0, (len(resid)-lags)*(lags+1))
+	y := make([]float64, 0, len(resid)-lags)
+	for t := lags; t < len(e2); t++ {
+		ok := !math.IsNaN(e2[t])
+		for j := 1; j <= lags && ok; j++ {
+			ok = !math.IsNaN(e2[t-j])
+		}
+		if !ok {
+			continue
+		}
+		rows = append(rows, 1)
+		for j := 1; j <= lags; j++ {
+			rows = append(rows, e2[t-j])
+		}
+		y = append(y, e2[t])
+	}
+	n := len(y)
+	if n <= lags+1 {
+		return ArchResult{}, errorx.New(errCode.INVALID_VALUE, "样本量过小, 无法进行ARCH-LM检验")
+	}
+
+	model, err := ols.MultiRegressionMat(mat.NewDense(n, lags+1, rows), mat.NewVecDense(n, y))
+	if err != nil {
+		return ArchResult{}, err
+	}
+	r2 := model.RSquared
+	lm := float64(n) * r2
+	df2 := float64(n - lags - 1)
+	f := (r2 / float64(lags)) / ((1 - r2) / df2)
+	chi2 := distuv.ChiSquared{K: float64(lags)}
+	return ArchResult{
+		Lags:     lags,
+		NObs:     n,
+		LM:       lm,
+		LMPValue: chi2.Survival(lm),
+		F:        f,
+		FPValue:  mathext.RegIncBeta(df2/2, float64(lags)/2, df2/(df2+float64(lags)*f)), // F 分布尾概率，避免 1-CDF 精度损失
+	}, nil
+}
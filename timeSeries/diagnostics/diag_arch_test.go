@@ -0,0 +1,85 @@
+package diagnostics
+
+import (
+	"math"
+	"testing"
+)
+
+// ARCH(1) 残差: σ²_t = 0.2 + 0.6ε²_{t-1}，n=300(保留 4 位小数)
+var archSample = []float64{
+	-0.4429, -0.3274, 0.4179, -0.2956, 0.0577, -0.1078, 0.2821, -0.0683,
+	0.9295, 0.0411, -0.12, -0.6736, 0.067, -0.4361, 0.3691, 0.3059,
+	1.3692, -0.9331, 0.115, 0.4889, -1.2086, 0.6862, 1.3052, 0.0722,
+	-0.0123, 0.5645, 0.1805, 0.1424, 0.4042, -0.4327, 1.1006, -0.3393,
+	0.0031, -0.1761, -1.234, -1.7505, -0.5271, -0.0042, 0.5302, 1.6553,
+	1.0122, -0.0986, -0.2997, 0.7256, -0.4596, -0.0176, 0.62, -0.089,
+	-0.0632, 0.0711, -0.7508, -0.0798, 0.0845, 0.1736, -0.1702, -1.035,
+	-0.7436, 0.5765, -0.5012, -0.5424, 0.3405, 0.3005, 0.091, 0.2887,
+	0.4236, 0.2908, 0.1865, 0.8946, -0.9266, 1.9544, 0.468, -0.4228,
+	0.4092, 0.5885, -0.7798, 0.1512, 0.4069, 0.4708, 0.0365, -0.4856,
+	-0.0433, 1.0132, -0.8439, 2.4846, -0.0506, -0.0284, -0.2408, 0.0893,
+	0.1071, -0.4632, 1.214, 1.314, 0.4633, -0.7472, -0.2619, -0.0795,
+	0.3803, -0.0498, -0.515, -0.1043, -0.0858, 0.015, 0.4081, -0.3966,
+	-0.8445, 0.9766, 0.8932, -1.0109, 0.699, -0.3577, 0.3847, 0.304,
+	-0.7136, -0.7451, -0.1008, -0.6368, -0.7947, -0.6673, -0.1068, -0.0361,
+	-0.3444, -0.1208, 0.5652, 0.378, 0.2355, -0.3312, -0.348, -0.6416,
+	-0.5664, -1.154, -0.1362, -0.1763, -0.3209, -0.3178, -0.6627, 0.5103,
+	0.3868, 0.6707, 0.2531, -0.0737, 0.421, -0.6979, -0.9852, 0.8534,
+	0.4152, 0.156, 0.646, -0.0141, 0.1165, 0.2087, -0.5649, 0.2579,
+	0.3137, -0.0209, -0.3174, -0.4141, -1.2285, -0.8817, 1.1414, 1.2734,
+	-0.099, -0.3557, 0.6346, 0.7541, 0.4094, -0.4021, 0.4485, 0.5498,
+	-0.2784, -0.4109, -0.2161, 0.1179, 0.2045, 0.2608, 0.0123, -0.2276,
+	0.1724, 0.4939, 0.108, 0.3263, -0.0311, -0.3258, -0.1998, -0.075,
+	-0.6937, -0.12, -0.2805, 0.1045, 0.358, 0.0796, -0.1748, 0.117,
+	-0.4866, 0.311, 0.6905, 0.4036, 0.8171, -0.3313, -0.9266, 0.4983,
+	1.3593, -0.9486, 1.3979, -0.5984, 1.0223, -0.538, -0.149, 0.2212,
+	-0.7437, 0.0924, -0.0511, 0.4211, 0.1391, 0.0657, -0.0496, 0.8706,
+	0.4283, 0.1175, -0.9673, 1.3523, 0.0686, 0.1318, 0.4016, 0.1513,
+	0.6885, 0.0362, 0.2689, 0.1219, 0.3216, 0.188, 0.2394, 0.1529,
+	0.1165, 0.3121, -0.3242, -0.3686, 0.022, 0.0673, 0.5852, -0.9349,
+	1.2679, -0.5434, -0.4993, 0.2166, 0.1745, 0.4735, -1.1868, 1.1841,
+	0.4864, 0.8622, -0.1421, -0.6701, -0.1522, -0.0888, 0.33, 0.4964,
+	-1.2029, -1.6029, 0.2053, -0.3235, 0.5276, 0.8376, 1.7331, 0.2104,
+	0.5504, 0.4328, 0.1325, -0.1586, 0.5257, 0.5106, -0.0162, -0.0632,
+	-0.7839, 0.264, 0.0797, -0.4453, 0.7823, 0.0533, -0.4579, -0.1356,
+	-0.5009, -0.4455, -0.7083, 0.1048, -0.7235, -0.4648, 0.0308, -0.0908,
+	0.0724, -0.3286, -0.3179, 0.1683, -0.1682, 0.4383, -0.255, 0.0661,
+	-0.1361, 0.4882, -0.543, 0.4951,
+}
+
+// 对照值: 辅助回归 OLS 的 n·R² 与 F 统计量(同 statsmodels het_arch)，p值由 χ² 与 F 分布尾概率独立计算
+func TestArchLMReference(t *testing.T) {
+	tests := []struct {
+		name    string
+		x       []float64
+		lags    int
+		nObs    int
+		lm, lmP float64
+		f, fP   float64
+	}{
+		{"arch-1", archSample, 1, 299, 9.595095145089413, 0.001950978148693732, 9.846907257913399, 0.0018720524759660605},
+		{"arch-2", archSample, 2, 298, 9.577695805237273, 0.008322039661105004, 4.898061317471959, 0.008078956258471742},
+		{"arch-4", archSample, 4, 296, 12.696772290017766, 0.012856504254936132, 3.260429440092278, 0.012287403081263876},
+		{"normal-2", normalSample, 2, 48, 1.6127296397812376, 0.44647814827775106, 0.7822494536388306, 0.4634955302453243},
+	}
+	near := func(a, b float64) bool { return math.Abs(a-b) <= 1e-9*math.Max(1, math.Abs(b)) }
+	for _, tt := range tests {
+		res, err := ArchLMTest(tt.x, tt.lags)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.NObs != tt.nObs || !near(res.LM, tt.lm) || !near(res.LMPValue, tt.lmP) || !near(res.F, tt.f) || !near(res.FPValue, tt.fP) {
+			t.Errorf("%s: %+v", tt.name, res)
+		}
+	}
+	// 含 NaN 的回归行被跳过
+	withNaN := append([]float64(nil), archSample...)
+	withNaN[100] = math.NaN()
+	res, err := ArchLMTest(withNaN, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.NObs != 298-3 {
+		t.Errorf("nobs with NaN = %d", res.NObs)
+	}
+}
@@ -0,0 +1,100 @@
+// 残差诊断: 对任意残差向量(AdfTest、MultiRegression、ARIMA、GARCH 标准化残差等)
+// 给出 ARCH 效应与正态性检验的汇总报告
+package diagnostics
+
+import (
+	"math"
+	"ofeisInfra/infra/errorx"
+	"ofeisInfra/infra/errorx/errCode"
+)
+
+// 通用检验统计量
+type TestStat struct {
+	Stat   float64 // 统计量
+	PValue float64 // p值
+}
+
+type ResidReport struct {
+	NObs            int        // 非 NaN 样本量
+	Mean            float64    // 均值
+	Std             float64    // 标准差(ddof=1)
+	Skewness        float64    // 偏度(有偏估计 g1)
+	Kurtosis        float64    // 超额峰度(有偏估计 g2)
+	SkewTest        TestStat   // D'Agostino 偏度检验, H0: 偏度为0
+	KurtTest        TestStat   // Anscombe-Glynn 峰度检验, H0: 峰度与正态一致
+	JarqueBera      TestStat   // Jarque-Bera 正态性检验
+	AndersonDarling ADResult   // Anderson-Darling 正态性检验
+	ArchLM          ArchResult // Engle ARCH-LM 检验, H0: 无 ARCH 效应
+}
+
+// 残差诊断报告
+// input: resid 残差序列(NaN 会被剔除; ARCH-LM 中含 NaN 的回归行被跳过); archLags ARCH-LM 滞后阶数
+func Diagnose(resid []float64, archLags int) (ResidReport, error) {
+	x := dropNaN(resid)
+	if len(x) < 8 {
+		return ResidReport{}, errorx.New(errCode.INVALID_VALUE, "样本量过小(<8), 无法进行残差诊断")
+	}
+	report := ResidReport{NObs: len(x)}
+	m := moments(x)
+	report.Mean = m.mean
+	report.Std = math.Sqrt(m.m2 * float64(len(x)) / float64(len(x)-1))
+	report.Skewness = m.skew
+	report.Kurtosis = m.kurt
+
+	var err error
+	if report.SkewTest, err = SkewTest(x); err != nil {
+		return ResidReport{}, err
+	}
+	if report.KurtTest, err = KurtosisTest(x); err != nil {
+		return ResidReport{}, err
+	}
+	if report.JarqueBera, err = JarqueBeraTest(x); err != nil {
+		return ResidReport{}, err
+	}
+	if report.AndersonDarling, err = AndersonDarlingTest(x); err != nil {
+		return ResidReport{}, err
+	}
+	if report.ArchLM, err = ArchLMTest(resid, archLags); err != nil {
+		return ResidReport{}, err
+	}
+	return report, nil
+}
+
+// 样本矩
+type sampleMoments struct {
+	mean float64
+	m2   float64 // 二阶中心矩(除以 n)
+	skew float64 // m3 / m2^1.5
+	kurt float64 // m4 / m2^2 - 3
+}
+
+func moments(x []float64) sampleMoments {
+	n := float64(len(x))
+	mean := 0.0
+	for _, v := range x {
+		mean += v
+	}
+	mean /= n
+	var m2, m3, m4 float64
+	for _, v := range x {
+		d := v - mean
+		d2 := d * d
+		m2 += d2
+		m3 += d2 * d
+		m4 += d2 * d2
+	}
+	m2 /= n
+	m3 /= n
+	m4 /= n
+	return sampleMoments{mean: mean, m2: m2, skew: m3 / math.Pow(m2, 1.5), kurt: m4/(m2*m2) - 3}
+}
+
+func dropNaN(x []float64) []float64 {
+	out := make([]float64, 0, len(x))
+	for _, v := range x {
+		if !math.IsNaN(v) {
+			out = append(out, v)
+		}
+	}
+	return out
+}
@@ -0,0 +1,142 @@
+// 正态性检验，统计量定义与 scipy.stats 一致
+package diagnostics
+
+import (
+	"math"
+	"ofeisInfra/infra/errorx"
+	"ofeisInfra/infra/errorx/errCode"
+	"sort"
+
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+type ADResult struct {
+	Stat      float64            // A² 统计量
+	AdjStat   float64            // 小样本修正 A*² = A²(1 + 0.75/n + 2.25/n²)
+	PValue    float64            // D'Agostino-Stephens(1986) 近似 p值
+	Criticals map[string]float64 // 临界值（15%, 10%, 5%, 2.5%, 1%），与 Stat 比较
+}
+
+// Jarque-Bera 检验: JB = n/6·(S² + K²/4) ~ χ²(2)，K 为超额峰度
+func JarqueBeraTest(x []float64) (TestStat, error) {
+	x = dropNaN(x)
+	n := len(x)
+	if n < 3 {
+		return TestStat{}, errorx.New(errCode.INVALID_VALUE, "样本量过小, 无法进行Jarque-Bera检验")
+	}
+	m := moments(x)
+	jb := float64(n) / 6 * (m.skew*m.skew + m.kurt*m.kurt/4)
+	chi2 := distuv.ChiSquared{K: 2}
+	return TestStat{Stat: jb, PValue: chi2.Survival(jb)}, nil
+}
+
+// D'Agostino 偏度检验(与 scipy.stats.skewtest 一致)，n >= 8
+func SkewTest(x []float64) (TestStat, error) {
+	x = dropNaN(x)
+	n := float64(len(x))
+	if n < 8 {
+		return TestStat{}, errorx.New(errCode.INVALID_VALUE, "样本量过小(<8), 无法进行偏度检验")
+	}
+	b2 := moments(x).skew
+	y := b2 * math.Sqrt((n+1)*(n+3)/(6*(n-2)))
+	beta2 := 3 * (n*n + 27*n - 70) * (n + 1) * (n + 3) / ((n - 2) * (n + 5) * (n + 7) * (n + 9))
+	W2 := -1 + math.Sqrt(2*(beta2-1))
+	delta := 1 / math.Sqrt(0.5*math.Log(W2))
+	alpha := math.Sqrt(2 / (W2 - 1))
+	if y == 0 {
+		y = 1
+	}
+	Z := delta * math.Log(y/alpha+math.Sqrt((y/alpha)*(y/alpha)+1))
+	return TestStat{Stat: Z, PValue: 2 * distuv.UnitNormal.Survival(math.Abs(Z))}, nil
+}
+
+// Anscombe-Glynn 峰度检验(与 scipy.stats.kurtosistest 一致)，n >= 5
+func KurtosisTest(x []float64) (TestStat, error) {
+	x = dropNaN(x)
+	n := float64(len(x))
+	if n < 5 {
+		return TestStat{}, errorx.New(errCode.INVALID_VALUE, "样本量过小(<5), 无法进行峰度检验")
+	}
+	b2 := moments(x).kurt + 3
+	E := 3 * (n - 1) / (n + 1)
+	varb2 := 24 * n * (n - 2) * (n - 3) / ((n + 1) * (n + 1) * (n + 3) * (n + 5))
+	xs := (b2 - E) / math.Sqrt(varb2)
+	sqrtbeta1 := 6 * (n*n - 5*n + 2) / ((n + 7) * (n + 9)) * math.Sqrt(6*(n+3)*(n+5)/(n*(n-2)*(n-3)))
+	A := 6 + 8/sqrtbeta1*(2/sqrtbeta1+math.Sqrt(1+4/(sqrtbeta1*sqrtbeta1)))
+	term1 := 1 - 2/(9*A)
+	denom := 1 + xs*math.Sqrt(2/(A-4))
+	if denom == 0 {
+		return TestStat{Stat: math.NaN(), PValue: math.NaN()}, nil
+	}
+	term2 := math.Copysign(math.Cbrt((1-2/A)/math.Abs(denom)), denom)
+	Z := (term1 - term2) / math.Sqrt(2/(9*A))
+	return TestStat{Stat: Z, PValue: 2 * distuv.UnitNormal.Survival(math.Abs(Z))}, nil
+}
+
+// Anderson-Darling 正态性检验(均值、方差由样本估计，与 scipy.stats.anderson(dist="norm") 一致)
+// A² = -n - (1/n)Σ(2i-1)[lnΦ(z_(i)) + ln(1-Φ(z_(n+1-i)))]
+func AndersonDarlingTest(x []float64) (ADResult, error) {
+	x = dropNaN(x)
+	n := len(x)
+	if n < 8 {
+		return ADResult{}, errorx.New(errCode.INVALID_VALUE, "样本量过小(<8), 无法进行Anderson-Darling检验")
+	}
+	m := moments(x)
+	std := math.Sqrt(m.m2 * float64(n) / float64(n-1))
+	if std == 0 {
+		return ADResult{}, errorx.New(errCode.INVALID_VALUE, "样本方差为0")
+	}
+	z := make([]float64, n)
+	for i, v := range x {
+		z[i] = (v - m.mean) / std
+	}
+	sort.Float64s(z)
+
+	nf := float64(n)
+	s := 0.0
+	for i := 0; i < n; i++ {
+		logCdf := logNormCDF(z[i])
+		logSf := logNormCDF(-z[n-1-i])
+		s += float64(2*i+1) * (logCdf + logSf)
+	}
+	A2 := -nf - s/nf
+	adj := A2 * (1 + 0.75/nf + 2.25/(nf*nf))
+
+	scale := 1 / (1 + 4/nf - 25/(nf*nf))
+	return ADResult{
+		Stat:    A2,
+		AdjStat: adj,
+		PValue:  adPValue(adj),
+		Criticals: map[string]float64{
+			"15%":  0.576 * scale,
+			"10%":  0.656 * scale,
+			"5%":   0.787 * scale,
+			"2.5%": 0.918 * scale,
+			"1%":   1.092 * scale,
+		},
+	}, nil
+}
+
+// D'Agostino-Stephens(1986) 分段近似 p值，adj 为小样本修正统计量 A*²
+func adPValue(adj float64) float64 {
+	var p float64
+	switch {
+	case adj >= 0.6:
+		p = math.Exp(1.2937 - 5.709*adj + 0.0186*adj*adj)
+	case adj >= 0.34:
+		p = math.Exp(0.9177 - 4.279*adj - 1.38*adj*adj)
+	case adj >= 0.2:
+		p = 1 - math.Exp(-8.318+42.796*adj-59.938*adj*adj)
+	default:
+		p = 1 - math.Exp(-13.436+101.14*adj-223.73*adj*adj)
+	}
+	return math.Max(0, math.Min(1, p))
+}
+
+// lnΦ(z)，z < -37 时 Φ 下溢，改用 Mills 比渐近展开
+func logNormCDF(z float64) float64 {
+	if z > -37 {
+		return math.Log(distuv.UnitNormal.CDF(z))
+	}
+	return -0.5*z*z - math.Log(-z) - 0.5*math.Log(2*math.Pi) + math.Log(1-1/(z*z))
+}
@@ -0,0 +1,106 @@
+// 分数阶差分(López de Prado, AFML 第5章)
+// (1-B)^d x_t = Σ_k w_k x_{t-k},  w_0 = 1, w_k = -w_{k-1}(d - k + 1)/k
+// 输出与输入等长，无法计算的位置为 NaN，便于与原序列按下标对齐
+package fracdiff
+
+import (
+	"fmt"
+	"math"
+	"ofeisInfra/infra/errorx"
+	"ofeisInfra/infra/errorx/errCode"
+)
+
+// 分数阶差分权重 w_0..，|w_k| < threshold 或长度达到 maxLen 时截断(threshold <= 0 时仅按 maxLen)
+func Weights(d float64, threshold float64, maxLen int) []float64 {
+	w := []float64{1}
+	for k := 1; k < maxLen; k++ {
+		next := -w[k-1] * (d - float64(k) + 1) / float64(k)
+		if threshold > 0 && math.Abs(next) < threshold {
+			break
+		}
+		w = append(w, next)
+	}
+	return w
+}
+
+// 固定窗口分数阶差分(FFD)
+// 权重截断于 |w_k| < threshold(常用 1e-5)，窗口宽度 L 固定，前 L-1 个及窗口内含 NaN 的位置输出 NaN
+// 权重在 len(x) 阶内未衰减到 threshold 以下时返回错误，而不是按序列长度截断窗口
+func FFD(x []float64, d, threshold float64) ([]float64, error) {
+	if d < 0 {
+		return nil, errorx.New(errCode.INVALID_VALUE, "d 不能为负")
+	}
+	if threshold <= 0 {
+		return nil, errorx.New(errCode.INVALID_VALUE, "threshold 必须 > 0")
+	}
+	w := Weights(d, threshold, len(x)+1)
+	L := len(w)
+	if L > len(x) {
+		return nil, errorx.New(errCode.INVALID_VALUE, fmt.Sprintf("d=%g 的权重在 %d 阶内未衰减到 threshold=%g 以下, 样本量不足", d, len(x), threshold))
+	}
+	out := make([]float64, len(x))
+	for t := range out {
+		out[t] = math.NaN()
+		if t < L-1 {
+			continue
+		}
+		s := 0.0
+		for k, wk := range w {
+			s += wk * x[t-k]
+		}
+		out[t] = s // 窗口内的 NaN 自然传播
+	}
+	return out, nil
+}
+
+// 扩展窗口分数阶差分
+// t 时刻使用全部 t+1 个权重；截去累计权重损失 Σ_{k>t}|w_k| / Σ|w_k| 超过 threshold(常用 0.01) 的前段
+// 序列中的 NaN 视为缺失，该处及其后的输出为 NaN
+func Expanding(x []float64, d, threshold float64) ([]float64, error) {
+	if d < 0 {
+		return nil, errorx.New(errCode.INVALID_VALUE, "d 不能为负")
+	}
+	if threshold <= 0 || threshold >= 1 {
+		return nil, errorx.New(errCode.INVALID_VALUE, "threshold 必须在 (0,1) 之间")
+	}
+	n := len(x)
+	if n == 0 {
+		return nil, errorx.New(errCode.EMPTY_VALUE, "序列为空")
+	}
+	w := Weights(d, 0, n)
+	cum := make([]float64, len(w))
+	total := 0.0
+	for k, wk := range w {
+		total += math.Abs(wk)
+		cum[k] = total
+	}
+	skip := 0
+	for skip < n && 1-cum[skip]/total > threshold {
+		skip++
+	}
+	out := make([]float64, n)
+	for t := range out {
+		out[t] = math.NaN()
+		if t < skip {
+			continue
+		}
+		s := 0.0
+		for k := 0; k <= t; k++ {
+			s += w[k] * x[t-k]
+		}
+		out[t] = s
+	}
+	return out, nil
+}
+
+// 按方式分派
+func Apply(x []float64, d, threshold float64, method FracMethod) ([]float64, error) {
+	switch method {
+	case FRAC_FFD:
+		return FFD(x, d, threshold)
+	case FRAC_EXPANDING:
+		return Expanding(x, d, threshold)
+	default:
+		return nil, errorx.New(errCode.INVALID_VALUE, "未知的分数阶差分方式")
+	}
+}
@@ -0,0 +1,159 @@
+package fracdiff
+
+import (
+	"math"
+	"math/rand"
+	"method/timeSeries/adfuller"
+	"testing"
+)
+
+// d = 1 时两种方式都退化为一阶差分
+func TestFirstDifference(t *testing.T) {
+	x := []float64{1, 3, 2, 5, 4, 8}
+	for _, method := range []FracMethod{FRAC_FFD, FRAC_EXPANDING} {
+		threshold := 1e-5
+		if method == FRAC_EXPANDING {
+			threshold = 0.01
+		}
+		out, err := Apply(x, 1, threshold, method)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(out) != len(x) || !math.IsNaN(out[0]) {
+			t.Fatalf("%s: %v", method, out)
+		}
+		for i := 1; i < len(x); i++ {
+			if math.Abs(out[i]-(x[i]-x[i-1])) > 1e-12 {
+				t.Errorf("%s: out[%d] = %v, want %v", method, i, out[i], x[i]-x[i-1])
+			}
+		}
+	}
+}
+
+// 空序列返回错误而不是越界
+func TestEmptyInput(t *testing.T) {
+	if _, err := Expanding(nil, 0.4, 0.01); err == nil {
+		t.Error("Expanding accepted empty input")
+	}
+	if _, err := Expanding([]float64{2}, 0.4, 0.01); err != nil {
+		t.Errorf("single observation: %v", err)
+	}
+	opts := adfuller.AdfOptions{Trend: adfuller.TREND_C, Tail: adfuller.TAIL_LEFT, MaxLag: 1, AutoLag: adfuller.LAG_MODE_AIC}
+	if _, err := SearchMinD(nil, []float64{0.2, 0.4}, FRAC_EXPANDING, 0.01, opts, "5%"); err == nil {
+		t.Error("SearchMinD accepted empty input")
+	}
+}
+
+// d = 0.5 的权重闭式解 w_k = -Γ(k-0.5) / (Γ(-0.5)Γ(k+1)): 1, -1/2, -1/8, -1/16, -5/128, -7/256
+func TestWeightsHalf(t *testing.T) {
+	want := []float64{1, -0.5, -0.125, -0.0625, -0.0390625, -0.02734375}
+	w := Weights(0.5, 0, len(want))
+	if len(w) != len(want) {
+		t.Fatalf("len %d", len(w))
+	}
+	for k := range want {
+		if math.Abs(w[k]-want[k]) > 1e-15 {
+			t.Errorf("w[%d] = %v, want %v", k, w[k], want[k])
+		}
+	}
+	// |w_4| = 0.039 < 0.05 处截断
+	if w := Weights(0.5, 0.05, 100); len(w) != 4 {
+		t.Errorf("threshold 0.05: %v", w)
+	}
+}
+
+// n=6、d=0.5 时 Σ|w| = 1.75390625，累计权重损失依次为 0.430、0.145、0.073、0.038...
+func TestExpandingWarmUp(t *testing.T) {
+	x := []float64{1, 3, 2, 5, 4, 8}
+	w := []float64{1, -0.5, -0.125, -0.0625, -0.0390625, -0.02734375}
+	for _, tt := range []struct {
+		threshold float64
+		skip      int
+	}{{0.5, 0}, {0.1, 2}, {0.05, 3}, {0.01, 5}} {
+		out, err := Expanding(x, 0.5, tt.threshold)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i, v := range out {
+			if i < tt.skip {
+				if !math.IsNaN(v) {
+					t.Errorf("threshold %v: out[%d] = %v, want NaN", tt.threshold, i, v)
+				}
+				continue
+			}
+			want := 0.0
+			for k := 0; k <= i; k++ {
+				want += w[k] * x[i-k]
+			}
+			if math.Abs(v-want) > 1e-12 {
+				t.Errorf("threshold %v: out[%d] = %v, want %v", tt.threshold, i, v, want)
+			}
+		}
+	}
+}
+
+func TestFFDWindow(t *testing.T) {
+	x := []float64{1, 3, 2, 5, 4, 8, 6, 7}
+	// 权重 1, -0.5, -0.125, -0.0625，窗口 L=4
+	out, err := FFD(x, 0.5, 0.05)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, v := range out {
+		if i < 3 {
+			if !math.IsNaN(v) {
+				t.Errorf("out[%d] = %v, want NaN", i, v)
+			}
+			continue
+		}
+		if want := x[i] - 0.5*x[i-1] - 0.125*x[i-2] - 0.0625*x[i-3]; math.Abs(v-want) > 1e-12 {
+			t.Errorf("out[%d] = %v, want %v", i, v, want)
+		}
+	}
+	// 1e-5 需要数千阶权重，8 个样本内达不到
+	if _, err := FFD(x, 0.5, 1e-5); err == nil {
+		t.Error("FFD truncated weights silently")
+	}
+}
+
+// 随机游走: d=0 不拒绝单位根，d=1 拒绝，最小 d 落在 (0,1) 内，且记忆保留(相关系数)随 d 单调下降
+func TestSearchMinDRandomWalk(t *testing.T) {
+	rng := rand.New(rand.NewSource(40))
+	x := make([]float64, 1000)
+	for i := 1; i < len(x); i++ {
+		x[i] = x[i-1] + rng.NormFloat64()
+	}
+	grid := make([]float64, 11)
+	for i := range grid {
+		grid[i] = float64(i) / 10
+	}
+	opts := adfuller.AdfOptions{Trend: adfuller.TREND_C, Tail: adfuller.TAIL_LEFT, MaxLag: 1, AutoLag: adfuller.LAG_MODE_FIXED}
+	res, err := SearchMinD(x, grid, FRAC_FFD, 1e-3, opts, "5%")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res.Found || !(res.MinD > 0 && res.MinD < 1) {
+		t.Fatalf("MinD=%v found=%v", res.MinD, res.Found)
+	}
+	if res.Rows[0].Stationary || !res.Rows[len(res.Rows)-1].Stationary {
+		t.Errorf("d=0 stationary=%v, d=1 stationary=%v", res.Rows[0].Stationary, res.Rows[len(res.Rows)-1].Stationary)
+	}
+	for i, row := range res.Rows {
+		if row.Err != nil {
+			t.Fatalf("d=%v: %v", row.D, row.Err)
+		}
+		if i > 0 && row.Corr >= res.Rows[i-1].Corr {
+			t.Errorf("corr not decreasing at d=%v: %v >= %v", row.D, row.Corr, res.Rows[i-1].Corr)
+		}
+	}
+	if math.Abs(res.Rows[0].Corr-1) > 1e-12 {
+		t.Errorf("d=0 corr %v", res.Rows[0].Corr)
+	}
+
+	// 序列中段的缺失值直接报错，不拼接前后两段
+	gap := append([]float64(nil), x...)
+	gap[500] = math.NaN()
+	if _, err := SearchMinD(gap, grid, FRAC_FFD, 1e-3, opts, "5%"); err == nil {
+		t.Error("interior NaN accepted")
+	}
+}
@@ -0,0 +1,32 @@
+package fracdiff
+
+// 分数阶差分方式
+type FracMethod int
+
+const (
+	FRAC_FFD       FracMethod = iota // "ffd" 固定窗口宽度
+	FRAC_EXPANDING                   // "expanding" 扩展窗口
+	FRAC_ERROR                       // "ERROR"
+)
+
+func (s FracMethod) String() string {
+	switch s {
+	case FRAC_FFD:
+		return "ffd"
+	case FRAC_EXPANDING:
+		return "expanding"
+	default:
+		return "ERROR"
+	}
+}
+
+func GetMyFracMethod(s string) FracMethod {
+	switch s {
+	case "ffd":
+		return FRAC_FFD
+	case "expanding":
+		return FRAC_EXPANDING
+	default:
+		return FRAC_ERROR
+	}
+}
@@ -0,0 +1,98 @@
+// 最小平稳阶数搜索: 在 d 网格上依次做分数阶差分，用 ADF 检验平稳性，
+// 返回拒绝单位根的最小 d，并记录每个 d 与原序列的相关系数(记忆保留程度)
+package fracdiff
+
+import (
+	"fmt"
+	"math"
+	"method/timeSeries/adfuller"
+	"ofeisInfra/infra/errorx"
+	"ofeisInfra/infra/errorx/errCode"
+	"sort"
+
+	"gonum.org/v1/gonum/stat"
+)
+
+type DSearchRow struct {
+	D          float64 // 差分阶数
+	NObs       int     // 差分后去掉预热期的样本量
+	ADFStat    float64 // ADF 统计量
+	PValue     float64 // ADF p值
+	Critical   float64 // 所选显著性水平的临界值
+	Corr       float64 // 与原序列(同下标对齐)的 Pearson 相关系数
+	Stationary bool    // 是否拒绝单位根
+	Err        error   // 差分或 ADF 检验失败时的错误
+}
+
+type MinDResult struct {
+	MinD  float64      // 拒绝单位根的最小 d，未找到时为 NaN
+	Found bool         // 是否找到
+	Level string       // 显著性水平 ("1%"、"5%"、"10%")
+	Rows  []DSearchRow // 每个 d 的检验结果，按 d 升序
+}
+
+// 最小 d 搜索
+// input: x 原序列(通常为对数价格，不可含 NaN); dGrid 待搜索的 d; method 差分方式; threshold 见 FFD / Expanding;
+// opts ADF 参数(左尾); level 显著性水平，对应 ADFResult.Criticals 的键
+func SearchMinD(x []float64, dGrid []float64, method FracMethod, threshold float64, opts adfuller.AdfOptions, level string) (MinDResult, error) {
+	if len(x) == 0 {
+		return MinDResult{}, errorx.New(errCode.EMPTY_VALUE, "序列为空")
+	}
+	if len(dGrid) == 0 {
+		return MinDResult{}, errorx.New(errCode.EMPTY_VALUE, "dGrid 为空")
+	}
+	if opts.Tail != adfuller.TAIL_LEFT {
+		return MinDResult{}, errorx.New(errCode.INVALID_VALUE, "平稳性搜索须使用左尾 ADF")
+	}
+	if level != "1%" && level != "5%" && level != "10%" {
+		return MinDResult{}, errorx.New(errCode.INVALID_VALUE, fmt.Sprintf("未知的显著性水平 %q", level))
+	}
+	if method != FRAC_FFD && method != FRAC_EXPANDING {
+		return MinDResult{}, errorx.New(errCode.INVALID_VALUE, "未知的分数阶差分方式")
+	}
+	for _, v := range x {
+		if math.IsNaN(v) {
+			return MinDResult{}, errorx.New(errCode.INVALID_VALUE, "序列含 NaN, 请先处理缺失值")
+		}
+	}
+	grid := append([]float64(nil), dGrid...)
+	sort.Float64s(grid)
+
+	result := MinDResult{MinD: math.NaN(), Level: level}
+	for _, d := range grid {
+		row := DSearchRow{D: d, ADFStat: math.NaN(), PValue: math.NaN(), Critical: math.NaN(), Corr: math.NaN()}
+		fd, err := Apply(x, d, threshold, method)
+		if err != nil {
+			row.Err = err
+			result.Rows = append(result.Rows, row)
+			continue
+		}
+
+		// 仅去掉前段预热期的 NaN，相关系数在同下标对齐的样本上计算
+		start := 0
+		for start < len(fd) && math.IsNaN(fd[start]) {
+			start++
+		}
+		clean, orig := fd[start:], x[start:]
+		row.NObs = len(clean)
+		if len(clean) > 2 {
+			row.Corr = stat.Correlation(clean, orig, nil)
+		}
+
+		adf, err := adfuller.AdfTestWithOptions(clean, opts)
+		if err != nil {
+			row.Err = err
+		} else {
+			row.ADFStat = adf.TStat
+			row.PValue = adf.PValue
+			row.Critical = adf.Criticals[level]
+			row.Stationary = adf.TStat < row.Critical
+		}
+		if row.Stationary && !result.Found {
+			result.Found = true
+			result.MinD = d
+		}
+		result.Rows = append(result.Rows, row)
+	}
+	return result, nil
+}
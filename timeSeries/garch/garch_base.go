@@ -0,0 +1,420 @@
+// GARCH 族波动率模型，用于 ADFResult.Resid、MultiLinearModel.Resids 等残差序列
+// ε_t = r_t - μ = σ_t z_t，z_t 为零均值单位方差新息(正态或标准化 Student-t)
+//
+//	GARCH(p,q):     σ²_t = ω + Σ_{i=1}^p α_i ε²_{t-i} + Σ_{j=1}^q β_j σ²_{t-j}
+//	GJR-GARCH(p,q): σ²_t = ω + Σ_{i=1}^p (α_i + γ_i·I(ε_{t-i}<0)) ε²_{t-i} + Σ_{j=1}^q β_j σ²_{t-j}
+//	EGARCH(p,q):    lnσ²_t = ω + Σ_{i=1}^p [α_i(|z_{t-i}| - √(2/π)) + γ_i z_{t-i}] + Σ_{j=1}^q β_j lnσ²_{t-j}
+//
+// 样本前的 ε² 与 σ² 用前 75 个 ε² 的指数加权均值回推(与 arch 包一致)
+// 极大似然在原参数空间以 Nelder-Mead 求解，不满足平稳/正性约束的点似然记为 -Inf
+package garch
+
+import (
+	"fmt"
+	"math"
+	"method/timeSeries/internal/mle"
+	"ofeisInfra/infra/errorx"
+	"ofeisInfra/infra/errorx/errCode"
+	"strategyCrypto/pkg/utils/myTools"
+
+	"gonum.org/v1/gonum/optimize"
+)
+
+type GarchModel struct {
+	Model       VolModel
+	P           int // ARCH 阶数(ε² / z 的滞后)
+	Q           int // GARCH 阶数(σ² 的滞后)
+	Dist        InnovDist
+	WithMean    bool
+	Mu          float64   // 均值
+	Omega       float64   // ω
+	Alpha       []float64 // α_1..α_p
+	Gamma       []float64 // γ_1..γ_p (GJR 为杠杆项，EGARCH 为符号项；GARCH 为空)
+	Beta        []float64 // β_1..β_q
+	Nu          float64   // Student-t 自由度(正态分布时为 +Inf)
+	ParamNames  []string  // 参数名
+	Params      []float64 // 参数向量 [(μ), ω, α..., (γ...), β..., (ν)]
+	SE          []float64 // 参数标准误
+	ZStats      []float64 // z统计量
+	PValues     []float64 // p值（双尾）
+	LogLik      float64
+	AIC         float64
+	BIC         float64
+	NObs        int
+	Persistence float64   // 持续性: GARCH Σα+Σβ, GJR Σα+0.5Σγ+Σβ, EGARCH Σβ
+	Sigma2      []float64 // 条件方差
+	StdResid    []float64 // 标准化残差 z_t = ε_t/σ_t，可直接送入 adfuller.LjungBoxTest
+
+	backcast float64   // 样本前方差
+	resid    []float64 // 去均值后的 ε_t
+}
+
+// 模型参数
+type garchParams struct {
+	mu    float64
+	omega float64
+	alpha []float64
+	gamma []float64
+	beta  []float64
+	nu    float64
+}
+
+// 拟合 GARCH 族模型
+// input: resid 残差序列; model 模型类型; p ARCH 阶数(>=1); q GARCH 阶数(>=0); dist 新息分布; withMean 是否估计均值
+func Fit(resid []float64, model VolModel, p, q int, dist InnovDist, withMean bool) (*GarchModel, error) {
+	if model != MODEL_GARCH && model != MODEL_GJR && model != MODEL_EGARCH {
+		return nil, errorx.New(errCode.INVALID_VALUE, "未知的波动率模型")
+	}
+	if dist != DIST_NORMAL && dist != DIST_STUDENTT {
+		return nil, errorx.New(errCode.INVALID_VALUE, "未知的新息分布")
+	}
+	if p < 1 || q < 0 {
+		return nil, errorx.New(errCode.INVALID_VALUE, "阶数非法: 需 p >= 1, q >= 0")
+	}
+	for _, v := range resid {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return nil, errorx.New(errCode.INVALID_VALUE, "残差序列含 NaN/Inf")
+		}
+	}
+
+	m := &GarchModel{Model: model, P: p, Q: q, Dist: dist, WithMean: withMean, Nu: math.Inf(1)}
+	names := m.paramNames()
+	k := len(names)
+	n := len(resid)
+	if n <= k+10 {
+		return nil, errorx.New(errCode.INVALID_VALUE, fmt.Sprintf("样本量过小, 无法拟合 %s(%d,%d)", model, p, q))
+	}
+
+	// 1. 初值
+	mu0 := 0.0
+	if withMean {
+		mu0 = myTools.ArrMean(resid)
+	}
+	var v0 float64
+	for _, v := range resid {
+		v0 += (v - mu0) * (v - mu0)
+	}
+	v0 /= float64(n)
+	if v0 <= 0 {
+		return nil, errorx.New(errCode.INVALID_VALUE, "残差方差为0")
+	}
+	x0 := m.startParams(mu0, v0)
+
+	// 2. 极大似然
+	negLogLik := func(x []float64) float64 {
+		par := m.unpack(x)
+		if !m.valid(par) {
+			return math.Inf(1)
+		}
+		ll, _, _ := m.logLik(resid, par)
+		if math.IsNaN(ll) {
+			return math.Inf(1)
+		}
+		return -ll
+	}
+	xHat := x0
+	fHat := negLogLik(x0)
+	for restart := 0; restart < 3; restart++ {
+		res, _ := optimize.Minimize(optimize.Problem{Func: negLogLik}, xHat, nil, &optimize.NelderMead{})
+		if res == nil || math.IsNaN(res.F) || res.F >= fHat-1e-8 {
+			break
+		}
+		xHat, fHat = res.X, res.F
+	}
+	if math.IsInf(fHat, 1) {
+		return nil, errorx.New(errCode.INVALID_VALUE, "极大似然估计失败")
+	}
+
+	// 3. 结果
+	par := m.unpack(xHat)
+	m.setParams(par)
+	ll, sigma2, eps := m.logLik(resid, par)
+	m.LogLik = ll
+	m.Sigma2 = sigma2
+	m.resid = eps
+	m.backcast = backcast(eps)
+	m.StdResid = make([]float64, n)
+	for t := range eps {
+		m.StdResid[t] = eps[t] / math.Sqrt(sigma2[t])
+	}
+	m.NObs = n
+	m.ParamNames = names
+	m.Params = xHat
+	m.AIC = -2*ll + 2*float64(k)
+	m.BIC = -2*ll + float64(k)*math.Log(float64(n))
+	m.SE, m.ZStats, m.PValues = mle.HessianInference(negLogLik, xHat)
+	return m, nil
+}
+
+// 用已拟合参数对新残差序列做条件方差滤波
+// output: sigma2 条件方差; stdResid 标准化残差
+func (m *GarchModel) Filter(resid []float64) (sigma2, stdResid []float64, err error) {
+	if len(resid) == 0 {
+		return nil, nil, errorx.New(errCode.EMPTY_VALUE, "残差序列为空")
+	}
+	par := garchParams{mu: m.Mu, omega: m.Omega, alpha: m.Alpha, gamma: m.Gamma, beta: m.Beta, nu: m.Nu}
+	eps := make([]float64, len(resid))
+	for t, v := range resid {
+		eps[t] = v - m.Mu
+	}
+	sigma2 = m.variance(eps, par, backcast(eps))
+	stdResid = make([]float64, len(eps))
+	for t := range eps {
+		stdResid[t] = eps[t] / math.Sqrt(sigma2[t])
+	}
+	return sigma2, stdResid, nil
+}
+
+// 无条件方差(不平稳时为 NaN)
+func (m *GarchModel) UnconditionalVariance() float64 {
+	if m.Persistence >= 1 {
+		return math.NaN()
+	}
+	if m.Model == MODEL_EGARCH {
+		return math.Exp(m.Omega / (1 - m.Persistence))
+	}
+	return m.Omega / (1 - m.Persistence)
+}
+
+func (m *GarchModel) paramNames() []string {
+	names := make([]string, 0, 2+2*m.P+m.Q+1)
+	if m.WithMean {
+		names = append(names, "mu")
+	}
+	names = append(names, "omega")
+	for i := 1; i <= m.P; i++ {
+		names = append(names, fmt.Sprintf("alpha[%d]", i))
+	}
+	if m.Model != MODEL_GARCH {
+		for i := 1; i <= m.P; i++ {
+			names = append(names, fmt.Sprintf("gamma[%d]", i))
+		}
+	}
+	for j := 1; j <= m.Q; j++ {
+		names = append(names, fmt.Sprintf("beta[%d]", j))
+	}
+	if m.Dist == DIST_STUDENTT {
+		names = append(names, "nu")
+	}
+	return names
+}
+
+func (m *GarchModel) startParams(mu0, v0 float64) []float64 {
+	x := make([]float64, 0, len(m.paramNames()))
+	if m.WithMean {
+		x = append(x, mu0)
+	}
+	alpha, gamma, beta := 0.1, 0.0, 0.8
+	if m.Model == MODEL_GJR {
+		alpha, gamma = 0.05, 0.05
+	}
+	if m.Q == 0 {
+		beta = 0
+	}
+	switch m.Model {
+	case MODEL_EGARCH:
+		x = append(x, math.Log(v0)*(1-0.9*float64(min(m.Q, 1))))
+		alpha, beta = 0.1, 0.9
+	default:
+		x = append(x, v0*(1-alpha-0.5*gamma-beta))
+	}
+	for i := 0; i < m.P; i++ {
+		x = append(x, alpha/float64(m.P))
+	}
+	if m.Model != MODEL_GARCH {
+		for i := 0; i < m.P; i++ {
+			x = append(x, gamma/float64(m.P))
+		}
+	}
+	for j := 0; j < m.Q; j++ {
+		x = append(x, beta/float64(m.Q))
+	}
+	if m.Dist == DIST_STUDENTT {
+		x = append(x, studentTNuStart)
+	}
+	return x
+}
+
+func (m *GarchModel) unpack(x []float64) garchParams {
+	par := garchParams{nu: math.Inf(1)}
+	pos := 0
+	if m.WithMean {
+		par.mu = x[pos]
+		pos++
+	}
+	par.omega = x[pos]
+	pos++
+	par.alpha = x[pos : pos+m.P]
+	pos += m.P
+	if m.Model != MODEL_GARCH {
+		par.gamma = x[pos : pos+m.P]
+		pos += m.P
+	}
+	par.beta = x[pos : pos+m.Q]
+	pos += m.Q
+	if m.Dist == DIST_STUDENTT {
+		par.nu = x[pos]
+	}
+	return par
+}
+
+func (m *GarchModel) setParams(par garchParams) {
+	m.Mu = par.mu
+	m.Omega = par.omega
+	m.Alpha = append([]float64(nil), par.alpha...)
+	m.Gamma = append([]float64(nil), par.gamma...)
+	m.Beta = append([]float64(nil), par.beta...)
+	m.Nu = par.nu
+	m.Persistence = m.persistence(par)
+}
+
+func (m *GarchModel) persistence(par garchParams) float64 {
+	s := 0.0
+	for _, b := range par.beta {
+		s += b
+	}
+	if m.Model == MODEL_EGARCH {
+		return s
+	}
+	for _, a := range par.alpha {
+		s += a
+	}
+	for _, g := range par.gamma {
+		s += 0.5 * g
+	}
+	return s
+}
+
+// 参数约束
+func (m *GarchModel) valid(par garchParams) bool {
+	if m.Dist == DIST_STUDENTT && (par.nu <= studentTNuMin || par.nu > studentTNuMax) {
+		return false
+	}
+	pers := m.persistence(par)
+	if m.Model == MODEL_EGARCH {
+		return math.Abs(pers) < 1
+	}
+	if par.omega <= 0 || pers >= 1 {
+		return false
+	}
+	for i, a := range par.alpha {
+		if a < 0 {
+			return false
+		}
+		if m.Model == MODEL_GJR && a+par.gamma[i] < 0 {
+			return false
+		}
+	}
+	for _, b := range par.beta {
+		if b < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// 条件方差递推
+func (m *GarchModel) variance(eps []float64, par garchParams, bc float64) []float64 {
+	n := len(eps)
+	sigma2 := make([]float64, n)
+	if m.Model == MODEL_EGARCH {
+		lnBc := math.Log(bc)
+		lnS2 := make([]float64, n)
+		eAbsZ := math.Sqrt(2 / math.Pi)
+		for t := 0; t < n; t++ {
+			v := par.omega
+			for i := 1; i <= m.P; i++ {
+				if t-i >= 0 {
+					z := eps[t-i] / math.Sqrt(sigma2[t-i])
+					v += par.alpha[i-1]*(math.Abs(z)-eAbsZ) + par.gamma[i-1]*z
+				}
+			}
+			for j := 1; j <= m.Q; j++ {
+				if t-j >= 0 {
+					v += par.beta[j-1] * lnS2[t-j]
+				} else {
+					v += par.beta[j-1] * lnBc
+				}
+			}
+			// 防止 exp 溢出
+			v = math.Max(-700, math.Min(700, v))
+			lnS2[t] = v
+			sigma2[t] = math.Exp(v)
+		}
+		return sigma2
+	}
+
+	for t := 0; t < n; t++ {
+		v := par.omega
+		for i := 1; i <= m.P; i++ {
+			e2, e2neg := bc, 0.5*bc
+			if t-i >= 0 {
+				e := eps[t-i]
+				e2 = e * e
+				e2neg = 0
+				if e < 0 {
+					e2neg = e2
+				}
+			}
+			v += par.alpha[i-1] * e2
+			if m.Model == MODEL_GJR {
+				v += par.gamma[i-1] * e2neg
+			}
+		}
+		for j := 1; j <= m.Q; j++ {
+			if t-j >= 0 {
+				v += par.beta[j-1] * sigma2[t-j]
+			} else {
+				v += par.beta[j-1] * bc
+			}
+		}
+		sigma2[t] = v
+	}
+	return sigma2
+}
+
+// 对数似然
+// output: ll 对数似然; sigma2 条件方差; eps 去均值残差
+func (m *GarchModel) logLik(resid []float64, par garchParams) (ll float64, sigma2, eps []float64) {
+	eps = make([]float64, len(resid))
+	for t, v := range resid {
+		eps[t] = v - par.mu
+	}
+	sigma2 = m.variance(eps, par, backcast(eps))
+	if m.Dist == DIST_STUDENTT {
+		nu := par.nu
+		lgA, _ := math.Lgamma((nu + 1) / 2)
+		lgB, _ := math.Lgamma(nu / 2)
+		c := lgA - lgB - 0.5*math.Log(math.Pi*(nu-2))
+		for t, e := range eps {
+			if sigma2[t] <= 0 {
+				return math.NaN(), sigma2, eps
+			}
+			ll += c - 0.5*math.Log(sigma2[t]) - (nu+1)/2*math.Log1p(e*e/(sigma2[t]*(nu-2)))
+		}
+		return ll, sigma2, eps
+	}
+	for t, e := range eps {
+		if sigma2[t] <= 0 {
+			return math.NaN(), sigma2, eps
+		}
+		ll += -0.5 * (math.Log(2*math.Pi) + math.Log(sigma2[t]) + e*e/sigma2[t])
+	}
+	return ll, sigma2, eps
+}
+
+// 样本前方差: 前 75 个 ε² 按 0.94^i 指数加权
+func backcast(eps []float64) float64 {
+	nb := min(backcastWindow, len(eps))
+	var num, den float64
+	w := 1.0
+	for i := 0; i < nb; i++ {
+		num += w * eps[i] * eps[i]
+		den += w
+		w *= backcastDecay
+	}
+	if den == 0 || num == 0 {
+		return 1e-12
+	}
+	return num / den
+}
@@ -0,0 +1,155 @@
+package garch
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// 模拟 GARCH(1,1) 残差
+func simulateGarch11(n int, omega, alpha, beta float64, seed int64) []float64 {
+	return simulateGjr11(n, omega, alpha, 0, beta, math.Inf(1), seed)
+}
+
+// 模拟 GJR-GARCH(1,1) 残差，nu 为有限整数时新息取标准化 Student-t，否则为正态
+func simulateGjr11(n int, omega, alpha, gamma, beta, nu float64, seed int64) []float64 {
+	rng := rand.New(rand.NewSource(seed))
+	out := make([]float64, n)
+	s2 := omega / (1 - alpha - 0.5*gamma - beta)
+	e := 0.0
+	for t := -200; t < n; t++ {
+		s2 = omega + alpha*e*e + beta*s2
+		if e < 0 {
+			s2 += gamma * e * e
+		}
+		z := rng.NormFloat64()
+		if !math.IsInf(nu, 1) {
+			chi2 := 0.0
+			for k := 0; k < int(nu); k++ {
+				v := rng.NormFloat64()
+				chi2 += v * v
+			}
+			z *= math.Sqrt((nu - 2) / chi2)
+		}
+		e = math.Sqrt(s2) * z
+		if t >= 0 {
+			out[t] = e
+		}
+	}
+	return out
+}
+
+func TestGarchFitForecast(t *testing.T) {
+	resid := simulateGarch11(2000, 0.1, 0.1, 0.8, 31)
+	m, err := Fit(resid, MODEL_GARCH, 1, 1, DIST_NORMAL, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(m.Alpha[0]-0.1) > 0.06 || math.Abs(m.Beta[0]-0.8) > 0.12 || m.Persistence >= 1 {
+		t.Errorf("omega=%v alpha=%v beta=%v", m.Omega, m.Alpha, m.Beta)
+	}
+
+	// 一步预测为 ω + α ε²_T + β σ²_T，长期预测收敛到 ω/(1-α-β)
+	fc, err := m.ForecastVariance(500, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	n := len(resid)
+	eT := resid[n-1] - m.Mu
+	if want := m.Omega + m.Alpha[0]*eT*eT + m.Beta[0]*m.Sigma2[n-1]; math.Abs(fc[0]-want) > 1e-12 {
+		t.Errorf("h=1: %v, want %v", fc[0], want)
+	}
+	uncond := m.Omega / (1 - m.Alpha[0] - m.Beta[0])
+	if math.Abs(fc[499]-uncond) > 1e-8*uncond || math.Abs(m.UnconditionalVariance()-uncond) > 1e-12 {
+		t.Errorf("h=500: %v, want %v", fc[499], uncond)
+	}
+	// 预测单调趋向无条件方差
+	for h := 1; h < len(fc); h++ {
+		if math.Abs(fc[h]-uncond) > math.Abs(fc[h-1]-uncond)+1e-15 {
+			t.Fatalf("forecast moves away from unconditional variance at h=%d", h+1)
+		}
+	}
+
+	// 对拟合样本重新滤波得到相同的条件方差
+	sigma2, stdResid, err := m.Filter(resid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range sigma2 {
+		if math.Abs(sigma2[i]-m.Sigma2[i]) > 1e-12 || math.Abs(stdResid[i]-m.StdResid[i]) > 1e-12 {
+			t.Fatalf("Filter differs from fit at %d", i)
+		}
+	}
+}
+
+// EGARCH 模拟预测在固定 rng 下可复现，一步预测不依赖模拟
+func TestEgarchForecastReproducible(t *testing.T) {
+	resid := simulateGarch11(1000, 0.1, 0.1, 0.8, 32)
+	m, err := Fit(resid, MODEL_EGARCH, 1, 1, DIST_NORMAL, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.ForecastVariance(5, nil); err == nil {
+		t.Error("EGARCH forecast without rng accepted")
+	}
+	a, _ := m.ForecastVariance(20, rand.New(rand.NewSource(5)))
+	b, _ := m.ForecastVariance(20, rand.New(rand.NewSource(5)))
+	c, _ := m.ForecastVariance(20, rand.New(rand.NewSource(6)))
+	for h := range a {
+		if a[h] != b[h] {
+			t.Fatalf("h=%d not reproducible: %v vs %v", h+1, a[h], b[h])
+		}
+	}
+	if a[19] == c[19] {
+		t.Error("different seeds give identical forecasts")
+	}
+	n := len(resid)
+	z := m.StdResid[n-1]
+	lnS2 := m.Omega + m.Alpha[0]*(math.Abs(z)-math.Sqrt(2/math.Pi)) + m.Gamma[0]*z + m.Beta[0]*math.Log(m.Sigma2[n-1])
+	if want := math.Exp(lnS2); math.Abs(a[0]-want) > 1e-10*want || a[0] != c[0] {
+		t.Errorf("h=1: %v, want %v", a[0], want)
+	}
+}
+
+func TestGjrFitRecoversLeverage(t *testing.T) {
+	const omega, alpha, gamma, beta = 0.05, 0.05, 0.15, 0.8
+	resid := simulateGjr11(4000, omega, alpha, gamma, beta, math.Inf(1), 33)
+	m, err := Fit(resid, MODEL_GJR, 1, 1, DIST_NORMAL, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 参数向量 [ω, α, γ, β]
+	if math.Abs(m.Gamma[0]-gamma) > 3*m.SE[2] || math.Abs(m.Gamma[0]-gamma) > 0.06 {
+		t.Errorf("gamma=%v se=%v", m.Gamma[0], m.SE[2])
+	}
+	if math.Abs(m.Alpha[0]-alpha) > 0.05 || math.Abs(m.Beta[0]-beta) > 0.08 {
+		t.Errorf("alpha=%v beta=%v", m.Alpha, m.Beta)
+	}
+	if want := m.Alpha[0] + 0.5*m.Gamma[0] + m.Beta[0]; math.Abs(m.Persistence-want) > 1e-12 {
+		t.Errorf("persistence=%v, want %v", m.Persistence, want)
+	}
+}
+
+func TestStudentTFitRecoversNu(t *testing.T) {
+	const omega, alpha, beta, nu = 0.1, 0.1, 0.8, 5
+	resid := simulateGjr11(4000, omega, alpha, 0, beta, nu, 34)
+	m, err := Fit(resid, MODEL_GARCH, 1, 1, DIST_STUDENTT, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 参数向量 [ω, α, β, ν]
+	if math.Abs(m.Nu-nu) > 3*m.SE[3] || math.Abs(m.Nu-nu) > 1.5 {
+		t.Errorf("nu=%v se=%v", m.Nu, m.SE[3])
+	}
+	if math.Abs(m.Alpha[0]-alpha) > 0.05 || math.Abs(m.Beta[0]-beta) > 0.08 {
+		t.Errorf("alpha=%v beta=%v", m.Alpha, m.Beta)
+	}
+	// 同一数据下正态似然应显著劣于 t 似然
+	normal, err := Fit(resid, MODEL_GARCH, 1, 1, DIST_NORMAL, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.LogLik-normal.LogLik < 10 {
+		t.Errorf("t logLik %v vs normal %v", m.LogLik, normal.LogLik)
+	}
+}
@@ -0,0 +1,77 @@
+package garch
+
+// 波动率模型类型
+type VolModel int
+
+const (
+	MODEL_GARCH  VolModel = iota // "GARCH"
+	MODEL_GJR                    // "GJR-GARCH"
+	MODEL_EGARCH                 // "EGARCH"
+	MODEL_ERROR                  // "ERROR"
+)
+
+func (s VolModel) String() string {
+	switch s {
+	case MODEL_GARCH:
+		return "GARCH"
+	case MODEL_GJR:
+		return "GJR-GARCH"
+	case MODEL_EGARCH:
+		return "EGARCH"
+	default:
+		return "ERROR"
+	}
+}
+
+func GetMyVolModel(s string) VolModel {
+	switch s {
+	case "GARCH":
+		return MODEL_GARCH
+	case "GJR-GARCH":
+		return MODEL_GJR
+	case "EGARCH":
+		return MODEL_EGARCH
+	default:
+		return MODEL_ERROR
+	}
+}
+
+// 新息分布
+type InnovDist int
+
+const (
+	DIST_NORMAL   InnovDist = iota // "normal"
+	DIST_STUDENTT                  // "t" 标准化Student-t(方差为1)
+	DIST_ERROR                     // "ERROR"
+)
+
+func (s InnovDist) String() string {
+	switch s {
+	case DIST_NORMAL:
+		return "normal"
+	case DIST_STUDENTT:
+		return "t"
+	default:
+		return "ERROR"
+	}
+}
+
+func GetMyInnovDist(s string) InnovDist {
+	switch s {
+	case "normal":
+		return DIST_NORMAL
+	case "t":
+		return DIST_STUDENTT
+	default:
+		return DIST_ERROR
+	}
+}
+
+const (
+	backcastDecay   = 0.94  // 初始方差回推的指数权重(与 arch 包一致)
+	backcastWindow  = 75    // 初始方差回推使用的样本数
+	forecastSims    = 10000 // EGARCH 多步预测的模拟路径数
+	studentTNuMin   = 2.05  // Student-t 自由度下界
+	studentTNuMax   = 500.0 // Student-t 自由度上界
+	studentTNuStart = 8.0   // Student-t 自由度初值
+)
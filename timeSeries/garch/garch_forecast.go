@@ -0,0 +1,126 @@
+package garch
+
+import (
+	"math"
+	"math/rand"
+	"ofeisInfra/infra/errorx"
+	"ofeisInfra/infra/errorx/errCode"
+
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// 多步条件方差预测 E[σ²_{T+h} | F_T]，h = 1..steps
+// GARCH / GJR 用解析递推: 未来 E[ε²] = σ²，E[ε²·I(ε<0)] = 0.5σ²(新息对称)
+// EGARCH 无解析式，按拟合的新息分布模拟 10000 条路径取均值，rng 用于复现(GARCH/GJR 可传 nil)
+func (m *GarchModel) ForecastVariance(steps int, rng *rand.Rand) ([]float64, error) {
+	if steps <= 0 {
+		return nil, errorx.New(errCode.INVALID_VALUE, "预测步数必须 > 0")
+	}
+	if len(m.resid) == 0 {
+		return nil, errorx.New(errCode.EMPTY_VALUE, "模型未拟合")
+	}
+	if m.Model == MODEL_EGARCH {
+		if rng == nil {
+			return nil, errorx.New(errCode.INVALID_VALUE, "EGARCH 预测需要随机数生成器 rng")
+		}
+		return m.forecastEgarch(steps, rng), nil
+	}
+
+	n := len(m.resid)
+	// e2/e2neg/s2 在样本内取实际值，样本外取期望
+	e2 := make([]float64, n+steps)
+	e2neg := make([]float64, n+steps)
+	s2 := make([]float64, n+steps)
+	for t, e := range m.resid {
+		e2[t] = e * e
+		if e < 0 {
+			e2neg[t] = e * e
+		}
+		s2[t] = m.Sigma2[t]
+	}
+	out := make([]float64, steps)
+	for t := n; t < n+steps; t++ {
+		v := m.Omega
+		for i := 1; i <= m.P; i++ {
+			a, b := m.backcast, 0.5*m.backcast
+			if t-i >= 0 {
+				a, b = e2[t-i], e2neg[t-i]
+			}
+			v += m.Alpha[i-1] * a
+			if m.Model == MODEL_GJR {
+				v += m.Gamma[i-1] * b
+			}
+		}
+		for j := 1; j <= m.Q; j++ {
+			if t-j >= 0 {
+				v += m.Beta[j-1] * s2[t-j]
+			} else {
+				v += m.Beta[j-1] * m.backcast
+			}
+		}
+		s2[t] = v
+		e2[t] = v
+		e2neg[t] = 0.5 * v
+		out[t-n] = v
+	}
+	return out, nil
+}
+
+// EGARCH 模拟预测
+func (m *GarchModel) forecastEgarch(steps int, rng *rand.Rand) []float64 {
+	n := len(m.resid)
+	eAbsZ := math.Sqrt(2 / math.Pi)
+	var tDist distuv.StudentsT
+	if m.Dist == DIST_STUDENTT {
+		tDist = distuv.StudentsT{Mu: 0, Sigma: math.Sqrt((m.Nu - 2) / m.Nu), Nu: m.Nu, Src: rng}
+	}
+	draw := func() float64 {
+		if m.Dist == DIST_STUDENTT {
+			return tDist.Rand()
+		}
+		return rng.NormFloat64()
+	}
+
+	lnBc := math.Log(m.backcast)
+	// 样本内历史: z 与 lnσ²
+	hist := max(m.P, m.Q)
+	zHist := make([]float64, hist)
+	lnHist := make([]float64, hist)
+	for k := 0; k < hist; k++ {
+		t := n - hist + k
+		if t >= 0 {
+			zHist[k] = m.StdResid[t]
+			lnHist[k] = math.Log(m.Sigma2[t])
+		} else {
+			lnHist[k] = lnBc
+		}
+	}
+	sum := make([]float64, steps)
+	z := make([]float64, hist+steps)
+	lnS2 := make([]float64, hist+steps)
+	for s := 0; s < forecastSims; s++ {
+		copy(z, zHist)
+		copy(lnS2, lnHist)
+		for h := 0; h < steps; h++ {
+			k := hist + h
+			v := m.Omega
+			for i := 1; i <= m.P; i++ {
+				// 样本前的 z 不参与递推
+				if n-hist+k-i >= 0 {
+					v += m.Alpha[i-1]*(math.Abs(z[k-i])-eAbsZ) + m.Gamma[i-1]*z[k-i]
+				}
+			}
+			for j := 1; j <= m.Q; j++ {
+				v += m.Beta[j-1] * lnS2[k-j]
+			}
+			v = math.Max(-700, math.Min(700, v))
+			lnS2[k] = v
+			z[k] = draw()
+			sum[h] += math.Exp(v)
+		}
+	}
+	for h := range sum {
+		sum[h] /= forecastSims
+	}
+	return sum
+}
@@ -0,0 +1,161 @@
+// Granger 因果检验(与 statsmodels grangercausalitytests 一致)
+// 对滞后阶数 p:
+//
+//	受限模型:   y_t = c + Σ_{i=1}^p a_i y_{t-i} + e_t
+//	非受限模型: y_t = c + Σ_{i=1}^p a_i y_{t-i} + Σ_{i=1}^p b_i x_{t-i} + u_t
+//
+// H0: b_1 = ... = b_p = 0 (x 不是 y 的 Granger 原因)，各阶数的检验使用去掉前 p 个观测后的样本；
+// 阶数选择时所有阶数共用去掉前 maxLag 个观测后的样本，保证信息准则可比
+package granger
+
+import (
+	"fmt"
+	"math"
+	"method/ml/ols"
+	"method/timeSeries/adfuller"
+	"ofeisInfra/infra/errorx"
+	"ofeisInfra/infra/errorx/errCode"
+
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/mathext"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+type GrangerLagResult struct {
+	Lag        int     // 滞后阶数
+	NObs       int     // 有效样本量
+	FStat      float64 // SSR F 统计量 ~ F(p, n-2p-1)
+	FPValue    float64
+	FDf1       int
+	FDf2       int
+	Chi2Stat   float64 // SSR χ² 统计量 n(SSR_r - SSR_u)/SSR_u ~ χ²(p)
+	Chi2PValue float64
+	LRStat     float64 // 似然比统计量 -2(logL_r - logL_u) ~ χ²(p)
+	LRPValue   float64
+	AIC        float64 // 非受限模型 AIC(本阶数样本)
+	BIC        float64 // 非受限模型 BIC(本阶数样本)
+}
+
+type GrangerResult struct {
+	Lags    []GrangerLagResult // 滞后 1..maxLag 的检验结果
+	IC      []float64          // 公共样本上滞后 1..maxLag 的非受限模型信息准则，用于选阶
+	BestLag int                // 按信息准则选出的阶数
+	Best    GrangerLagResult   // BestLag 对应的检验结果
+	Method  adfuller.LagMode   // 阶数选择准则
+}
+
+// Granger 因果检验
+// input: y 被解释序列; x 候选原因序列; maxLag 最大滞后阶数; ic LAG_MODE_AIC 或 LAG_MODE_BIC
+// 在 1..maxLag 中选公共样本上非受限模型信息准则最小的阶数
+func GrangerCausality(y, x []float64, maxLag int, ic adfuller.LagMode) (GrangerResult, error) {
+	if len(y) != len(x) {
+		return GrangerResult{}, errorx.New(errCode.INVALID_VALUE, "y 与 x 长度不一致")
+	}
+	if maxLag < 1 {
+		return GrangerResult{}, errorx.New(errCode.INVALID_VALUE, "maxLag 必须 >= 1")
+	}
+	if ic != adfuller.LAG_MODE_AIC && ic != adfuller.LAG_MODE_BIC {
+		return GrangerResult{}, errorx.New(errCode.INVALID_VALUE, "阶数选择仅支持 AIC 或 BIC")
+	}
+	if err := checkFinite(y, x); err != nil {
+		return GrangerResult{}, err
+	}
+	if len(y)-maxLag <= 2*maxLag+1 {
+		return GrangerResult{}, errorx.New(errCode.INVALID_VALUE, fmt.Sprintf("样本量 %d 不足以支持 maxLag=%d", len(y), maxLag))
+	}
+
+	result := GrangerResult{Method: ic}
+	best := math.Inf(1)
+	for p := 1; p <= maxLag; p++ {
+		res, err := grangerAtLag(y, x, p)
+		if err != nil {
+			return GrangerResult{}, err
+		}
+		result.Lags = append(result.Lags, res)
+		common, err := grangerAtLag(y[maxLag-p:], x[maxLag-p:], p)
+		if err != nil {
+			return GrangerResult{}, err
+		}
+		crit := common.AIC
+		if ic == adfuller.LAG_MODE_BIC {
+			crit = common.BIC
+		}
+		result.IC = append(result.IC, crit)
+		if crit < best {
+			best = crit
+			result.BestLag = p
+			result.Best = res
+		}
+	}
+	if result.BestLag == 0 {
+		return GrangerResult{}, errorx.New(errCode.INVALID_VALUE, "各阶数的信息准则均无效")
+	}
+	return result, nil
+}
+
+func grangerAtLag(y, x []float64, p int) (GrangerLagResult, error) {
+	n := len(y) - p
+	matR := mat.NewDense(n, p+1, nil)
+	matU := mat.NewDense(n, 2*p+1, nil)
+	matY := mat.NewVecDense(n, nil)
+	for t := p; t < len(y); t++ {
+		row := t - p
+		matR.Set(row, 0, 1)
+		matU.Set(row, 0, 1)
+		for i := 1; i <= p; i++ {
+			matR.Set(row, i, y[t-i])
+			matU.Set(row, i, y[t-i])
+			matU.Set(row, p+i, x[t-i])
+		}
+		matY.SetVec(row, y[t])
+	}
+	restricted, err := ols.MultiRegressionMat(matR, matY)
+	if err != nil {
+		return GrangerLagResult{}, err
+	}
+	unrestricted, err := ols.MultiRegressionMat(matU, matY)
+	if err != nil {
+		return GrangerLagResult{}, err
+	}
+	ssrR := sumSquares(restricted.Resids)
+	ssrU := sumSquares(unrestricted.Resids)
+	nf := float64(n)
+	df2 := n - 2*p - 1
+
+	res := GrangerLagResult{Lag: p, NObs: n, FDf1: p, FDf2: df2, AIC: unrestricted.AIC, BIC: unrestricted.BIC}
+	res.FStat = (ssrR - ssrU) / ssrU * float64(df2) / float64(p)
+	res.FPValue = fSurvival(res.FStat, float64(p), float64(df2))
+	chi2 := distuv.ChiSquared{K: float64(p)}
+	res.Chi2Stat = nf * (ssrR - ssrU) / ssrU
+	res.Chi2PValue = chi2.Survival(res.Chi2Stat)
+	res.LRStat = nf * math.Log(ssrR/ssrU)
+	res.LRPValue = chi2.Survival(res.LRStat)
+	return res, nil
+}
+
+func sumSquares(x []float64) float64 {
+	s := 0.0
+	for _, v := range x {
+		s += v * v
+	}
+	return s
+}
+
+// F 分布尾概率，直接用正则化不完全 Beta 避免 1-CDF 精度损失
+func fSurvival(f, d1, d2 float64) float64 {
+	if f <= 0 || math.IsNaN(f) {
+		return 1
+	}
+	return mathext.RegIncBeta(d2/2, d1/2, d2/(d2+d1*f))
+}
+
+func checkFinite(series ...[]float64) error {
+	for _, s := range series {
+		for _, v := range s {
+			if math.IsNaN(v) || math.IsInf(v, 0) {
+				return errorx.New(errCode.INVALID_VALUE, "序列含 NaN/Inf")
+			}
+		}
+	}
+	return nil
+}
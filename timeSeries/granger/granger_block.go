@@ -0,0 +1,191 @@
+// 多变量 Granger 因果(块外生性)检验
+// 对 VAR(p) 中 effect 组变量的方程，检验 cause 组变量的全部滞后系数是否同时为 0
+// 受限/非受限系统各方程回归变量相同，逐方程 OLS 即为系统估计，
+//
+//	LR = T·(ln|Σ_r| - ln|Σ_u|) ~ χ²(p·k_e·k_c)
+//
+// 小样本修正(Sims 1980): 以 T - m 代替 T，m 为非受限模型每个方程的参数个数
+package granger
+
+import (
+	"fmt"
+	"math"
+	"method/ml/ols"
+	"method/timeSeries/adfuller"
+	"ofeisInfra/infra/errorx"
+	"ofeisInfra/infra/errorx/errCode"
+
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+type BlockResult struct {
+	Lag           int     // VAR 滞后阶数
+	NObs          int     // 有效样本量
+	Df            int     // 约束个数 p·k_e·k_c
+	LRStat        float64 // 似然比统计量
+	LRPValue      float64
+	LRSmallStat   float64 // Sims 小样本修正似然比统计量
+	LRSmallPValue float64
+	AIC           float64 // 非受限 VAR 的系统 AIC
+	BIC           float64 // 非受限 VAR 的系统 BIC
+}
+
+// 块外生性检验
+// input: endog 多变量序列(n×m，每行一个观测); effect 被解释变量列号; cause 原因变量列号(与 effect 不相交);
+// maxLag 最大滞后阶数; ic LAG_MODE_AIC 或 LAG_MODE_BIC，按非受限 VAR 的系统信息准则在 1..maxLag 中选阶，
+// 选阶时所有阶数共用去掉前 maxLag 个观测后的样本；检验统计量在选出阶数自身的样本上计算
+// 其余列作为控制变量进入每个方程
+func BlockExogeneityTest(endog [][]float64, effect, cause []int, maxLag int, ic adfuller.LagMode) (BlockResult, error) {
+	n := len(endog)
+	if n == 0 {
+		return BlockResult{}, errorx.New(errCode.EMPTY_VALUE, "endog 为空")
+	}
+	m := len(endog[0])
+	for _, row := range endog {
+		if len(row) != m {
+			return BlockResult{}, errorx.New(errCode.INVALID_VALUE, "endog 各行长度不一致")
+		}
+		if err := checkFinite(row); err != nil {
+			return BlockResult{}, err
+		}
+	}
+	if len(effect) == 0 || len(cause) == 0 {
+		return BlockResult{}, errorx.New(errCode.INVALID_VALUE, "effect 与 cause 不能为空")
+	}
+	seen := make(map[int]bool)
+	for _, c := range append(append([]int(nil), effect...), cause...) {
+		if c < 0 || c >= m || seen[c] {
+			return BlockResult{}, errorx.New(errCode.INVALID_VALUE, "effect/cause 列号越界或重复")
+		}
+		seen[c] = true
+	}
+	if maxLag < 1 {
+		return BlockResult{}, errorx.New(errCode.INVALID_VALUE, "maxLag 必须 >= 1")
+	}
+	if ic != adfuller.LAG_MODE_AIC && ic != adfuller.LAG_MODE_BIC {
+		return BlockResult{}, errorx.New(errCode.INVALID_VALUE, "阶数选择仅支持 AIC 或 BIC")
+	}
+	if n-maxLag <= m*maxLag+1 {
+		return BlockResult{}, errorx.New(errCode.INVALID_VALUE, fmt.Sprintf("样本量 %d 不足以支持 %d 变量 VAR(%d)", n, m, maxLag))
+	}
+
+	bestLag := 0
+	bestIC := math.Inf(1)
+	for p := 1; p <= maxLag; p++ {
+		res, err := blockAtLag(endog[maxLag-p:], effect, cause, p)
+		if err != nil {
+			return BlockResult{}, err
+		}
+		crit := res.AIC
+		if ic == adfuller.LAG_MODE_BIC {
+			crit = res.BIC
+		}
+		if crit < bestIC {
+			bestIC = crit
+			bestLag = p
+		}
+	}
+	if bestLag == 0 {
+		return BlockResult{}, errorx.New(errCode.INVALID_VALUE, "各阶数的信息准则均无效")
+	}
+	return blockAtLag(endog, effect, cause, bestLag)
+}
+
+func blockAtLag(endog [][]float64, effect, cause []int, p int) (BlockResult, error) {
+	n, m := len(endog), len(endog[0])
+	T := n - p
+	isCause := make(map[int]bool, len(cause))
+	for _, c := range cause {
+		isCause[c] = true
+	}
+
+	// 非受限: 常数 + 全部变量滞后; 受限: 去掉 cause 组的滞后
+	kU := 1 + m*p
+	kR := kU - len(cause)*p
+	XU := mat.NewDense(T, kU, nil)
+	XR := mat.NewDense(T, kR, nil)
+	for t := p; t < n; t++ {
+		row := t - p
+		XU.Set(row, 0, 1)
+		XR.Set(row, 0, 1)
+		cu, cr := 1, 1
+		for i := 1; i <= p; i++ {
+			for j := 0; j < m; j++ {
+				XU.Set(row, cu, endog[t-i][j])
+				cu++
+				if !isCause[j] {
+					XR.Set(row, cr, endog[t-i][j])
+					cr++
+				}
+			}
+		}
+	}
+
+	// 非受限 VAR 全部方程的残差(用于系统信息准则)，及 effect 组受限残差
+	residU := make([][]float64, m)
+	for j := 0; j < m; j++ {
+		model, err := ols.MultiRegressionMat(XU, column(endog, j, p))
+		if err != nil {
+			return BlockResult{}, err
+		}
+		residU[j] = model.Resids
+	}
+	residR := make([][]float64, len(effect))
+	residUe := make([][]float64, len(effect))
+	for k, j := range effect {
+		model, err := ols.MultiRegressionMat(XR, column(endog, j, p))
+		if err != nil {
+			return BlockResult{}, err
+		}
+		residR[k] = model.Resids
+		residUe[k] = residU[j]
+	}
+
+	ldU := logDetCov(residUe)
+	ldR := logDetCov(residR)
+	df := p * len(effect) * len(cause)
+	chi2 := distuv.ChiSquared{K: float64(df)}
+	res := BlockResult{Lag: p, NObs: T, Df: df}
+	res.LRStat = float64(T) * (ldR - ldU)
+	res.LRPValue = chi2.Survival(res.LRStat)
+	res.LRSmallStat = float64(T-kU) * (ldR - ldU)
+	res.LRSmallPValue = chi2.Survival(res.LRSmallStat)
+
+	// 系统信息准则: ln|Σ_u| + 2·(m·kU)/T 或 ln(T)·(m·kU)/T
+	ldAll := logDetCov(residU)
+	nParams := float64(m * kU)
+	res.AIC = ldAll + 2*nParams/float64(T)
+	res.BIC = ldAll + math.Log(float64(T))*nParams/float64(T)
+	return res, nil
+}
+
+// 第 j 列从下标 start 起的观测
+func column(endog [][]float64, j, start int) *mat.VecDense {
+	v := mat.NewVecDense(len(endog)-start, nil)
+	for t := start; t < len(endog); t++ {
+		v.SetVec(t-start, endog[t][j])
+	}
+	return v
+}
+
+// ln|E'E/T|，resid 每个元素为一个方程的残差
+func logDetCov(resid [][]float64) float64 {
+	k := len(resid)
+	T := float64(len(resid[0]))
+	S := mat.NewSymDense(k, nil)
+	for a := 0; a < k; a++ {
+		for b := a; b < k; b++ {
+			s := 0.0
+			for t := range resid[a] {
+				s += resid[a][t] * resid[b][t]
+			}
+			S.SetSym(a, b, s/T)
+		}
+	}
+	var chol mat.Cholesky
+	if !chol.Factorize(S) {
+		return math.NaN()
+	}
+	return chol.LogDet()
+}
@@ -0,0 +1,43 @@
+// 极大似然估计的公共推断工具，供 arima、garch 等按数值似然估计的模型共用
+package mle
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/diff/fd"
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// 负对数似然的数值 Hessian 求逆得到渐近协方差
+// Hessian 不可逆时全部返回 NaN，单个方差非正时对应参数返回 NaN
+// output: se 标准误; zStats z统计量; pValues 双尾p值
+func HessianInference(negLogLik func([]float64) float64, params []float64) (se, zStats, pValues []float64) {
+	k := len(params)
+	se = make([]float64, k)
+	zStats = make([]float64, k)
+	pValues = make([]float64, k)
+	if k == 0 {
+		return
+	}
+	H := mat.NewSymDense(k, nil)
+	fd.Hessian(H, negLogLik, params, nil)
+	var cov mat.Dense
+	if err := cov.Inverse(H); err != nil {
+		for i := range se {
+			se[i], zStats[i], pValues[i] = math.NaN(), math.NaN(), math.NaN()
+		}
+		return
+	}
+	for i := 0; i < k; i++ {
+		v := cov.At(i, i)
+		if v <= 0 || math.IsNaN(v) {
+			se[i], zStats[i], pValues[i] = math.NaN(), math.NaN(), math.NaN()
+			continue
+		}
+		se[i] = math.Sqrt(v)
+		zStats[i] = params[i] / se[i]
+		pValues[i] = 2 * distuv.UnitNormal.Survival(math.Abs(zStats[i]))
+	}
+	return
+}
@@ -0,0 +1,31 @@
+package mle
+
+import (
+	"math"
+	"testing"
+)
+
+// 二次型负对数似然 0.5(x-m)'A(x-m) 的渐近协方差为 A^{-1}
+func TestHessianInference(t *testing.T) {
+	// A = [[4, 1], [1, 2]]，A^{-1} = [[2, -1], [-1, 4]] / 7
+	m := []float64{1.5, -0.2}
+	negLogLik := func(x []float64) float64 {
+		d0, d1 := x[0]-m[0], x[1]-m[1]
+		return 0.5 * (4*d0*d0 + 2*d0*d1 + 2*d1*d1)
+	}
+	se, z, p := HessianInference(negLogLik, m)
+	wantSE := []float64{math.Sqrt(2.0 / 7), math.Sqrt(4.0 / 7)}
+	for i := range m {
+		if math.Abs(se[i]-wantSE[i]) > 1e-6 || math.Abs(z[i]-m[i]/wantSE[i]) > 1e-5 || !(p[i] > 0 && p[i] < 1) {
+			t.Errorf("param %d: se=%v z=%v p=%v", i, se[i], z[i], p[i])
+		}
+	}
+	// 奇异 Hessian 返回 NaN
+	flat := func(x []float64) float64 { return (x[0] + x[1]) * (x[0] + x[1]) }
+	if se, _, _ := HessianInference(flat, []float64{1, 1}); !math.IsNaN(se[0]) || !math.IsNaN(se[1]) {
+		t.Errorf("singular Hessian: se=%v", se)
+	}
+	if se, _, _ := HessianInference(flat, nil); len(se) != 0 {
+		t.Error("empty params")
+	}
+}
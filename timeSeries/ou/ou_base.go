@@ -0,0 +1,288 @@
+// Ornstein-Uhlenbeck 过程参数估计，用于价差均值回复的半衰期
+//
+//	dX_t = κ(θ - X_t)dt + σ dW_t
+//
+// 精确离散化: X_{t+Δ} = θ + (X_t - θ)e^{-κΔ} + ε,  Var(ε) = σ²(1 - e^{-2κΔ}) / (2κ)
+// 半衰期 = ln2 / κ，与 Δ 的时间单位一致
+package ou
+
+import (
+	"math"
+	"method/ml/ols"
+	"method/timeSeries/adfuller"
+	"method/timeSeries/internal/mle"
+	"ofeisInfra/infra/errorx"
+	"ofeisInfra/infra/errorx/errCode"
+
+	"gonum.org/v1/gonum/diff/fd"
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/optimize"
+)
+
+type OUResult struct {
+	Kappa      float64   // 回复速度 κ
+	Theta      float64   // 长期均值 θ
+	Sigma      float64   // 扩散系数 σ
+	HalfLife   float64   // 半衰期 ln2/κ
+	KappaSE    float64   // κ 标准误
+	ThetaSE    float64   // θ 标准误
+	SigmaSE    float64   // σ 标准误
+	HalfLifeSE float64   // 半衰期标准误(delta 法)
+	LogLik     float64   // 精确转移密度下的对数似然
+	NObs       int       // 转移个数
+	Method     OUMethod  // 估计方法
+	Resid      []float64 // 标准化残差 (X_i - E[X_i|X_{i-1}]) / sd
+}
+
+// 等间隔样本估计
+// input: x 样本(不可含 NaN); dt 采样间隔(>0); method METHOD_EXACT_MLE 或 METHOD_AR1_OLS
+func Fit(x []float64, dt float64, method OUMethod) (OUResult, error) {
+	if dt <= 0 || math.IsNaN(dt) {
+		return OUResult{}, errorx.New(errCode.INVALID_VALUE, "dt 必须 > 0")
+	}
+	dts := make([]float64, max(len(x)-1, 0))
+	for i := range dts {
+		dts[i] = dt
+	}
+	return fit(x, dts, method)
+}
+
+// 非等间隔样本估计
+// input: x 样本; times 对应时间戳(严格递增); method 同 Fit
+// METHOD_AR1_OLS 在非等间隔时使用 Euler 加权回归 ΔX/√Δ = κθ√Δ - κX√Δ + σ·η
+func FitIrregular(x, times []float64, method OUMethod) (OUResult, error) {
+	if len(times) != len(x) {
+		return OUResult{}, errorx.New(errCode.INVALID_VALUE, "x 与 times 长度不一致")
+	}
+	dts := make([]float64, max(len(x)-1, 0))
+	for i := range dts {
+		dts[i] = times[i+1] - times[i]
+		if !(dts[i] > 0) {
+			return OUResult{}, errorx.New(errCode.INVALID_VALUE, "times 必须严格递增")
+		}
+	}
+	return fit(x, dts, method)
+}
+
+// 由 AdfTest 结果换算 OU 参数，复用已估计的 gamma
+// Δy_t = γ y_{t-1} + c + Σ... 对应 AR(1) 系数 b = 1+γ，κ = -ln(1+γ)/dt，θ = -c/γ
+// σ 由残差方差 s² 按 σ² = 2κs² / (1-b²) 换算；含滞后差分项时为近似值
+// 仅 κ 与半衰期有标准误(SE(γ) = γ/t)，θ、σ 的标准误为 NaN
+func FromADF(res adfuller.ADFResult, dt float64) (OUResult, error) {
+	if dt <= 0 || math.IsNaN(dt) {
+		return OUResult{}, errorx.New(errCode.INVALID_VALUE, "dt 必须 > 0")
+	}
+	if res.Detrend != "" {
+		return OUResult{}, errorx.New(errCode.INVALID_VALUE, "DF-GLS 结果为去趋势序列的回归, 无法换算 OU 均值")
+	}
+	switch adfuller.GetMyTrend(res.Trend) {
+	case adfuller.TREND_N, adfuller.TREND_C:
+	case adfuller.TREND_CT, adfuller.TREND_CTT:
+		return OUResult{}, errorx.New(errCode.INVALID_VALUE, "含时间趋势的 ADF 回归没有恒定的 OU 均值")
+	default:
+		return OUResult{}, errorx.New(errCode.INVALID_VALUE, "未知的 ADF 趋势类型")
+	}
+	g := res.Gamma
+	if !(g < 0 && g > -1) {
+		return OUResult{}, errorx.New(errCode.INVALID_VALUE, "gamma 不在 (-1, 0) 内, 序列不具备均值回复")
+	}
+	if len(res.Resid) < 3 {
+		return OUResult{}, errorx.New(errCode.EMPTY_VALUE, "ADFResult 残差为空")
+	}
+	_, muHat, _ := res.GetEstmate()
+
+	b := 1 + g
+	out := OUResult{Method: METHOD_ADF, NObs: res.NObs}
+	out.Kappa = -math.Log(b) / dt
+	out.Theta = -muHat / g
+	ss := 0.0
+	for _, e := range res.Resid {
+		ss += e * e
+	}
+	s2 := ss / float64(len(res.Resid)-len(res.Coeffs))
+	out.Sigma = math.Sqrt(2 * out.Kappa * s2 / (1 - b*b))
+	out.HalfLife = math.Ln2 / out.Kappa
+
+	seG := math.NaN()
+	if res.TStat != 0 {
+		seG = math.Abs(g / res.TStat)
+	}
+	out.KappaSE = seG / (b * dt)
+	out.HalfLifeSE = math.Ln2 / (out.Kappa * out.Kappa) * out.KappaSE
+	out.ThetaSE, out.SigmaSE = math.NaN(), math.NaN()
+	out.LogLik = math.NaN() // ADFResult 不含原序列
+	return out, nil
+}
+
+func fit(x, dts []float64, method OUMethod) (OUResult, error) {
+	if method != METHOD_EXACT_MLE && method != METHOD_AR1_OLS {
+		return OUResult{}, errorx.New(errCode.INVALID_VALUE, "未知的估计方法")
+	}
+	if len(x) < 10 {
+		return OUResult{}, errorx.New(errCode.INVALID_VALUE, "样本量过小(<10), 无法估计 OU 参数")
+	}
+	for _, v := range x {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return OUResult{}, errorx.New(errCode.INVALID_VALUE, "序列含 NaN/Inf")
+		}
+	}
+
+	// 1. AR(1) 回归估计，同时作为 MLE 初值
+	out, err := fitAR1(x, dts)
+	if err != nil {
+		return OUResult{}, err
+	}
+
+	// 2. 精确离散化极大似然，参数 (lnκ, θ, lnσ) 无约束优化
+	if method == METHOD_EXACT_MLE {
+		obj := func(p []float64) float64 {
+			ll, _ := exactLogLik(x, dts, math.Exp(p[0]), p[1], math.Exp(p[2]))
+			if math.IsNaN(ll) {
+				return math.Inf(1)
+			}
+			return -ll
+		}
+		x0 := []float64{math.Log(out.Kappa), out.Theta, math.Log(out.Sigma)}
+		best, bestF := x0, obj(x0)
+		if res, _ := optimize.Minimize(optimize.Problem{Func: obj}, x0, nil, &optimize.NelderMead{}); res != nil && res.F < bestF {
+			best = res.X
+		}
+		out = OUResult{Kappa: math.Exp(best[0]), Theta: best[1], Sigma: math.Exp(best[2])}
+		out.HalfLife = math.Ln2 / out.Kappa
+
+		// 原参数空间数值 Hessian → 标准误
+		se, _, _ := mle.HessianInference(func(p []float64) float64 {
+			ll, _ := exactLogLik(x, dts, p[0], p[1], p[2])
+			return -ll
+		}, []float64{out.Kappa, out.Theta, out.Sigma})
+		out.KappaSE, out.ThetaSE, out.SigmaSE = se[0], se[1], se[2]
+		out.HalfLifeSE = math.Ln2 / (out.Kappa * out.Kappa) * out.KappaSE
+	}
+
+	out.Method = method
+	out.NObs = len(dts)
+	out.LogLik, out.Resid = exactLogLik(x, dts, out.Kappa, out.Theta, out.Sigma)
+	return out, nil
+}
+
+// AR(1) 回归估计
+// 等间隔: X_i = a + b X_{i-1} + e，κ = -ln b/Δ，θ = a/(1-b)，σ² = 2κs²/(1-b²)
+// 非等间隔: Euler 加权回归 ΔX/√Δ = c1√Δ + c2·X√Δ + e，κ = -c2，θ = c1/κ，σ² = s²
+// 标准误由 (系数, s²) 的渐近协方差经 delta 法得到，Var(s²) = 2s⁴/(n-2)
+func fitAR1(x, dts []float64) (OUResult, error) {
+	n := len(dts)
+	regular := true
+	for _, d := range dts {
+		if math.Abs(d-dts[0]) > 1e-12*dts[0] {
+			regular = false
+			break
+		}
+	}
+
+	X := mat.NewDense(n, 2, nil)
+	y := mat.NewVecDense(n, nil)
+	for i := 0; i < n; i++ {
+		if regular {
+			X.Set(i, 0, 1)
+			X.Set(i, 1, x[i])
+			y.SetVec(i, x[i+1])
+		} else {
+			sq := math.Sqrt(dts[i])
+			X.Set(i, 0, sq)
+			X.Set(i, 1, x[i]*sq)
+			y.SetVec(i, (x[i+1]-x[i])/sq)
+		}
+	}
+	model, err := ols.MultiRegressionMat(X, y)
+	if err != nil {
+		return OUResult{}, err
+	}
+	s2 := model.Sigma2
+
+	// 映射 (c0, c1, s²) → (κ, θ, σ)
+	dt := dts[0]
+	mapping := func(p []float64) (kappa, theta, sigma float64) {
+		if regular {
+			kappa = -math.Log(p[1]) / dt
+			theta = p[0] / (1 - p[1])
+			sigma = math.Sqrt(2 * kappa * p[2] / (1 - p[1]*p[1]))
+			return
+		}
+		kappa = -p[1]
+		theta = p[0] / kappa
+		sigma = math.Sqrt(p[2])
+		return
+	}
+	params := []float64{model.Coeffs[0], model.Coeffs[1], s2}
+	kappa, theta, sigma := mapping(params)
+	if !(kappa > 0) || math.IsInf(kappa, 0) {
+		return OUResult{}, errorx.New(errCode.INVALID_VALUE, "估计的 κ <= 0, 序列不具备均值回复")
+	}
+	out := OUResult{Kappa: kappa, Theta: theta, Sigma: sigma, HalfLife: math.Ln2 / kappa}
+
+	// 参数协方差: blockdiag(s²(X'X)^-1, 2s⁴/(n-2))
+	var xtx, inv mat.Dense
+	xtx.Mul(X.T(), X)
+	cov := mat.NewSymDense(3, nil)
+	if err := inv.Inverse(&xtx); err == nil {
+		for i := 0; i < 2; i++ {
+			for j := i; j < 2; j++ {
+				cov.SetSym(i, j, s2*inv.At(i, j))
+			}
+		}
+	}
+	cov.SetSym(2, 2, 2*s2*s2/float64(n-2))
+	ses := make([]float64, 4)
+	for k := range ses {
+		ses[k] = deltaSE(func(p []float64) float64 {
+			kp, th, sg := mapping(p)
+			return []float64{kp, th, sg, math.Ln2 / kp}[k]
+		}, params, cov)
+	}
+	out.KappaSE, out.ThetaSE, out.SigmaSE, out.HalfLifeSE = ses[0], ses[1], ses[2], ses[3]
+	return out, nil
+}
+
+// 精确转移密度对数似然与标准化残差
+func exactLogLik(x, dts []float64, kappa, theta, sigma float64) (float64, []float64) {
+	if !(kappa > 0) || !(sigma > 0) {
+		return math.NaN(), nil
+	}
+	ll := 0.0
+	resid := make([]float64, len(dts))
+	for i, d := range dts {
+		e := math.Exp(-kappa * d)
+		m := theta + (x[i]-theta)*e
+		v := sigma * sigma * -math.Expm1(-2*kappa*d) / (2 * kappa)
+		if !(v > 0) {
+			return math.NaN(), nil
+		}
+		r := x[i+1] - m
+		ll += -0.5 * (math.Log(2*math.Pi*v) + r*r/v)
+		resid[i] = r / math.Sqrt(v)
+	}
+	return ll, resid
+}
+
+// delta 法标准误 sqrt(∇f' Σ ∇f)
+func deltaSE(f func([]float64) float64, params []float64, cov *mat.SymDense) float64 {
+	grad := make([]float64, len(params))
+	fd.Gradient(grad, f, params, &fd.Settings{Formula: fd.Central})
+	g := mat.NewVecDense(len(grad), grad)
+	return safeSqrt(mat.Inner(g, cov, g))
+}
+
+func safeSqrt(v float64) float64 {
+	if v <= 0 || math.IsNaN(v) {
+		return math.NaN()
+	}
+	return math.Sqrt(v)
+}
+
+// 由 κ 计算半衰期
+func HalfLife(kappa float64) float64 {
+	if kappa <= 0 {
+		return math.Inf(1)
+	}
+	return math.Ln2 / kappa
+}
@@ -0,0 +1,139 @@
+package ou
+
+import (
+	"math"
+	"math/rand"
+	"method/timeSeries/adfuller"
+	"testing"
+)
+
+// 按精确转移密度模拟 OU 路径
+func simulateOU(dts []float64, kappa, theta, sigma float64, seed int64) []float64 {
+	rng := rand.New(rand.NewSource(seed))
+	x := make([]float64, len(dts)+1)
+	x[0] = theta
+	for i, d := range dts {
+		e := math.Exp(-kappa * d)
+		sd := sigma * math.Sqrt(-math.Expm1(-2*kappa*d)/(2*kappa))
+		x[i+1] = theta + (x[i]-theta)*e + sd*rng.NormFloat64()
+	}
+	return x
+}
+
+func TestFitRecoversOU(t *testing.T) {
+	const kappa, theta, sigma, dt = 2.0, 1.0, 0.5, 1.0 / 52
+	dts := make([]float64, 3000)
+	for i := range dts {
+		dts[i] = dt
+	}
+	x := simulateOU(dts, kappa, theta, sigma, 33)
+
+	mle, err := Fit(x, dt, METHOD_EXACT_MLE)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(mle.Kappa-kappa) > 3*mle.KappaSE || math.Abs(mle.Theta-theta) > 3*mle.ThetaSE ||
+		math.Abs(mle.Sigma-sigma) > 3*mle.SigmaSE || mle.NObs != len(dts) {
+		t.Errorf("mle: %+v", mle)
+	}
+	if math.Abs(mle.HalfLife-math.Ln2/mle.Kappa) > 1e-12 {
+		t.Errorf("half-life %v", mle.HalfLife)
+	}
+
+	// 等间隔时条件 MLE 与 AR(1) 回归映射只差 σ² 的自由度修正
+	ar1, err := Fit(x, dt, METHOD_AR1_OLS)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(ar1.Kappa-mle.Kappa) > 1e-3*mle.Kappa || math.Abs(ar1.Theta-mle.Theta) > 1e-4 ||
+		math.Abs(ar1.Sigma-mle.Sigma) > 1e-3*mle.Sigma {
+		t.Errorf("ar1 %+v vs mle kappa=%v theta=%v sigma=%v", ar1, mle.Kappa, mle.Theta, mle.Sigma)
+	}
+
+	// 以等间隔时间戳调用 FitIrregular 与 Fit 相同
+	times := make([]float64, len(x))
+	for i := range times {
+		times[i] = float64(i) * dt
+	}
+	irr, err := FitIrregular(x, times, METHOD_EXACT_MLE)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(irr.Kappa-mle.Kappa) > 1e-6*mle.Kappa || math.Abs(irr.Theta-mle.Theta) > 1e-6 {
+		t.Errorf("irregular on regular grid: %+v", irr)
+	}
+}
+
+func TestFitIrregularRecoversOU(t *testing.T) {
+	const kappa, theta, sigma = 3.0, -0.5, 0.8
+	rng := rand.New(rand.NewSource(34))
+	dts := make([]float64, 3000)
+	times := make([]float64, len(dts)+1)
+	for i := range dts {
+		dts[i] = (0.2 + 1.6*rng.Float64()) / 252
+		times[i+1] = times[i] + dts[i]
+	}
+	x := simulateOU(dts, kappa, theta, sigma, 35)
+	for _, method := range []OUMethod{METHOD_EXACT_MLE, METHOD_AR1_OLS} {
+		res, err := FitIrregular(x, times, method)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if math.Abs(res.Kappa-kappa) > 3*res.KappaSE || math.Abs(res.Theta-theta) > 3*res.ThetaSE ||
+			math.Abs(res.Sigma-sigma) > 3*res.SigmaSE {
+			t.Errorf("%s: %+v", method, res)
+		}
+	}
+	if _, err := FitIrregular(x, append([]float64{1}, times[1:]...), METHOD_EXACT_MLE); err == nil {
+		t.Error("non-increasing times accepted")
+	}
+}
+
+// 滞后 0、含常数项的 ADF 回归与 AR(1) 回归是同一回归，FromADF 应与 METHOD_AR1_OLS 一致
+func TestFromADF(t *testing.T) {
+	const dt = 1.0 / 52
+	dts := make([]float64, 1000)
+	for i := range dts {
+		dts[i] = dt
+	}
+	x := simulateOU(dts, 2, 1, 0.5, 36)
+	adf, err := adfuller.AdfTestWithOptions(x, adfuller.AdfOptions{Trend: adfuller.TREND_C, Tail: adfuller.TAIL_LEFT, MaxLag: 0, AutoLag: adfuller.LAG_MODE_FIXED})
+	if err != nil {
+		t.Fatal(err)
+	}
+	fromADF, err := FromADF(adf, dt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ar1, _ := Fit(x, dt, METHOD_AR1_OLS)
+	if math.Abs(fromADF.Kappa-ar1.Kappa) > 1e-9 || math.Abs(fromADF.Theta-ar1.Theta) > 1e-9 || math.Abs(fromADF.Sigma-ar1.Sigma) > 1e-9 {
+		t.Errorf("FromADF %+v vs ar1 %+v", fromADF, ar1)
+	}
+	if math.Abs(fromADF.KappaSE-ar1.KappaSE) > 1e-4*ar1.KappaSE || !math.IsNaN(fromADF.ThetaSE) {
+		t.Errorf("SE: %v vs %v", fromADF.KappaSE, ar1.KappaSE)
+	}
+
+	// 含时间趋势的回归没有恒定的 OU 均值
+	for _, tr := range []adfuller.Trend{adfuller.TREND_CT, adfuller.TREND_CTT} {
+		trend, err := adfuller.AdfTestWithOptions(x, adfuller.AdfOptions{Trend: tr, Tail: adfuller.TAIL_LEFT, MaxLag: 0, AutoLag: adfuller.LAG_MODE_FIXED})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := FromADF(trend, dt); err == nil {
+			t.Errorf("%s ADF result accepted", tr)
+		}
+	}
+	unknown := adf
+	unknown.Trend = ""
+	if _, err := FromADF(unknown, dt); err == nil {
+		t.Error("unknown trend accepted")
+	}
+	// DF-GLS 回归的是去趋势序列
+	gls, err := adfuller.DfGlsTest(x, "c", 0, adfuller.LAG_MODE_AIC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := FromADF(gls, dt); err == nil {
+		t.Error("DF-GLS result accepted")
+	}
+}
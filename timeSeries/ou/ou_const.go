@@ -0,0 +1,37 @@
+package ou
+
+// OU 参数估计方法
+type OUMethod int
+
+const (
+	METHOD_EXACT_MLE OUMethod = iota // "exact-mle" 精确离散化转移密度极大似然
+	METHOD_AR1_OLS                   // "ar1-ols" AR(1) 回归(ols)映射
+	METHOD_ADF                       // "adf" 由 ADFResult 的 gamma 换算
+	METHOD_ERROR                     // "ERROR"
+)
+
+func (s OUMethod) String() string {
+	switch s {
+	case METHOD_EXACT_MLE:
+		return "exact-mle"
+	case METHOD_AR1_OLS:
+		return "ar1-ols"
+	case METHOD_ADF:
+		return "adf"
+	default:
+		return "ERROR"
+	}
+}
+
+func GetMyOUMethod(s string) OUMethod {
+	switch s {
+	case "exact-mle":
+		return METHOD_EXACT_MLE
+	case "ar1-ols":
+		return METHOD_AR1_OLS
+	case "adf":
+		return METHOD_ADF
+	default:
+		return METHOD_ERROR
+	}
+}
@@ -0,0 +1,271 @@
+// VAR(p) 向量自回归
+//
+//	y_t = c + A_1 y_{t-1} + ... + A_p y_{t-p} + u_t,  E[u_t u_t'] = Σ_u
+//
+// 各方程回归变量相同，逐方程 OLS(ols.MultiRegressionMat) 即为系统 GLS 估计
+// Σ_u 取自由度修正 U'U/(T - Kp - 1)，信息准则使用 ML 估计 U'U/T，与 statsmodels VAR 一致
+package varmodel
+
+import (
+	"fmt"
+	"math"
+	"method/ml/ols"
+	"ofeisInfra/infra/errorx"
+	"ofeisInfra/infra/errorx/errCode"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+type VarModel struct {
+	K         int                    // 变量个数
+	P         int                    // 滞后阶数
+	WithConst bool                   // 是否含常数项
+	Intercept []float64              // 常数项 c
+	Coefs     [][][]float64          // Coefs[i][r][c] 为 A_{i+1} 第 r 个方程对第 c 个变量的系数
+	Sigma     [][]float64            // 残差协方差 Σ_u(自由度修正)
+	Equations []ols.MultiLinearModel // 各方程 OLS 结果，系数顺序 [c, y_{t-1}(K), ..., y_{t-p}(K)]
+	Resid     [][]float64            // 残差 T×K
+	LogLik    float64
+	AIC       float64
+	BIC       float64
+	HQ        float64
+	NObs      int // 有效样本量 T = n - p
+
+	endog [][]float64 // 原始序列 n×K
+}
+
+// 各阶数的信息准则，下标为滞后阶数 0..maxLag
+type LagOrderResult struct {
+	AIC      []float64
+	BIC      []float64
+	HQ       []float64
+	Selected map[InfoCriterion]int // 各准则选出的阶数
+}
+
+// 估计 VAR(p)
+// input: endog 多变量序列(n×K，每行一个观测); p 滞后阶数(>=1); withConst 是否含常数项
+func Fit(endog [][]float64, p int, withConst bool) (*VarModel, error) {
+	if err := validate(endog); err != nil {
+		return nil, err
+	}
+	if p < 1 {
+		return nil, errorx.New(errCode.INVALID_VALUE, "滞后阶数 p 必须 >= 1")
+	}
+	return fitSample(endog, p, 0, withConst)
+}
+
+// 按信息准则选阶后估计
+// 选出 0 阶(序列间无可用的滞后信息)时返回错误，不静默改用 VAR(1)
+func FitAuto(endog [][]float64, maxLag int, withConst bool, ic InfoCriterion) (*VarModel, error) {
+	order, err := SelectOrder(endog, maxLag, withConst)
+	if err != nil {
+		return nil, err
+	}
+	p, ok := order.Selected[ic]
+	if !ok {
+		return nil, errorx.New(errCode.INVALID_VALUE, "未知的信息准则")
+	}
+	if p == 0 {
+		return nil, errorx.New(errCode.INVALID_VALUE, fmt.Sprintf("%s 选出滞后阶数 0, 无需估计 VAR", ic))
+	}
+	return Fit(endog, p, withConst)
+}
+
+// 滞后阶数选择: 所有阶数共用去掉前 maxLag 个观测后的样本，保证信息准则可比
+func SelectOrder(endog [][]float64, maxLag int, withConst bool) (LagOrderResult, error) {
+	if err := validate(endog); err != nil {
+		return LagOrderResult{}, err
+	}
+	if maxLag < 1 {
+		return LagOrderResult{}, errorx.New(errCode.INVALID_VALUE, "maxLag 必须 >= 1")
+	}
+	result := LagOrderResult{
+		AIC:      make([]float64, maxLag+1),
+		BIC:      make([]float64, maxLag+1),
+		HQ:       make([]float64, maxLag+1),
+		Selected: make(map[InfoCriterion]int, 3),
+	}
+	best := map[InfoCriterion]float64{IC_AIC: math.Inf(1), IC_BIC: math.Inf(1), IC_HQ: math.Inf(1)}
+	for p := 0; p <= maxLag; p++ {
+		model, err := fitSample(endog, p, maxLag-p, withConst)
+		if err != nil {
+			return LagOrderResult{}, err
+		}
+		result.AIC[p], result.BIC[p], result.HQ[p] = model.AIC, model.BIC, model.HQ
+		for ic, v := range map[InfoCriterion]float64{IC_AIC: model.AIC, IC_BIC: model.BIC, IC_HQ: model.HQ} {
+			if v < best[ic] {
+				best[ic] = v
+				result.Selected[ic] = p
+			}
+		}
+	}
+	return result, nil
+}
+
+// 在 endog[skip:] 上估计 VAR(p)
+func fitSample(endog [][]float64, p, skip int, withConst bool) (*VarModel, error) {
+	data := endog[skip:]
+	n, K := len(data), len(data[0])
+	T := n - p
+	nDet := 0
+	if withConst {
+		nDet = 1
+	}
+	kEq := nDet + K*p
+	if T <= kEq {
+		return nil, errorx.New(errCode.INVALID_VALUE, fmt.Sprintf("样本量 %d 不足以估计 %d 变量 VAR(%d)", len(endog), K, p))
+	}
+
+	X := mat.NewDense(T, max(kEq, 1), nil)
+	for t := p; t < n; t++ {
+		row := t - p
+		if withConst {
+			X.Set(row, 0, 1)
+		}
+		for i := 1; i <= p; i++ {
+			for j := 0; j < K; j++ {
+				X.Set(row, nDet+(i-1)*K+j, data[t-i][j])
+			}
+		}
+	}
+
+	model := &VarModel{
+		K:         K,
+		P:         p,
+		WithConst: withConst,
+		Intercept: make([]float64, K),
+		Coefs:     make([][][]float64, p),
+		Equations: make([]ols.MultiLinearModel, K),
+		Resid:     make([][]float64, T),
+		NObs:      T,
+		endog:     endog,
+	}
+	for i := range model.Coefs {
+		model.Coefs[i] = newSquare(K)
+	}
+	for t := range model.Resid {
+		model.Resid[t] = make([]float64, K)
+	}
+	for r := 0; r < K; r++ {
+		y := mat.NewVecDense(T, nil)
+		for t := p; t < n; t++ {
+			y.SetVec(t-p, data[t][r])
+		}
+		var resid []float64
+		if kEq == 0 {
+			// VAR(0) 无常数项: 残差即为序列本身
+			resid = y.RawVector().Data
+		} else {
+			eq, err := ols.MultiRegressionMat(X, y)
+			if err != nil {
+				return nil, err
+			}
+			model.Equations[r] = eq
+			resid = eq.Resids
+			if withConst {
+				model.Intercept[r] = eq.Coeffs[0]
+			}
+			for i := 0; i < p; i++ {
+				for j := 0; j < K; j++ {
+					model.Coefs[i][r][j] = eq.Coeffs[nDet+i*K+j]
+				}
+			}
+		}
+		for t := 0; t < T; t++ {
+			model.Resid[t][r] = resid[t]
+		}
+	}
+
+	// 残差协方差与信息准则
+	model.Sigma = newSquare(K)
+	sigmaML := mat.NewSymDense(K, nil)
+	for a := 0; a < K; a++ {
+		for b := a; b < K; b++ {
+			s := 0.0
+			for t := 0; t < T; t++ {
+				s += model.Resid[t][a] * model.Resid[t][b]
+			}
+			model.Sigma[a][b] = s / float64(T-kEq)
+			model.Sigma[b][a] = model.Sigma[a][b]
+			sigmaML.SetSym(a, b, s/float64(T))
+		}
+	}
+	var chol mat.Cholesky
+	if !chol.Factorize(sigmaML) {
+		return nil, errorx.New(errCode.INVALID_VALUE, "残差协方差矩阵非正定, 变量可能共线")
+	}
+	ld := chol.LogDet()
+	Tf := float64(T)
+	free := float64(K * kEq)
+	model.LogLik = -0.5 * Tf * (float64(K)*math.Log(2*math.Pi) + ld + float64(K))
+	model.AIC = ld + 2*free/Tf
+	model.BIC = ld + math.Log(Tf)*free/Tf
+	model.HQ = ld + 2*math.Log(math.Log(Tf))*free/Tf
+	return model, nil
+}
+
+// 伴随矩阵特征值模长全部 < 1 时 VAR 平稳；特征值分解失败或模长为 NaN 时视为不平稳
+func (m *VarModel) IsStable() bool {
+	roots := m.Roots()
+	if roots == nil {
+		return false
+	}
+	for _, v := range roots {
+		if !(v < 1) {
+			return false
+		}
+	}
+	return true
+}
+
+// 伴随矩阵特征值的模长，分解失败时返回 nil
+func (m *VarModel) Roots() []float64 {
+	K, p := m.K, m.P
+	comp := mat.NewDense(K*p, K*p, nil)
+	for i := 0; i < p; i++ {
+		for r := 0; r < K; r++ {
+			for c := 0; c < K; c++ {
+				comp.Set(r, i*K+c, m.Coefs[i][r][c])
+			}
+		}
+	}
+	for i := K; i < K*p; i++ {
+		comp.Set(i, i-K, 1)
+	}
+	var eig mat.Eigen
+	if !eig.Factorize(comp, mat.EigenNone) {
+		return nil
+	}
+	vals := eig.Values(nil)
+	out := make([]float64, len(vals))
+	for i, v := range vals {
+		out[i] = math.Hypot(real(v), imag(v))
+	}
+	return out
+}
+
+func validate(endog [][]float64) error {
+	if len(endog) == 0 || len(endog[0]) == 0 {
+		return errorx.New(errCode.EMPTY_VALUE, "endog 为空")
+	}
+	K := len(endog[0])
+	for _, row := range endog {
+		if len(row) != K {
+			return errorx.New(errCode.INVALID_VALUE, "endog 各行长度不一致")
+		}
+		for _, v := range row {
+			if math.IsNaN(v) || math.IsInf(v, 0) {
+				return errorx.New(errCode.INVALID_VALUE, "序列含 NaN/Inf")
+			}
+		}
+	}
+	return nil
+}
+
+func newSquare(k int) [][]float64 {
+	out := make([][]float64, k)
+	for i := range out {
+		out[i] = make([]float64, k)
+	}
+	return out
+}
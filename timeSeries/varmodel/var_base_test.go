@@ -0,0 +1,42 @@
+package varmodel
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestIsStable(t *testing.T) {
+	stable := &VarModel{K: 2, P: 1, Coefs: [][][]float64{{{0.5, 0.1}, {0.2, 0.3}}}}
+	if !stable.IsStable() {
+		t.Errorf("roots %v reported unstable", stable.Roots())
+	}
+	explosive := &VarModel{K: 2, P: 1, Coefs: [][][]float64{{{1.1, 0}, {0, 0.3}}}}
+	if explosive.IsStable() {
+		t.Errorf("roots %v reported stable", explosive.Roots())
+	}
+	// 系数含 NaN 时特征值分解失败或模长为 NaN，均不能判为平稳
+	broken := &VarModel{K: 2, P: 1, Coefs: [][][]float64{{{math.NaN(), 0}, {0, 0.3}}}}
+	if broken.IsStable() {
+		t.Errorf("roots %v reported stable", broken.Roots())
+	}
+}
+
+// 白噪声序列上 BIC 选出 0 阶，FitAuto 应报错而不是改用 VAR(1)
+func TestFitAutoZeroOrder(t *testing.T) {
+	rng := rand.New(rand.NewSource(38))
+	endog := make([][]float64, 200)
+	for i := range endog {
+		endog[i] = []float64{rng.NormFloat64(), rng.NormFloat64()}
+	}
+	order, err := SelectOrder(endog, 4, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if order.Selected[IC_BIC] != 0 {
+		t.Fatalf("BIC selected %d on white noise", order.Selected[IC_BIC])
+	}
+	if _, err := FitAuto(endog, 4, true, IC_BIC); err == nil {
+		t.Error("zero selected order accepted")
+	}
+}
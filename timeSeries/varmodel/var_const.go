@@ -0,0 +1,68 @@
+package varmodel
+
+// 滞后阶数选择准则
+type InfoCriterion int
+
+const (
+	IC_AIC   InfoCriterion = iota // "aic"
+	IC_BIC                        // "bic"
+	IC_HQ                         // "hq" Hannan-Quinn
+	IC_ERROR                      // "ERROR"
+)
+
+func (s InfoCriterion) String() string {
+	switch s {
+	case IC_AIC:
+		return "aic"
+	case IC_BIC:
+		return "bic"
+	case IC_HQ:
+		return "hq"
+	default:
+		return "ERROR"
+	}
+}
+
+func GetMyInfoCriterion(s string) InfoCriterion {
+	switch s {
+	case "aic":
+		return IC_AIC
+	case "bic":
+		return IC_BIC
+	case "hq":
+		return IC_HQ
+	default:
+		return IC_ERROR
+	}
+}
+
+// 脉冲响应类型
+type IRFType int
+
+const (
+	IRF_ORTHOGONAL  IRFType = iota // "orth" Cholesky 正交化，依赖变量顺序
+	IRF_GENERALIZED                // "generalized" Pesaran-Shin 广义脉冲，与变量顺序无关
+	IRF_ERROR                      // "ERROR"
+)
+
+func (s IRFType) String() string {
+	switch s {
+	case IRF_ORTHOGONAL:
+		return "orth"
+	case IRF_GENERALIZED:
+		return "generalized"
+	default:
+		return "ERROR"
+	}
+}
+
+func GetMyIRFType(s string) IRFType {
+	switch s {
+	case "orth":
+		return IRF_ORTHOGONAL
+	case "generalized":
+		return IRF_GENERALIZED
+	default:
+		return IRF_ERROR
+	}
+}
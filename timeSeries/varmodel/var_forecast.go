@@ -0,0 +1,81 @@
+package varmodel
+
+import (
+	"math"
+	"ofeisInfra/infra/errorx"
+	"ofeisInfra/infra/errorx/errCode"
+
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+type ForecastResult struct {
+	Mean  [][]float64 // Mean[h][k]: h+1 步点预测
+	SE    [][]float64 // 预测标准误
+	Lower [][]float64 // 预测区间下界
+	Upper [][]float64 // 预测区间上界
+	Alpha float64     // 显著性水平，区间置信度为 1-α
+}
+
+// 多步预测
+// 点预测由样本末 p 个观测递推；预测 MSE: Σ_y(h) = Σ_{i<h} Φ_i Σ_u Φ_i'(不含参数估计误差)
+func (m *VarModel) Forecast(steps int, alpha float64) (ForecastResult, error) {
+	if steps <= 0 {
+		return ForecastResult{}, errorx.New(errCode.INVALID_VALUE, "预测步数必须 > 0")
+	}
+	if alpha <= 0 || alpha >= 1 {
+		return ForecastResult{}, errorx.New(errCode.INVALID_VALUE, "alpha 必须在 (0,1) 之间")
+	}
+	K, p := m.K, m.P
+	n := len(m.endog)
+	hist := make([][]float64, 0, p+steps)
+	hist = append(hist, m.endog[n-p:]...)
+
+	result := ForecastResult{
+		Mean:  make([][]float64, steps),
+		SE:    make([][]float64, steps),
+		Lower: make([][]float64, steps),
+		Upper: make([][]float64, steps),
+		Alpha: alpha,
+	}
+	for h := 0; h < steps; h++ {
+		t := len(hist)
+		row := make([]float64, K)
+		for r := 0; r < K; r++ {
+			v := m.Intercept[r]
+			for i := 0; i < p; i++ {
+				for c := 0; c < K; c++ {
+					v += m.Coefs[i][r][c] * hist[t-i-1][c]
+				}
+			}
+			row[r] = v
+		}
+		hist = append(hist, row)
+		result.Mean[h] = row
+	}
+
+	phi := m.MACoefs(steps - 1)
+	zCrit := distuv.UnitNormal.Quantile(1 - alpha/2)
+	mse := make([]float64, K) // 对角元累计
+	for h := 0; h < steps; h++ {
+		// diag(Φ_h Σ_u Φ_h')
+		for r := 0; r < K; r++ {
+			s := 0.0
+			for a := 0; a < K; a++ {
+				for b := 0; b < K; b++ {
+					s += phi[h][r][a] * m.Sigma[a][b] * phi[h][r][b]
+				}
+			}
+			mse[r] += s
+		}
+		result.SE[h] = make([]float64, K)
+		result.Lower[h] = make([]float64, K)
+		result.Upper[h] = make([]float64, K)
+		for r := 0; r < K; r++ {
+			se := math.Sqrt(mse[r])
+			result.SE[h][r] = se
+			result.Lower[h][r] = result.Mean[h][r] - zCrit*se
+			result.Upper[h][r] = result.Mean[h][r] + zCrit*se
+		}
+	}
+	return result, nil
+}
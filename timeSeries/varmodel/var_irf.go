@@ -0,0 +1,284 @@
+// 脉冲响应与预测误差方差分解
+// MA(∞) 系数: Φ_0 = I, Φ_i = Σ_{j=1}^{min(i,p)} Φ_{i-j} A_j
+// 正交化脉冲: Θ_i = Φ_i P，P 为 Σ_u 的下三角 Cholesky 因子
+// 广义脉冲(Pesaran-Shin 1998): GI_i[:, j] = Φ_i Σ_u e_j / √σ_jj
+package varmodel
+
+import (
+	"math"
+	"math/rand"
+	"ofeisInfra/infra/errorx"
+	"ofeisInfra/infra/errorx/errCode"
+	"runtime"
+	"sort"
+	"sync"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+type IRFResult struct {
+	Point [][][]float64 // Point[h][r][s]: 第 s 个冲击在 h 期对第 r 个变量的响应，h = 0..horizon
+	Lower [][][]float64 // bootstrap 下分位
+	Upper [][][]float64 // bootstrap 上分位
+	Alpha float64       // 显著性水平，区间置信度为 1-α
+	Type  IRFType
+	NBoot int // 有效 bootstrap 次数
+}
+
+// MA(∞) 系数 Φ_0..Φ_horizon
+func (m *VarModel) MACoefs(horizon int) [][][]float64 {
+	K := m.K
+	phi := make([][][]float64, horizon+1)
+	phi[0] = newSquare(K)
+	for i := 0; i < K; i++ {
+		phi[0][i][i] = 1
+	}
+	for h := 1; h <= horizon; h++ {
+		phi[h] = newSquare(K)
+		for j := 1; j <= h && j <= m.P; j++ {
+			matMulAdd(phi[h], phi[h-j], m.Coefs[j-1])
+		}
+	}
+	return phi
+}
+
+// 脉冲响应点估计
+// input: horizon 最大期数; kind IRF_ORTHOGONAL 或 IRF_GENERALIZED
+func (m *VarModel) IRF(horizon int, kind IRFType) ([][][]float64, error) {
+	if horizon < 0 {
+		return nil, errorx.New(errCode.INVALID_VALUE, "horizon 不能为负")
+	}
+	phi := m.MACoefs(horizon)
+	K := m.K
+	out := make([][][]float64, horizon+1)
+	switch kind {
+	case IRF_ORTHOGONAL:
+		P, err := choleskyLower(m.Sigma)
+		if err != nil {
+			return nil, err
+		}
+		for h := range phi {
+			out[h] = newSquare(K)
+			matMulAdd(out[h], phi[h], P)
+		}
+	case IRF_GENERALIZED:
+		for h := range phi {
+			out[h] = newSquare(K)
+			matMulAdd(out[h], phi[h], m.Sigma)
+			for s := 0; s < K; s++ {
+				sd := math.Sqrt(m.Sigma[s][s])
+				for r := 0; r < K; r++ {
+					out[h][r][s] /= sd
+				}
+			}
+		}
+	default:
+		return nil, errorx.New(errCode.INVALID_VALUE, "未知的脉冲响应类型")
+	}
+	return out, nil
+}
+
+// 脉冲响应及残差 bootstrap 置信带
+// 以样本前 p 个观测为初值，按估计系数与重抽样(去均值)残差生成新序列并重新估计 VAR(p)，取分位数
+// input: nBoot bootstrap 次数; alpha 显著性水平; rng 随机数生成器，每次重抽样的子种子由其顺序生成，结果与并发调度无关
+func (m *VarModel) IRFBands(horizon int, kind IRFType, nBoot int, alpha float64, rng *rand.Rand) (IRFResult, error) {
+	if nBoot <= 0 {
+		return IRFResult{}, errorx.New(errCode.INVALID_VALUE, "bootstrap 次数必须 > 0")
+	}
+	if alpha <= 0 || alpha >= 1 {
+		return IRFResult{}, errorx.New(errCode.INVALID_VALUE, "alpha 必须在 (0,1) 之间")
+	}
+	if rng == nil {
+		return IRFResult{}, errorx.New(errCode.INVALID_VALUE, "随机数生成器 rng 不能为空")
+	}
+	point, err := m.IRF(horizon, kind)
+	if err != nil {
+		return IRFResult{}, err
+	}
+
+	K, T := m.K, m.NObs
+	resid := make([][]float64, T)
+	for t := range resid {
+		resid[t] = make([]float64, K)
+	}
+	for k := 0; k < K; k++ {
+		mean := 0.0
+		for t := 0; t < T; t++ {
+			mean += m.Resid[t][k]
+		}
+		mean /= float64(T)
+		for t := 0; t < T; t++ {
+			resid[t][k] = m.Resid[t][k] - mean
+		}
+	}
+	seeds := make([]int64, nBoot)
+	for i := range seeds {
+		seeds[i] = rng.Int63()
+	}
+
+	draws := make([][][][]float64, nBoot)
+	tasks := make(chan int, nBoot)
+	wg := sync.WaitGroup{}
+	worker := func() {
+		defer wg.Done()
+		for b := range tasks {
+			r := rand.New(rand.NewSource(seeds[b]))
+			sim := m.simulate(resid, r)
+			fit, err := fitSample(sim, m.P, 0, m.WithConst)
+			if err != nil {
+				continue
+			}
+			irf, err := fit.IRF(horizon, kind)
+			if err != nil {
+				continue
+			}
+			draws[b] = irf
+		}
+	}
+	numWorkers := runtime.NumCPU()
+	wg.Add(numWorkers)
+	for i := 0; i < numWorkers; i++ {
+		go worker()
+	}
+	for b := 0; b < nBoot; b++ {
+		tasks <- b
+	}
+	close(tasks)
+	wg.Wait()
+
+	valid := draws[:0]
+	for _, d := range draws {
+		if d != nil {
+			valid = append(valid, d)
+		}
+	}
+	if len(valid) == 0 {
+		return IRFResult{}, errorx.New(errCode.INVALID_VALUE, "所有 bootstrap 重估计均失败")
+	}
+
+	result := IRFResult{Point: point, Alpha: alpha, Type: kind, NBoot: len(valid)}
+	result.Lower = make([][][]float64, horizon+1)
+	result.Upper = make([][][]float64, horizon+1)
+	buf := make([]float64, len(valid))
+	for h := 0; h <= horizon; h++ {
+		result.Lower[h] = newSquare(K)
+		result.Upper[h] = newSquare(K)
+		for r := 0; r < K; r++ {
+			for s := 0; s < K; s++ {
+				for b, d := range valid {
+					buf[b] = d[h][r][s]
+				}
+				sort.Float64s(buf)
+				result.Lower[h][r][s] = quantile(buf, alpha/2)
+				result.Upper[h][r][s] = quantile(buf, 1-alpha/2)
+			}
+		}
+	}
+	return result, nil
+}
+
+// 预测误差方差分解(基于正交化脉冲)
+// 返回 FEVD[h][r][s]: h+1 步预测误差中第 r 个变量的方差由第 s 个冲击解释的比例，h = 0..horizon-1
+func (m *VarModel) FEVD(horizon int) ([][][]float64, error) {
+	if horizon < 1 {
+		return nil, errorx.New(errCode.INVALID_VALUE, "horizon 必须 >= 1")
+	}
+	theta, err := m.IRF(horizon-1, IRF_ORTHOGONAL)
+	if err != nil {
+		return nil, err
+	}
+	K := m.K
+	cum := newSquare(K)
+	out := make([][][]float64, horizon)
+	for h := 0; h < horizon; h++ {
+		out[h] = newSquare(K)
+		for r := 0; r < K; r++ {
+			total := 0.0
+			for s := 0; s < K; s++ {
+				cum[r][s] += theta[h][r][s] * theta[h][r][s]
+				total += cum[r][s]
+			}
+			for s := 0; s < K; s++ {
+				out[h][r][s] = cum[r][s] / total
+			}
+		}
+	}
+	return out, nil
+}
+
+// 以样本前 p 个观测为初值、重抽样残差生成与原样本等长的序列
+func (m *VarModel) simulate(resid [][]float64, rng *rand.Rand) [][]float64 {
+	K, p := m.K, m.P
+	n := len(m.endog)
+	sim := make([][]float64, n)
+	for t := 0; t < p; t++ {
+		sim[t] = append([]float64(nil), m.endog[t]...)
+	}
+	for t := p; t < n; t++ {
+		e := resid[rng.Intn(len(resid))]
+		row := make([]float64, K)
+		for r := 0; r < K; r++ {
+			v := m.Intercept[r] + e[r]
+			for i := 0; i < p; i++ {
+				for c := 0; c < K; c++ {
+					v += m.Coefs[i][r][c] * sim[t-i-1][c]
+				}
+			}
+			row[r] = v
+		}
+		sim[t] = row
+	}
+	return sim
+}
+
+// dst += a·b
+func matMulAdd(dst, a, b [][]float64) {
+	K := len(dst)
+	for r := 0; r < K; r++ {
+		for c := 0; c < K; c++ {
+			s := 0.0
+			for k := 0; k < K; k++ {
+				s += a[r][k] * b[k][c]
+			}
+			dst[r][c] += s
+		}
+	}
+}
+
+func choleskyLower(sigma [][]float64) ([][]float64, error) {
+	K := len(sigma)
+	S := mat.NewSymDense(K, nil)
+	for a := 0; a < K; a++ {
+		for b := a; b < K; b++ {
+			S.SetSym(a, b, sigma[a][b])
+		}
+	}
+	var chol mat.Cholesky
+	if !chol.Factorize(S) {
+		return nil, errorx.New(errCode.INVALID_VALUE, "残差协方差矩阵非正定")
+	}
+	var L mat.TriDense
+	chol.LTo(&L)
+	out := newSquare(K)
+	for r := 0; r < K; r++ {
+		for c := 0; c <= r; c++ {
+			out[r][c] = L.At(r, c)
+		}
+	}
+	return out, nil
+}
+
+// 线性插值分位数(与 numpy 默认 linear 一致)，x 需已升序
+func quantile(x []float64, q float64) float64 {
+	n := len(x)
+	if n == 1 {
+		return x[0]
+	}
+	pos := q * float64(n-1)
+	lo := int(math.Floor(pos))
+	if lo+1 >= n {
+		return x[n-1]
+	}
+	frac := pos - float64(lo)
+	return x[lo] + frac*(x[lo+1]-x[lo])
+}
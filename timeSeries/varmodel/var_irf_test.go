@@ -0,0 +1,125 @@
+package varmodel
+
+import (
+	"math"
+	"testing"
+)
+
+// VAR(1): A = [[0.5, 0.1], [0.2, 0.3]]，Σ_u = [[1, 0.3], [0.3, 0.5]]，c = [0.1, -0.2]，y_T = [1, 2]
+func knownVar1() *VarModel {
+	return &VarModel{
+		K:         2,
+		P:         1,
+		WithConst: true,
+		Intercept: []float64{0.1, -0.2},
+		Coefs:     [][][]float64{{{0.5, 0.1}, {0.2, 0.3}}},
+		Sigma:     [][]float64{{1, 0.3}, {0.3, 0.5}},
+		endog:     [][]float64{{0, 0}, {0.5, -0.5}, {1, 2}},
+	}
+}
+
+func TestIRFKnownVar(t *testing.T) {
+	m := knownVar1()
+	s := math.Sqrt(0.41)
+	orth, err := m.IRF(10, IRF_ORTHOGONAL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// h=0 为 Σ_u 的下三角 Cholesky 因子，h=1 为 A·P
+	want := [][][]float64{
+		{{1, 0}, {0.3, s}},
+		{{0.53, 0.1 * s}, {0.29, 0.3 * s}},
+	}
+	for h := range want {
+		for r := 0; r < 2; r++ {
+			for c := 0; c < 2; c++ {
+				if math.Abs(orth[h][r][c]-want[h][r][c]) > 1e-12 {
+					t.Errorf("orth h=%d [%d][%d] = %v, want %v", h, r, c, orth[h][r][c], want[h][r][c])
+				}
+			}
+		}
+	}
+	// 平稳 VAR 的脉冲响应衰减
+	for r := 0; r < 2; r++ {
+		for c := 0; c < 2; c++ {
+			if math.Abs(orth[10][r][c]) > 1e-2 {
+				t.Errorf("orth h=10 [%d][%d] = %v", r, c, orth[10][r][c])
+			}
+		}
+	}
+
+	// 广义脉冲 h=0 的第 s 列为 Σ_u e_s / √σ_ss
+	gen, err := m.IRF(0, IRF_GENERALIZED)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantGen := [][]float64{{1, 0.3 / math.Sqrt(0.5)}, {0.3, 0.5 / math.Sqrt(0.5)}}
+	for r := 0; r < 2; r++ {
+		for c := 0; c < 2; c++ {
+			if math.Abs(gen[0][r][c]-wantGen[r][c]) > 1e-12 {
+				t.Errorf("generalized [%d][%d] = %v, want %v", r, c, gen[0][r][c], wantGen[r][c])
+			}
+		}
+	}
+}
+
+func TestFEVDKnownVar(t *testing.T) {
+	m := knownVar1()
+	fevd, err := m.FEVD(10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for h := range fevd {
+		for r := 0; r < 2; r++ {
+			if sum := fevd[h][r][0] + fevd[h][r][1]; math.Abs(sum-1) > 1e-12 {
+				t.Errorf("h=%d row %d sums to %v", h, r, sum)
+			}
+		}
+	}
+	// 一步: 第一个变量完全由第一个冲击解释，第二个变量为 (0.09, 0.41)/0.5
+	want := [][]float64{{1, 0}, {0.18, 0.82}}
+	for r := 0; r < 2; r++ {
+		for c := 0; c < 2; c++ {
+			if math.Abs(fevd[0][r][c]-want[r][c]) > 1e-12 {
+				t.Errorf("h=1 [%d][%d] = %v, want %v", r, c, fevd[0][r][c], want[r][c])
+			}
+		}
+	}
+	// 两步分解的分母即两步预测 MSE 的对角元
+	fc, err := m.Forecast(2, 0.05)
+	if err != nil {
+		t.Fatal(err)
+	}
+	orth, _ := m.IRF(1, IRF_ORTHOGONAL)
+	for r := 0; r < 2; r++ {
+		mse := fc.SE[1][r] * fc.SE[1][r]
+		for c := 0; c < 2; c++ {
+			contrib := orth[0][r][c]*orth[0][r][c] + orth[1][r][c]*orth[1][r][c]
+			if math.Abs(fevd[1][r][c]*mse-contrib) > 1e-12 {
+				t.Errorf("h=2 [%d][%d]: %v·%v vs %v", r, c, fevd[1][r][c], mse, contrib)
+			}
+		}
+	}
+}
+
+// 预测 MSE: Σ_y(1) = Σ_u，Σ_y(2) = Σ_u + AΣ_uA'，对角元为 (1.285, 0.621)
+func TestForecastKnownVar(t *testing.T) {
+	m := knownVar1()
+	fc, err := m.Forecast(2, 0.05)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantMean := [][]float64{{0.8, 0.6}, {0.56, 0.14}}
+	wantSE := [][]float64{{1, math.Sqrt(0.5)}, {math.Sqrt(1.285), math.Sqrt(0.621)}}
+	for h := 0; h < 2; h++ {
+		for r := 0; r < 2; r++ {
+			if math.Abs(fc.Mean[h][r]-wantMean[h][r]) > 1e-12 || math.Abs(fc.SE[h][r]-wantSE[h][r]) > 1e-12 {
+				t.Errorf("h=%d var %d: mean %v se %v, want %v %v", h+1, r, fc.Mean[h][r], fc.SE[h][r], wantMean[h][r], wantSE[h][r])
+			}
+			if half := 1.959963984540054 * wantSE[h][r]; math.Abs(fc.Upper[h][r]-fc.Mean[h][r]-half) > 1e-9 ||
+				math.Abs(fc.Mean[h][r]-fc.Lower[h][r]-half) > 1e-9 {
+				t.Errorf("h=%d var %d: interval [%v, %v]", h+1, r, fc.Lower[h][r], fc.Upper[h][r])
+			}
+		}
+	}
+}